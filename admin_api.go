@@ -3,10 +3,10 @@ package spvwallet
 import (
 	"context"
 	"fmt"
-	"net/url"
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/admin/accesskeys"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/admin/contacts"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/admin/invitations"
@@ -19,9 +19,11 @@ import (
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/admin/xpubs"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/configs"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/errutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/pagination"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/constants"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
 	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
 	"github.com/bitcoin-sv/spv-wallet/models"
 	"github.com/bitcoin-sv/spv-wallet/models/filter"
@@ -37,6 +39,9 @@ import (
 //
 // Methods may return wrapped errors, including models.SPVError or
 // ErrUnrecognizedAPIResponse, depending on the behavior of the SPV Wallet API.
+// Errors caused by a non-2xx response wrap *errors.APIError, carrying the status code,
+// server error code and raw body; classify them with errors.IsNotFound, errors.IsUnauthorized,
+// errors.IsInsufficientFunds and errors.IsRetryable.
 type AdminAPI struct {
 	configsAPI      *configs.API
 	xpubsAPI        *xpubs.API
@@ -49,15 +54,19 @@ type AdminAPI struct {
 	webhooksAPI     *webhooks.API
 	statusAPI       *status.API
 	statsAPI        *stats.API
+	tracer          *tracing.Tracer
 }
 
 // SharedConfig retrieves the shared configuration via the configurations API.
 // The response is unmarshaled into a response.SharedConfig.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) SharedConfig(ctx context.Context) (*response.SharedConfig, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.SharedConfig", constants.AdminSharedConfigAPI)
+	defer span.End()
+
 	res, err := a.configsAPI.SharedConfig(ctx)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminSharedConfigAPI, "retrieve shared configuration", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminSharedConfigAPI, "retrieve shared configuration", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -69,9 +78,12 @@ func (a *AdminAPI) SharedConfig(ctx context.Context) (*response.SharedConfig, er
 // The API response is unmarshaled into a *response.Xpub struct.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (a *AdminAPI) CreateXPub(ctx context.Context, cmd *commands.CreateUserXpub) (*response.Xpub, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.CreateXPub", constants.AdminXPubsAPI)
+	defer span.End()
+
 	res, err := a.xpubsAPI.CreateXPub(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminXPubsAPI, "create XPub", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminXPubsAPI, "create XPub", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -87,14 +99,24 @@ func (a *AdminAPI) CreateXPub(ctx context.Context, cmd *commands.CreateUserXpub)
 // The API response is unmarshaled into a *queries.XPubPage struct.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (a *AdminAPI) XPubs(ctx context.Context, opts ...queries.QueryOption[filter.XpubFilter]) (*queries.XPubPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.XPubs", constants.AdminXPubsAPI)
+	defer span.End()
+
 	res, err := a.xpubsAPI.XPubs(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminXPubsAPI, "retrieve XPubs page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminXPubsAPI, "retrieve XPubs page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllXPubs returns an iterator over all user XPubs, walking through every page
+// returned by AdminAPI.XPubs. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllXPubs(ctx context.Context, opts ...queries.QueryOption[filter.XpubFilter]) queries.Iterator[response.Xpub] {
+	return pagination.Iterate(ctx, a.XPubs, opts...)
+}
+
 // CreateContact creates a new contact record via the Admin Contacts API.
 // It accepts a command containing the necessary parameters to define the contact record,
 // such as the creator's paymail, contact's full name, paymail and any associated metadata.
@@ -105,9 +127,12 @@ func (a *AdminAPI) XPubs(ctx context.Context, opts ...queries.QueryOption[filter
 // If the API request fails or the response cannot be decoded, an error is returned. The error is
 // wrapped with additional context to assist in troubleshooting.
 func (a *AdminAPI) CreateContact(ctx context.Context, cmd *commands.CreateContact) (*response.Contact, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.CreateContact", constants.AdminContactsAPI)
+	defer span.End()
+
 	res, err := a.contactsAPI.CreateContact(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "create user contacts page", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "create user contacts page", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -120,14 +145,24 @@ func (a *AdminAPI) CreateContact(ctx context.Context, cmd *commands.CreateContac
 // provided using query options. The result is unmarshaled into a *queries.ContactsPage.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (a *AdminAPI) Contacts(ctx context.Context, opts ...queries.QueryOption[filter.AdminContactFilter]) (*queries.ContactsPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Contacts", constants.AdminContactsAPI)
+	defer span.End()
+
 	res, err := a.contactsAPI.Contacts(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "retrieve user contacts page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "retrieve user contacts page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllContacts returns an iterator over all user contacts, walking through every page
+// returned by AdminAPI.Contacts. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllContacts(ctx context.Context, opts ...queries.QueryOption[filter.AdminContactFilter]) queries.Iterator[response.Contact] {
+	return pagination.Iterate(ctx, a.Contacts, opts...)
+}
+
 // ContactUpdate updates a user's contact information through the admin contacts API.
 //
 // This method uses the `UpdateContact` command to specify the details of the contact to update.
@@ -135,10 +170,13 @@ func (a *AdminAPI) Contacts(ctx context.Context, opts ...queries.QueryOption[fil
 // and returns the updated contact. If the API request fails or the response cannot be decoded,
 // an error is returned.
 func (a *AdminAPI) ContactUpdate(ctx context.Context, cmd *commands.UpdateContact) (*response.Contact, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.ContactUpdate", constants.AdminContactsAPI)
+	defer span.End()
+
 	res, err := a.contactsAPI.UpdateContact(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("update contact with ID: %s", cmd.ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatPutErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatPutErr())
 	}
 
 	return res, nil
@@ -148,10 +186,13 @@ func (a *AdminAPI) ContactUpdate(ctx context.Context, cmd *commands.UpdateContac
 // Returns an error if the API request fails or the response cannot be decoded.
 // A nil error indicates the deleting contact was successful.
 func (a *AdminAPI) DeleteContact(ctx context.Context, ID string) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.DeleteContact", constants.AdminContactsAPI)
+	defer span.End()
+
 	err := a.contactsAPI.DeleteContact(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("delete contact with ID: %s", ID)
-		return errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatDeleteErr())
 	}
 
 	return nil
@@ -161,10 +202,13 @@ func (a *AdminAPI) DeleteContact(ctx context.Context, ID string) error {
 // Returns an error if the API fails to confirm both contacts.
 // A nil error indicates the confirmation was successful.
 func (a *AdminAPI) ConfirmContacts(ctx context.Context, cmd *commands.ConfirmContacts) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.ConfirmContacts", constants.AdminContactsAPI)
+	defer span.End()
+
 	err := a.contactsAPI.ConfirmContacts(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("confirm contacts: %s & %s", cmd.PaymailA, cmd.PaymailB)
-		return errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatPostErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, msg, err).FormatPostErr())
 	}
 
 	return nil
@@ -173,10 +217,13 @@ func (a *AdminAPI) ConfirmContacts(ctx context.Context, cmd *commands.ConfirmCon
 // AcceptInvitation processes and accepts a user contact invitation using the given ID via the admin invitations API.
 // Returns an error if the API request fails. A nil error indicates the invitation was successfully accepted.
 func (a *AdminAPI) AcceptInvitation(ctx context.Context, ID string) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.AcceptInvitation", constants.AdminInvitationsAPI)
+	defer span.End()
+
 	err := a.invitationsAPI.AcceptInvitation(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("accept invitation with ID: %s", ID)
-		return errutil.NewHTTPErrorFormatter(constants.AdminInvitationsAPI, msg, err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminInvitationsAPI, msg, err).FormatDeleteErr())
 	}
 
 	return nil
@@ -185,10 +232,13 @@ func (a *AdminAPI) AcceptInvitation(ctx context.Context, ID string) error {
 // RejectInvitation processes and rejects a user contact invitation using the given ID via the admin invitations API.
 // Returns an error if the API request fails. A nil error indicates the invitation was successfully rejected.
 func (a *AdminAPI) RejectInvitation(ctx context.Context, ID string) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.RejectInvitation", constants.AdminInvitationsAPI)
+	defer span.End()
+
 	err := a.invitationsAPI.RejectInvitation(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("delete invitation with ID: %s", ID)
-		return errutil.NewHTTPErrorFormatter(constants.AdminInvitationsAPI, msg, err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminInvitationsAPI, msg, err).FormatDeleteErr())
 	}
 
 	return nil
@@ -202,22 +252,35 @@ func (a *AdminAPI) RejectInvitation(ctx context.Context, ID string) error {
 // The response is expected to be to unmarshal into a *queries.TransactionPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) Transactions(ctx context.Context, opts ...queries.QueryOption[filter.AdminTransactionFilter]) (*queries.TransactionPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Transactions", constants.AdminTransactionsAPI)
+	defer span.End()
+
 	res, err := a.transactionsAPI.Transactions(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminTransactionsAPI, "retrieve transactions page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminTransactionsAPI, "retrieve transactions page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllTransactions returns an iterator over all transactions, walking through every page
+// returned by AdminAPI.Transactions. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllTransactions(ctx context.Context, opts ...queries.QueryOption[filter.AdminTransactionFilter]) queries.Iterator[response.Transaction] {
+	return pagination.Iterate(ctx, a.Transactions, opts...)
+}
+
 // Transaction retrieves a specific transaction by its ID via the Admin transactions API.
 // The response is expected to be unmarshaled into a *response.Transaction struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) Transaction(ctx context.Context, ID string) (*response.Transaction, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Transaction", constants.AdminTransactionsAPI)
+	defer span.End()
+
 	res, err := a.transactionsAPI.Transaction(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("retrieve a transaction with ID: %s", ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminTransactionsAPI, msg, err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminTransactionsAPI, msg, err).FormatGetErr())
 	}
 
 	return res, nil
@@ -231,24 +294,37 @@ func (a *AdminAPI) Transaction(ctx context.Context, ID string) (*response.Transa
 // The response is expected to unmarshal into a *queries.AccessKeyPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) AccessKeys(ctx context.Context, opts ...queries.QueryOption[filter.AdminAccessKeyFilter]) (*queries.AccessKeyPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.AccessKeys", constants.AdminAccessKeyAPI)
+	defer span.End()
+
 	res, err := a.accessKeyAPI.AccessKeys(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, "retrieve access keys page ", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, "retrieve access keys page ", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllAccessKeys returns an iterator over all access keys, walking through every page
+// returned by AdminAPI.AccessKeys. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllAccessKeys(ctx context.Context, opts ...queries.QueryOption[filter.AdminAccessKeyFilter]) queries.Iterator[response.AccessKey] {
+	return pagination.Iterate(ctx, a.AccessKeys, opts...)
+}
+
 // SubscribeWebhook registers a webhook subscription using the Admin Webhooks API.
 // The provided command contains the parameters required to define the webhook subscription.
 // Accepts context for controlling cancellation and timeout for the API request.
 // The CreateWebhookSubscription command includes the webhook URL and authentication details.
 // Returns a formatted error if the API request fails. A nil error indicates the webhook subscription was successful.
 func (a *AdminAPI) SubscribeWebhook(ctx context.Context, cmd *commands.CreateWebhookSubscription) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.SubscribeWebhook", constants.AdminWebhooksAPI)
+	defer span.End()
+
 	err := a.webhooksAPI.SubscribeWebhook(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("subscribe webhook URL address: %s", cmd.URL)
-		return errutil.NewHTTPErrorFormatter(constants.AdminWebhooksAPI, msg, err).FormatPostErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminWebhooksAPI, msg, err).FormatPostErr())
 	}
 
 	return nil
@@ -259,15 +335,50 @@ func (a *AdminAPI) SubscribeWebhook(ctx context.Context, cmd *commands.CreateWeb
 // CancelWebhookSubscription command specifies the webhook URL to be unsubscribed.
 // Returns a formatted error if the API request fails. A nil error indicates the webhook subscription was successfully deleted.
 func (a *AdminAPI) UnsubscribeWebhook(ctx context.Context, cmd *commands.CancelWebhookSubscription) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.UnsubscribeWebhook", constants.AdminWebhooksAPI)
+	defer span.End()
+
 	err := a.webhooksAPI.UnsubscribeWebhook(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("unsubscribe webhook URL address: %s", cmd.URL)
-		return errutil.NewHTTPErrorFormatter(constants.AdminWebhooksAPI, msg, err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminWebhooksAPI, msg, err).FormatDeleteErr())
 	}
 
 	return nil
 }
 
+// WebhookSubscriptions retrieves the webhooks currently subscribed to the SPV Wallet using the Admin Webhooks API.
+// Token headers and values of the subscriptions are not exposed by the API.
+// Returns a formatted error if the API request fails or the response cannot be decoded.
+func (a *AdminAPI) WebhookSubscriptions(ctx context.Context) ([]*models.Webhook, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.WebhookSubscriptions", constants.AdminWebhooksAPI)
+	defer span.End()
+
+	res, err := a.webhooksAPI.Subscriptions(ctx)
+	if err != nil {
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminWebhooksAPI, "retrieve webhook subscriptions", err).FormatGetErr())
+	}
+
+	return res, nil
+}
+
+// AdminSubscribeWebhook registers a webhook subscription for the given URL, authenticated
+// with the token header and value. Together with AdminUnsubscribeWebhook it makes AdminAPI
+// a notifications.WebhookSubscriber, so it can be passed directly to notifications.NewWebhook.
+func (a *AdminAPI) AdminSubscribeWebhook(ctx context.Context, webhookURL, tokenHeader, tokenValue string) error {
+	return a.SubscribeWebhook(ctx, &commands.CreateWebhookSubscription{
+		URL:         webhookURL,
+		TokenHeader: tokenHeader,
+		TokenValue:  tokenValue,
+	})
+}
+
+// AdminUnsubscribeWebhook removes the webhook subscription for the given URL.
+// See AdminSubscribeWebhook.
+func (a *AdminAPI) AdminUnsubscribeWebhook(ctx context.Context, webhookURL string) error {
+	return a.UnsubscribeWebhook(ctx, &commands.CancelWebhookSubscription{URL: webhookURL})
+}
+
 // UTXOs fetches a paginated list of UTXOs via the Admin XPubs API.
 // The response includes UTXOs along with pagination details, such as page number,
 // sort order, and sorting field.
@@ -276,14 +387,24 @@ func (a *AdminAPI) UnsubscribeWebhook(ctx context.Context, cmd *commands.CancelW
 // The response is unmarshaled into a *queries.UtxosPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) UTXOs(ctx context.Context, opts ...queries.QueryOption[filter.AdminUtxoFilter]) (*queries.UtxosPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.UTXOs", constants.AdminUtxosAPI)
+	defer span.End()
+
 	res, err := a.utxosAPI.UTXOs(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminUtxosAPI, "retrieve utxos page ", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminUtxosAPI, "retrieve utxos page ", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllUTXOs returns an iterator over all UTXOs, walking through every page
+// returned by AdminAPI.UTXOs. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllUTXOs(ctx context.Context, opts ...queries.QueryOption[filter.AdminUtxoFilter]) queries.Iterator[response.Utxo] {
+	return pagination.Iterate(ctx, a.UTXOs, opts...)
+}
+
 // Paymails retrieves a paginated list of paymail addresses via the Admin Paymails API.
 // The response includes user paymails along with pagination metadata, such as
 // the current page number, sort order, and the field used for sorting (sortBy).
@@ -294,22 +415,35 @@ func (a *AdminAPI) UTXOs(ctx context.Context, opts ...queries.QueryOption[filter
 // The API response is unmarshaled into a *queries.PaymailsPage struct.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (a *AdminAPI) Paymails(ctx context.Context, opts ...queries.QueryOption[filter.AdminPaymailFilter]) (*queries.PaymailsPage, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Paymails", constants.AdminPaymailAPI)
+	defer span.End()
+
 	res, err := a.paymailsAPI.Paymails(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, "retrieve paymail addresses page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, "retrieve paymail addresses page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllPaymails returns an iterator over all paymail addresses, walking through every page
+// returned by AdminAPI.Paymails. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (a *AdminAPI) AllPaymails(ctx context.Context, opts ...queries.QueryOption[filter.AdminPaymailFilter]) queries.Iterator[response.PaymailAddress] {
+	return pagination.Iterate(ctx, a.Paymails, opts...)
+}
+
 // Paymail retrieves the paymail address associated with the specified ID via the Admin Paymails API.
 // The response is expected to be unmarshaled into a *response.PaymailAddress struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (a *AdminAPI) Paymail(ctx context.Context, ID string) (*response.PaymailAddress, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Paymail", constants.AdminPaymailAPI)
+	defer span.End()
+
 	res, err := a.paymailsAPI.Paymail(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("retrieve paymail address with ID: %s", ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, msg, err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, msg, err).FormatGetErr())
 	}
 
 	return res, nil
@@ -321,9 +455,12 @@ func (a *AdminAPI) Paymail(ctx context.Context, ID string) (*response.PaymailAdd
 // The API response is unmarshaled into a *response.Xpub PaymailAddress.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (a *AdminAPI) CreatePaymail(ctx context.Context, cmd *commands.CreatePaymail) (*response.PaymailAddress, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.CreatePaymail", constants.AdminPaymailAPI)
+	defer span.End()
+
 	res, err := a.paymailsAPI.CreatePaymail(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, "create paymail address", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, "create paymail address", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -333,10 +470,13 @@ func (a *AdminAPI) CreatePaymail(ctx context.Context, cmd *commands.CreatePaymai
 // It returns an error if the API request fails. A nil error indicates that the paymail
 // was successfully deleted.
 func (a *AdminAPI) DeletePaymail(ctx context.Context, address string) error {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.DeletePaymail", constants.AdminPaymailAPI)
+	defer span.End()
+
 	err := a.paymailsAPI.DeletePaymail(ctx, address)
 	if err != nil {
 		msg := fmt.Sprintf("remove paymail address: %s", address)
-		return errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, msg, err).FormatGetErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminPaymailAPI, msg, err).FormatGetErr())
 	}
 
 	return nil
@@ -348,9 +488,12 @@ func (a *AdminAPI) DeletePaymail(ctx context.Context, address string) error {
 // A nil error with a valid response indicates the request was successful.
 // Returns a formatted error if the API request fails.
 func (a *AdminAPI) Stats(ctx context.Context) (*models.AdminStats, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Stats", constants.AdminStatsAPI)
+	defer span.End()
+
 	res, err := a.statsAPI.Stats(ctx)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminStatsAPI, "retrieve stats", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminStatsAPI, "retrieve stats", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -361,9 +504,12 @@ func (a *AdminAPI) Stats(ctx context.Context) (*models.AdminStats, error) {
 // Otherwise, it returns false with a nil error, indicating that the key used does not match
 // the SPV Wallet API admin key. A non-nil error is returned if the API request fails.
 func (a *AdminAPI) Status(ctx context.Context) (bool, error) {
+	ctx, span := a.tracer.Start(ctx, "AdminAPI.Status", constants.AdminStatusAPI)
+	defer span.End()
+
 	ok, err := a.statusAPI.Status(ctx)
 	if err != nil {
-		return false, errutil.NewHTTPErrorFormatter(constants.AdminStatusAPI, "retrieve information about the used key type: %w", err).FormatGetErr()
+		return false, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminStatusAPI, "retrieve information about the used key type: %w", err).FormatGetErr())
 	}
 
 	return ok, nil
@@ -398,15 +544,37 @@ func NewAdminAPIWithXPub(cfg config.Config, xPub string) (*AdminAPI, error) {
 	return initAdminAPI(cfg, authenticator)
 }
 
+// NewAdminAPIFromKeystore initializes a new AdminAPI instance using the xPriv stored under the name
+// in the encrypted keystore file at the path, decrypted with the password (see walletkeys.Keystore),
+// or derived from the mnemonic phrase of the entry with an empty BIP39 passphrase when it has no xPriv.
+// If the keystore can't be opened or the entry has neither an xPriv nor a mnemonic phrase, an appropriate error is returned.
+//
+// Note: Requests made with this instance will be securely signed.
+func NewAdminAPIFromKeystore(cfg config.Config, path, name, password string) (*AdminAPI, error) {
+	entry, err := keystoreEntry(path, name, password)
+	if err != nil {
+		return nil, err
+	}
+	xPriv, err := keystoreEntryXPriv(entry)
+	if err != nil {
+		return nil, err
+	}
+	if xPriv == "" {
+		return nil, fmt.Errorf("%w: %q has neither an xPriv nor a mnemonic phrase", goclienterr.ErrKeystoreEntryNoCredentials, name)
+	}
+
+	return NewAdminAPIWithXPriv(cfg, xPriv)
+}
+
 func initAdminAPI(cfg config.Config, auth authenticator) (*AdminAPI, error) {
-	url, err := url.Parse(cfg.Addr)
+	url, err := cfg.BaseURL()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse addr to url.URL: %w", err)
 	}
 
-	httpClient := restyutil.NewHTTPClient(cfg, auth)
-	if httpClient == nil {
-		return nil, fmt.Errorf("failed to initialize HTTP client - nil value")
+	httpClient, err := restyutil.NewHTTPClient(cfg, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize HTTP client: %w", err)
 	}
 
 	return &AdminAPI{
@@ -421,5 +589,6 @@ func initAdminAPI(cfg config.Config, auth authenticator) (*AdminAPI, error) {
 		invitationsAPI:  invitations.NewAPI(url, httpClient),
 		statusAPI:       status.NewAPI(url, httpClient),
 		statsAPI:        stats.NewAPI(url, httpClient),
+		tracer:          tracing.NewTracer(cfg.TracerProvider),
 	}, nil
 }
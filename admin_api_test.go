@@ -0,0 +1,91 @@
+package spvwallet_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	spvwallet "github.com/bitcoin-sv/spv-wallet-go-client"
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAdminAPI_InvalidTransport(t *testing.T) {
+	// given:
+	cfg := config.Config{
+		Addr:      "unix:///var/run/spv-wallet.sock",
+		Transport: config.RoundTripFunc(http.DefaultTransport.RoundTrip),
+	}
+
+	// when:
+	wallet, err := spvwallet.NewAdminAPIWithXPub(cfg, testutils.UserXPub)
+
+	// then:
+	require.ErrorIs(t, err, errors.ErrConfigValidationInvalidTransport)
+	require.Nil(t, wallet)
+}
+
+func TestNewAdminAPIFromKeystore(t *testing.T) {
+	mnemonicXPriv, err := walletkeys.XPrivFromMnemonic(keystoreMnemonic)
+	require.NoError(t, err)
+	mnemonicXPub, err := walletkeys.XPubFromXPriv(mnemonicXPriv.String())
+	require.NoError(t, err)
+
+	path := testutils.GivenKeystore(t, map[string]walletkeys.KeystoreEntry{
+		"admin":      {XPriv: testutils.UserXPriv},
+		"mnemonic":   {Mnemonic: keystoreMnemonic},
+		"access-key": {AccessKey: testutils.UserPrivAccessKey},
+	})
+
+	tests := map[string]struct {
+		name         string
+		expectedXPub string
+		expectedErr  error
+	}{
+		"Entry with xPriv": {
+			name:         "admin",
+			expectedXPub: testutils.UserXPub,
+		},
+		"Entry with mnemonic": {
+			name:         "mnemonic",
+			expectedXPub: mnemonicXPub,
+		},
+		"Entry without xPriv": {
+			name:        "access-key",
+			expectedErr: errors.ErrKeystoreEntryNoCredentials,
+		},
+	}
+
+	url := testutils.FullAPIURL(t, "/api/v1/admin/users")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			transport := httpmock.NewMockTransport()
+			var header http.Header
+			transport.RegisterResponder(http.MethodGet, url, func(req *http.Request) (*http.Response, error) {
+				header = req.Header
+				return testutils.NewStringResponderStatusOK("{}")(req)
+			})
+			cfg := config.Config{Addr: testutils.TestAPIAddr, Transport: transport}
+
+			// when:
+			wallet, err := spvwallet.NewAdminAPIFromKeystore(cfg, path, tc.name, testutils.KeystorePassword)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.Nil(t, wallet)
+				return
+			}
+			_, err = wallet.XPubs(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedXPub, header.Get(models.AuthHeader))
+			require.NotEmpty(t, header.Get(models.AuthSignature))
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+func adminCommand() *command {
+	return &command{
+		name:    "admin",
+		summary: "Run admin operations; requires the admin credentials",
+		subcommands: []*command{
+			adminXPubsCommand(),
+			adminPaymailsCommand(),
+			adminWebhooksCommand(),
+			{
+				name:    "stats",
+				summary: "Show the SPV Wallet statistics",
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						stats, err := api.Stats(ctx)
+						if err != nil {
+							return err
+						}
+
+						return c.printDetails(stats, []field{
+							{"Balance", fmt.Sprint(stats.Balance)},
+							{"xPubs", fmt.Sprint(stats.XPubs)},
+							{"Paymail addresses", fmt.Sprint(stats.PaymailAddresses)},
+							{"Transactions", fmt.Sprint(stats.Transactions)},
+							{"UTXOs", fmt.Sprint(stats.Utxos)},
+							{"Destinations", fmt.Sprint(stats.Destinations)},
+						})
+					}
+				},
+			},
+			{
+				name:    "status",
+				summary: "Check whether the admin credentials are accepted by the SPV Wallet",
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						ok, err := api.Status(ctx)
+						if err != nil {
+							return err
+						}
+
+						status := struct {
+							Authorized bool `json:"authorized"`
+						}{ok}
+						return c.printDetails(status, []field{{"Authorized", fmt.Sprint(status.Authorized)}})
+					}
+				},
+			},
+		},
+	}
+}
+
+func adminXPubsCommand() *command {
+	return &command{
+		name:    "xpubs",
+		summary: "Register and list the xPubs of the users",
+		subcommands: []*command{
+			{
+				name:    "create",
+				args:    "[-metadata <key=value> ...] <xpub>",
+				summary: "Register the xPub of a new user",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					metadata := metadataFlag{}
+					fs.Var(metadata, "metadata", "xPub metadata `key=value`, repeatable")
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						xPub, err := api.CreateXPub(ctx, &commands.CreateUserXpub{XPub: args[0], Metadata: metadata.metadata()})
+						if err != nil {
+							return err
+						}
+
+						return c.printXPubs([]*response.Xpub{xPub})
+					}
+				},
+			},
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the registered xPubs",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						xPubs, err := fetchList(ctx, list, api.XPubs, api.AllXPubs)
+						if err != nil {
+							return err
+						}
+
+						return c.printXPubs(xPubs)
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printXPubs(xPubs []*response.Xpub) error {
+	rows := make([][]string, 0, len(xPubs))
+	for _, xPub := range xPubs {
+		rows = append(rows, []string{xPub.ID, formatUint(xPub.CurrentBalance), formatTime(xPub.CreatedAt)})
+	}
+	return c.printTable(xPubs, []string{"ID", "BALANCE", "CREATED AT"}, rows)
+}
+
+func adminPaymailsCommand() *command {
+	return &command{
+		name:    "paymails",
+		summary: "Manage the paymail addresses of the users",
+		subcommands: []*command{
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the paymail addresses",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						paymails, err := fetchList(ctx, list, api.Paymails, api.AllPaymails)
+						if err != nil {
+							return err
+						}
+
+						return c.printPaymails(paymails)
+					}
+				},
+			},
+			{
+				name:    "get",
+				args:    "<id>",
+				summary: "Show the paymail address with the ID",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						paymail, err := api.Paymail(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return c.printPaymails([]*response.PaymailAddress{paymail})
+					}
+				},
+			},
+			{
+				name:    "create",
+				args:    "-xpub <xpub> [-name <public name>] [-avatar <url>] [-metadata <key=value> ...] <address>",
+				summary: "Create a paymail address for the user with the xPub",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					var (
+						cmd      commands.CreatePaymail
+						metadata = metadataFlag{}
+					)
+					fs.StringVar(&cmd.Key, "xpub", "", "`xpub` of the paymail owner")
+					fs.StringVar(&cmd.PublicName, "name", "", "public `name` of the paymail")
+					fs.StringVar(&cmd.Avatar, "avatar", "", "`url` of the paymail avatar")
+					fs.Var(metadata, "metadata", "paymail metadata `key=value`, repeatable")
+					return func(ctx context.Context, c *cli, args []string) error {
+						if cmd.Key == "" {
+							return c.usageError(fs.Usage, "%s: -xpub is required", fs.Name())
+						}
+						cmd.Address = args[0]
+						cmd.Metadata = metadata.metadata()
+
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						paymail, err := api.CreatePaymail(ctx, &cmd)
+						if err != nil {
+							return err
+						}
+
+						return c.printPaymails([]*response.PaymailAddress{paymail})
+					}
+				},
+			},
+			{
+				name:    "delete",
+				args:    "<address>",
+				summary: "Delete the paymail address",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						if err := api.DeletePaymail(ctx, args[0]); err != nil {
+							return err
+						}
+
+						c.printDone("Paymail %s deleted", args[0])
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printPaymails(paymails []*response.PaymailAddress) error {
+	rows := make([][]string, 0, len(paymails))
+	for _, paymail := range paymails {
+		rows = append(rows, []string{paymail.ID, paymail.Address, paymail.PublicName, paymail.XpubID, formatTime(paymail.CreatedAt)})
+	}
+	return c.printTable(paymails, []string{"ID", "ADDRESS", "PUBLIC NAME", "XPUB ID", "CREATED AT"}, rows)
+}
+
+func adminWebhooksCommand() *command {
+	return &command{
+		name:    "webhooks",
+		summary: "Manage the webhook subscriptions",
+		subcommands: []*command{
+			{
+				name:    "list",
+				summary: "List the webhook subscriptions",
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						webhooks, err := api.WebhookSubscriptions(ctx)
+						if err != nil {
+							return err
+						}
+
+						return c.printWebhooks(webhooks)
+					}
+				},
+			},
+			{
+				name:    "subscribe",
+				args:    "[-token-header <header> -token-value <token>] <url>",
+				summary: "Subscribe the URL to the SPV Wallet events",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					var cmd commands.CreateWebhookSubscription
+					fs.StringVar(&cmd.TokenHeader, "token-header", "", "`header` authenticating the event deliveries")
+					fs.StringVar(&cmd.TokenValue, "token-value", "", "`token` sent in the token header")
+					return func(ctx context.Context, c *cli, args []string) error {
+						cmd.URL = args[0]
+
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						if err := api.SubscribeWebhook(ctx, &cmd); err != nil {
+							return err
+						}
+
+						c.printDone("Webhook %s subscribed", cmd.URL)
+						return nil
+					}
+				},
+			},
+			{
+				name:    "unsubscribe",
+				args:    "<url>",
+				summary: "Cancel the webhook subscription of the URL",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.adminAPI()
+						if err != nil {
+							return err
+						}
+
+						if err := api.UnsubscribeWebhook(ctx, &commands.CancelWebhookSubscription{URL: args[0]}); err != nil {
+							return err
+						}
+
+						c.printDone("Webhook %s unsubscribed", args[0])
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printWebhooks(webhooks []*models.Webhook) error {
+	rows := make([][]string, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		rows = append(rows, []string{webhook.URL, fmt.Sprint(webhook.Banned)})
+	}
+	return c.printTable(webhooks, []string{"URL", "BANNED"}, rows)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	wallet "github.com/bitcoin-sv/spv-wallet-go-client"
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+)
+
+const (
+	envPrefix          = "SPVWALLET"
+	envXPriv           = envPrefix + "_XPRIV"
+	envXPub            = envPrefix + "_XPUB"
+	envAccessKey       = envPrefix + "_ACCESS_KEY"
+	envAdminXPriv      = envPrefix + "_ADMIN_XPRIV"
+	envAdminXPub       = envPrefix + "_ADMIN_XPUB"
+	envKeystore        = envPrefix + "_KEYSTORE"
+	envKeystorePass    = envPrefix + "_KEYSTORE_PASSWORD"
+	envNewKeystorePass = envPrefix + "_NEW_KEYSTORE_PASSWORD"
+	envKey             = envPrefix + "_KEY"
+	envAdminKey        = envPrefix + "_ADMIN_KEY"
+	envPassphrase      = envPrefix + "_MNEMONIC_PASSPHRASE"
+	outputTable        = "table"
+	outputJSON         = "json"
+	exitCodeFailure    = 1
+	exitCodeWrongArgs  = 2
+)
+
+var (
+	// errUsage is returned for invalid command-line arguments, after the usage has been printed.
+	errUsage = errors.New("invalid usage")
+
+	errMissingUserCredentials  = fmt.Errorf("missing user credentials: set %s, %s, %s or %s", envKey, envXPriv, envAccessKey, envXPub)
+	errMissingAdminCredentials = fmt.Errorf("missing admin credentials: set %s, %s or %s", envAdminKey, envAdminXPriv, envAdminXPub)
+)
+
+// cli holds the global flags and the standard streams shared by the commands.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	lines  *bufio.Reader // Lazily created reader of stdin lines.
+
+	configFile string
+	addr       string
+	timeout    time.Duration
+	output     string
+
+	xPriv      string
+	xPub       string
+	accessKey  string
+	adminXPriv string
+	adminXPub  string
+
+	keystorePath string
+	key          string // Name of the keystore entry with the user credentials.
+	adminKey     string // Name of the keystore entry with the admin xPriv.
+}
+
+// run executes the command given by the arguments and returns the process exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	err := c.execute(ctx, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return exitCodeWrongArgs
+	default:
+		fmt.Fprintf(stderr, "spv-wallet: %v\n", err)
+		return exitCodeFailure
+	}
+}
+
+func (c *cli) execute(ctx context.Context, args []string) error {
+	root := rootCommand()
+	fs := flag.NewFlagSet("spv-wallet", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.configFile, "config", "", "load the connection settings from a JSON or YAML `file` instead of the environment")
+	fs.StringVar(&c.addr, "addr", "", "`address` of the SPV Wallet API, overriding the loaded one")
+	fs.DurationVar(&c.timeout, "timeout", 0, "HTTP requests `timeout`, overriding the loaded one")
+	fs.StringVar(&c.output, "output", outputTable, "output `format`: table or json")
+	fs.StringVar(&c.xPriv, "xpriv", "", "user xPriv, prefer "+envXPriv)
+	fs.StringVar(&c.xPub, "xpub", "", "user xPub, prefer "+envXPub)
+	fs.StringVar(&c.accessKey, "access-key", "", "user access key, prefer "+envAccessKey)
+	fs.StringVar(&c.adminXPriv, "admin-xpriv", "", "admin xPriv, prefer "+envAdminXPriv)
+	fs.StringVar(&c.adminXPub, "admin-xpub", "", "admin xPub, prefer "+envAdminXPub)
+	fs.StringVar(&c.keystorePath, "keystore", "", "`path` of the encrypted keystore, or "+envKeystore+"; its password is read from "+envKeystorePass+" or prompted")
+	fs.StringVar(&c.key, "key", "", "`name` of the keystore entry with the user xPriv or access key, or "+envKey)
+	fs.StringVar(&c.adminKey, "admin-key", "", "`name` of the keystore entry with the admin xPriv, or "+envAdminKey)
+	usage := func(w io.Writer) {
+		root.printUsage(w, "spv-wallet [global flags]")
+		fmt.Fprintln(w, "\nGlobal flags:")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+	fs.Usage = func() { usage(c.stderr) }
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	if c.output != outputTable && c.output != outputJSON {
+		return c.usageError(fs.Usage, "unsupported output format %q", c.output)
+	}
+	c.xPriv = flagOrEnv(c.xPriv, envXPriv)
+	c.xPub = flagOrEnv(c.xPub, envXPub)
+	c.accessKey = flagOrEnv(c.accessKey, envAccessKey)
+	c.adminXPriv = flagOrEnv(c.adminXPriv, envAdminXPriv)
+	c.adminXPub = flagOrEnv(c.adminXPub, envAdminXPub)
+	c.keystorePath = flagOrEnv(c.keystorePath, envKeystore)
+	c.key = flagOrEnv(c.key, envKey)
+	c.adminKey = flagOrEnv(c.adminKey, envAdminKey)
+
+	switch {
+	case fs.NArg() == 0:
+		fs.Usage()
+		return errUsage
+	case fs.Arg(0) == "help":
+		usage(c.stdout)
+		return nil
+	}
+
+	return root.execute(ctx, c, "spv-wallet", fs.Args())
+}
+
+// config loads the configuration from the -config file or the environment,
+// applying the -addr and -timeout flags on top of it.
+func (c *cli) config() (config.Config, error) {
+	var opts []config.Option
+	if c.addr != "" {
+		opts = append(opts, config.WithAddr(c.addr))
+	}
+	if c.timeout > 0 {
+		opts = append(opts, config.WithTimeout(c.timeout))
+	}
+
+	if c.configFile != "" {
+		return config.FromFile(c.configFile, opts...)
+	}
+	return config.FromEnv(envPrefix, opts...)
+}
+
+// userAPI creates the UserAPI authenticated with the keystore entry, if set,
+// or with the strongest of the provided user credentials.
+func (c *cli) userAPI() (*wallet.UserAPI, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.key != "":
+		path, password, err := c.keystoreCredentials()
+		if err != nil {
+			return nil, err
+		}
+		return wallet.NewUserAPIFromKeystore(cfg, path, c.key, password)
+	case c.xPriv != "":
+		return wallet.NewUserAPIWithXPriv(cfg, c.xPriv)
+	case c.accessKey != "":
+		return wallet.NewUserAPIWithAccessKey(cfg, c.accessKey)
+	case c.xPub != "":
+		return wallet.NewUserAPIWithXPub(cfg, c.xPub)
+	default:
+		return nil, errMissingUserCredentials
+	}
+}
+
+// adminAPI creates the AdminAPI authenticated with the keystore entry, if set,
+// or with the strongest of the provided admin credentials.
+func (c *cli) adminAPI() (*wallet.AdminAPI, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.adminKey != "":
+		path, password, err := c.keystoreCredentials()
+		if err != nil {
+			return nil, err
+		}
+		return wallet.NewAdminAPIFromKeystore(cfg, path, c.adminKey, password)
+	case c.adminXPriv != "":
+		return wallet.NewAdminAPIWithXPriv(cfg, c.adminXPriv)
+	case c.adminXPub != "":
+		return wallet.NewAdminAPIWithXPub(cfg, c.adminXPub)
+	default:
+		return nil, errMissingAdminCredentials
+	}
+}
+
+// parseError turns a flag parsing error, already printed by the flag set, into the error returned by execute.
+func parseError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return errUsage
+}
+
+// usageError prints the message followed by the usage and returns errUsage.
+func (c *cli) usageError(usage func(), format string, args ...any) error {
+	fmt.Fprintf(c.stderr, format+"\n", args...)
+	usage()
+	return errUsage
+}
+
+func flagOrEnv(value, env string) string {
+	if value != "" {
+		return value
+	}
+	return strings.TrimSpace(os.Getenv(env))
+}
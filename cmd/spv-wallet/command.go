@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// action runs a command with the arguments left after parsing its flags.
+type action func(ctx context.Context, c *cli, args []string) error
+
+// command is a node of the command tree: a group of subcommands or a leaf running an action.
+type command struct {
+	name        string
+	args        string // Synopsis of the arguments, e.g. "[flags] <id>".
+	summary     string
+	nargs       int // Number of required arguments, -1 for any.
+	subcommands []*command
+
+	// setup defines the flags of a leaf command and returns its action, which reads the parsed flags.
+	setup func(fs *flag.FlagSet) action
+}
+
+func (cmd *command) execute(ctx context.Context, c *cli, path string, args []string) error {
+	if cmd.setup == nil {
+		return cmd.dispatch(ctx, c, path, args)
+	}
+
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	act := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: %s %s\n\n%s\n", path, cmd.args, cmd.summary)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(c.stderr, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if cmd.nargs >= 0 && fs.NArg() != cmd.nargs {
+		return c.usageError(fs.Usage, "%s: expected %d argument(s), got %d", path, cmd.nargs, fs.NArg())
+	}
+
+	return act(ctx, c, fs.Args())
+}
+
+func (cmd *command) dispatch(ctx context.Context, c *cli, path string, args []string) error {
+	usage := func() { cmd.printUsage(c.stderr, path) }
+	if len(args) == 0 {
+		return c.usageError(usage, "%s: missing command", path)
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		cmd.printUsage(c.stdout, path)
+		return nil
+	}
+
+	for _, sub := range cmd.subcommands {
+		if sub.name == args[0] {
+			return sub.execute(ctx, c, path+" "+sub.name, args[1:])
+		}
+	}
+
+	return c.usageError(usage, "%s: unknown command %q", path, args[0])
+}
+
+func (cmd *command) printUsage(w io.Writer, path string) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [arguments]\n", path)
+	if cmd.summary != "" {
+		fmt.Fprintf(w, "\n%s\n", cmd.summary)
+	}
+
+	fmt.Fprintln(w, "\nCommands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, sub := range cmd.subcommands {
+		fmt.Fprintf(tw, "  %s\t%s\n", sub.name, sub.summary)
+	}
+	tw.Flush()
+}
+
+func rootCommand() *command {
+	return &command{
+		summary: "Command-line client of the SPV Wallet API.",
+		subcommands: []*command{
+			xPubCommand(),
+			balanceCommand(),
+			transactionsCommand(),
+			contactsCommand(),
+			accessKeysCommand(),
+			utxosCommand(),
+			merkleRootsCommand(),
+			keysCommand(),
+			adminCommand(),
+		},
+	}
+}
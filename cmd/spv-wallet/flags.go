@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/queryparams"
+	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet/models/filter"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+// listFlags holds the pagination and filtering flags of the list commands.
+type listFlags struct {
+	page     filter.Page
+	all      bool
+	metadata metadataFlag
+}
+
+func addListFlags(fs *flag.FlagSet) *listFlags {
+	l := &listFlags{metadata: metadataFlag{}}
+	fs.IntVar(&l.page.Number, "page", 0, "page `number`, starting at 1")
+	fs.IntVar(&l.page.Size, "size", 0, "page `size`")
+	fs.StringVar(&l.page.SortBy, "sort-by", "", "`field` to sort by, e.g. created_at")
+	fs.StringVar(&l.page.Sort, "sort", "", "sort `direction`: asc or desc")
+	fs.BoolVar(&l.all, "all", false, "fetch all pages, ignoring -page and -size")
+	fs.Var(l.metadata, "metadata", "filter by metadata `key=value`, repeatable")
+	return l
+}
+
+// fetchList fetches the page selected by the flags, or all pages with -all.
+func fetchList[T any, F queries.QueryFilters](
+	ctx context.Context,
+	l *listFlags,
+	page func(context.Context, ...queries.QueryOption[F]) (*response.PageModel[T], error),
+	all func(context.Context, ...queries.QueryOption[F]) queries.Iterator[T],
+) ([]*T, error) {
+	var opts []queries.QueryOption[F]
+	if len(l.metadata) > 0 {
+		opts = append(opts, queries.QueryWithMetadataFilter[F](l.metadata))
+	}
+	if l.all {
+		return all(ctx, opts...).Collect()
+	}
+
+	res, err := page(ctx, append(opts, queries.QueryWithPageFilter[F](l.page))...)
+	if err != nil {
+		return nil, err
+	}
+	return res.Content, nil
+}
+
+// metadataFlag collects repeated key=value flags.
+type metadataFlag map[string]any
+
+func (m metadataFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m metadataFlag) metadata() queryparams.Metadata {
+	return queryparams.Metadata(m)
+}
+
+func (m metadataFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+
+	m[k] = v
+	return nil
+}
+
+// recipientsFlag collects repeated paymail:satoshis flags.
+type recipientsFlag []*commands.Recipients
+
+func (r *recipientsFlag) String() string {
+	parts := make([]string, 0, len(*r))
+	for _, recipient := range *r {
+		parts = append(parts, fmt.Sprintf("%s:%d", recipient.To, recipient.Satoshis))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r *recipientsFlag) Set(value string) error {
+	to, amount, ok := strings.Cut(value, ":")
+	if !ok || to == "" {
+		return fmt.Errorf("expected paymail:satoshis, got %q", value)
+	}
+
+	satoshis, err := strconv.ParseUint(amount, 10, 64)
+	if err != nil || satoshis == 0 {
+		return fmt.Errorf("invalid amount of satoshis %q", amount)
+	}
+
+	*r = append(*r, &commands.Recipients{To: to, Satoshis: satoshis})
+	return nil
+}
+
+// stringsFlag collects repeated string flags.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
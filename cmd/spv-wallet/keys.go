@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+)
+
+// keysResult is the output of the keys commands. Empty fields are omitted.
+type keysResult struct {
+	Mnemonic  string `json:"mnemonic,omitempty"`
+	XPriv     string `json:"xPriv,omitempty"`
+	XPub      string `json:"xPub,omitempty"`
+	Path      string `json:"path,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	WIF       string `json:"wif,omitempty"`
+}
+
+func (c *cli) printKeys(r keysResult) error {
+	var fields []field
+	for _, f := range []field{
+		{"Mnemonic", r.Mnemonic},
+		{"Path", r.Path},
+		{"xPriv", r.XPriv},
+		{"xPub", r.XPub},
+		{"Access key", r.AccessKey},
+		{"WIF", r.WIF},
+	} {
+		if f.value != "" {
+			fields = append(fields, f)
+		}
+	}
+	return c.printDetails(r, fields)
+}
+
+// mnemonicFlags are the flags of the commands generating an xPriv from a mnemonic phrase.
+type mnemonicFlags struct {
+	passphrase *string
+	network    *string
+}
+
+func addMnemonicFlags(fs *flag.FlagSet) mnemonicFlags {
+	return mnemonicFlags{
+		passphrase: fs.String("passphrase", "", "BIP39 `passphrase` mixed with the mnemonic phrase, prefer "+envPassphrase),
+		network:    fs.String("network", walletkeys.MainNet.String(), "`network` of the keys: mainnet, testnet or regtest"),
+	}
+}
+
+// options returns the walletkeys options set by the flags.
+func (f mnemonicFlags) options() ([]walletkeys.Option, error) {
+	network, err := walletkeys.ParseNetwork(*f.network)
+	if err != nil {
+		return nil, err
+	}
+
+	return []walletkeys.Option{
+		walletkeys.WithPassphrase(flagOrEnv(*f.passphrase, envPassphrase)),
+		walletkeys.WithNetwork(network),
+	}, nil
+}
+
+// addSaveFlag defines the -save flag of the commands storing their result in the keystore.
+func addSaveFlag(fs *flag.FlagSet) *string {
+	return fs.String("save", "", "save the result in the keystore under the `name`, creating the keystore if missing")
+}
+
+func keysCommand() *command {
+	return &command{
+		name:    "keys",
+		summary: "Generate, derive and convert keys offline",
+		subcommands: []*command{
+			{
+				name:    "generate",
+				args:    "[-mnemonic=false] [-entropy <bits>] [-passphrase <passphrase>] [-network <network>] [-save <name>]",
+				summary: "Generate a random xPriv, from a new mnemonic phrase by default",
+				setup: func(fs *flag.FlagSet) action {
+					withMnemonic := fs.Bool("mnemonic", true, "generate the xPriv from a new mnemonic phrase")
+					entropy := fs.Int("entropy", walletkeys.DefaultEntropy, "entropy of the mnemonic phrase in `bits`: 128, 160, 192, 224 or 256 for 12 to 24 words")
+					flags := addMnemonicFlags(fs)
+					save := addSaveFlag(fs)
+					return func(_ context.Context, c *cli, _ []string) error {
+						opts, err := flags.options()
+						if err != nil {
+							return err
+						}
+						opts = append(opts, walletkeys.WithEntropy(*entropy))
+
+						var result keysResult
+						if *withMnemonic {
+							keys, err := walletkeys.RandomKeysWithMnemonic(opts...)
+							if err != nil {
+								return err
+							}
+							result = keysResult{Mnemonic: keys.Mnemonic(), XPriv: keys.XPriv(), XPub: keys.XPub()}
+						} else {
+							keys, err := walletkeys.RandomKeys(opts...)
+							if err != nil {
+								return err
+							}
+							result = keysResult{XPriv: keys.XPriv(), XPub: keys.XPub()}
+						}
+
+						return c.saveAndPrintKeys(*save, result)
+					}
+				},
+			},
+			{
+				name:    "from-mnemonic",
+				args:    "[-passphrase <passphrase>] [-network <network>] [-save <name>] [<word>...]",
+				summary: "Restore the xPriv from a mnemonic phrase, read from stdin if not given",
+				nargs:   -1,
+				setup: func(fs *flag.FlagSet) action {
+					flags := addMnemonicFlags(fs)
+					save := addSaveFlag(fs)
+					return func(_ context.Context, c *cli, args []string) error {
+						opts, err := flags.options()
+						if err != nil {
+							return err
+						}
+
+						mnemonic := strings.Join(args, " ")
+						if mnemonic == "" {
+							if c.isTerminal() {
+								fmt.Fprint(c.stderr, "Mnemonic: ")
+							}
+							line, err := c.readLine()
+							if err != nil {
+								return fmt.Errorf("failed to read mnemonic: %w", err)
+							}
+							mnemonic = strings.Join(strings.Fields(line), " ")
+						}
+
+						xPriv, err := walletkeys.XPrivFromMnemonic(mnemonic, opts...)
+						if err != nil {
+							return err
+						}
+						xPub, err := bip32.GetExtendedPublicKey(xPriv)
+						if err != nil {
+							return err
+						}
+
+						return c.saveAndPrintKeys(*save, keysResult{Mnemonic: mnemonic, XPriv: xPriv.String(), XPub: xPub})
+					}
+				},
+			},
+			{
+				name:    "xpub",
+				args:    "[<xpriv>]",
+				summary: "Derive the xPub from the xPriv, the user xPriv if not given",
+				nargs:   -1,
+				setup: func(*flag.FlagSet) action {
+					return func(_ context.Context, c *cli, args []string) error {
+						xPriv, err := c.keyArg(args)
+						if err != nil {
+							return err
+						}
+
+						xPub, err := walletkeys.XPubFromXPriv(xPriv)
+						if err != nil {
+							return err
+						}
+
+						return c.printKeys(keysResult{XPub: xPub})
+					}
+				},
+			},
+			{
+				name:    "derive",
+				args:    "-path <path> [-save <name>] [<xpriv or xpub>]",
+				summary: "Derive the child key at the BIP32 path, e.g. 0/1 or 44'/236'/0', from the key or the user xPriv",
+				nargs:   -1,
+				setup: func(fs *flag.FlagSet) action {
+					path := fs.String("path", "", "BIP32 derivation `path`, hardened children marked with '")
+					save := addSaveFlag(fs)
+					return func(_ context.Context, c *cli, args []string) error {
+						if *path == "" {
+							return c.usageError(fs.Usage, "%s: -path is required", fs.Name())
+						}
+						key, err := c.keyArg(args)
+						if err != nil {
+							return err
+						}
+
+						child, err := walletkeys.DeriveChild(key, *path)
+						if err != nil {
+							return err
+						}
+
+						result := keysResult{Path: *path}
+						if child.IsPrivate() {
+							result.XPriv = child.String()
+							if result.XPub, err = bip32.GetExtendedPublicKey(child); err != nil {
+								return err
+							}
+						} else {
+							if *save != "" {
+								return fmt.Errorf("derived xPub can't be saved in the keystore")
+							}
+							result.XPub = child.String()
+						}
+
+						return c.saveAndPrintKeys(*save, result)
+					}
+				},
+			},
+			{
+				name:    "access-key",
+				summary: "Convert access keys between hex and WIF",
+				subcommands: []*command{
+					{
+						name:    "to-wif",
+						args:    "<hex>",
+						summary: "Convert the hex encoded access key, as returned by the SPV Wallet, to WIF",
+						nargs:   1,
+						setup: func(*flag.FlagSet) action {
+							return func(_ context.Context, c *cli, args []string) error {
+								wif, err := walletkeys.AccessKeyToWIF(args[0])
+								if err != nil {
+									return err
+								}
+
+								return c.printKeys(keysResult{AccessKey: args[0], WIF: wif})
+							}
+						},
+					},
+					{
+						name:    "from-wif",
+						args:    "[-save <name>] <wif>",
+						summary: "Convert the WIF private key to the hex encoded access key accepted by the SPV Wallet",
+						nargs:   1,
+						setup: func(fs *flag.FlagSet) action {
+							save := addSaveFlag(fs)
+							return func(_ context.Context, c *cli, args []string) error {
+								accessKey, err := walletkeys.AccessKeyFromWIF(args[0])
+								if err != nil {
+									return err
+								}
+
+								return c.saveAndPrintKeys(*save, keysResult{AccessKey: accessKey, WIF: args[0]})
+							}
+						},
+					},
+				},
+			},
+			{
+				name:    "passwd",
+				summary: "Change the keystore password, the new one read from " + envNewKeystorePass + " or prompted",
+				setup: func(*flag.FlagSet) action {
+					return func(_ context.Context, c *cli, _ []string) error {
+						ks, err := c.openKeystore(false)
+						if err != nil {
+							return err
+						}
+						password, err := c.newPassword(envNewKeystorePass)
+						if err != nil {
+							return err
+						}
+						if err := ks.ChangePassword(password); err != nil {
+							return err
+						}
+
+						c.printDone("Changed password of keystore %s", ks.Path())
+						return nil
+					}
+				},
+			},
+			{
+				name:    "remove",
+				args:    "<name>",
+				summary: "Remove the entry from the keystore",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(_ context.Context, c *cli, args []string) error {
+						ks, err := c.openKeystore(false)
+						if err != nil {
+							return err
+						}
+						if err := ks.Delete(args[0]); err != nil {
+							return err
+						}
+
+						c.printDone("Removed %q from keystore %s", args[0], ks.Path())
+						return nil
+					}
+				},
+			},
+			{
+				name:    "list",
+				summary: "List the names of the keystore entries",
+				setup: func(*flag.FlagSet) action {
+					return func(_ context.Context, c *cli, _ []string) error {
+						ks, err := c.openKeystore(false)
+						if err != nil {
+							return err
+						}
+
+						names := ks.Names()
+						rows := make([][]string, 0, len(names))
+						for _, name := range names {
+							rows = append(rows, []string{name})
+						}
+						return c.printTable(names, []string{"NAME"}, rows)
+					}
+				},
+			},
+		},
+	}
+}
+
+// keyArg returns the key given as the only argument, or the user xPriv if there are no arguments.
+func (c *cli) keyArg(args []string) (string, error) {
+	switch {
+	case len(args) == 1:
+		return args[0], nil
+	case len(args) > 1:
+		return "", fmt.Errorf("expected at most 1 argument, got %d", len(args))
+	case c.xPriv != "":
+		return c.xPriv, nil
+	default:
+		return "", fmt.Errorf("missing key: pass it as an argument or set %s", envXPriv)
+	}
+}
+
+// saveAndPrintKeys saves the result in the keystore if name is set, then prints it.
+func (c *cli) saveAndPrintKeys(name string, r keysResult) error {
+	if name != "" {
+		entry := walletkeys.KeystoreEntry{XPriv: r.XPriv, Mnemonic: r.Mnemonic, AccessKey: r.AccessKey}
+		if err := c.saveToKeystore(name, entry); err != nil {
+			return err
+		}
+	}
+
+	return c.printKeys(r)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMnemonic   = "absorb corn ostrich order sing boost just harvest enable make detail future desert bus adult"
+	testXPriv      = "xprv9s21ZrQH143K3Lh4wdicqvYNMcdh49rMLqDvQoyys8L6f5tfE2WkQN7ZVE2awBrfVWNSJ8pPd4QLLr94Nur85Dvj8kD8RoZghBuNTpvL8si"
+	testXPub       = "xpub661MyMwAqRbcFpmY3fFdD4V6ueUBTcaCi49XDCPbRTs5XtDomZpzxAS3LUb2hMfUVphDsSPxfjietmsBRFkLDY9Xa3P4jbgNDMnDK3UqJe2"
+	testChildXPriv = "xprv9x2QT5LAcoHU27SVEUK9pFbhKSHTwhN9qFD2pciEsGHW9oKMrSSHeHTrXaveS7Dw4mMRrtWVQLncziuwruDRVJhbNubvjfoyXYcobGZvTBU"
+	testAccessKey  = "3fd870d6bf1725f04084cf31209c04be5bd9bed001a390ad3bc632a55a3ee078"
+	testWIF        = "KyMpQThiY3ie3PToyU7F39GJHacYvkqt8gusekm7d6qCVKwpb4je"
+	trezorMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	trezorXPriv    = "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF"
+	trezorTPriv    = "tprv8ZgxMBicQKsPeWHBt7a68nPnvgTnuDhUgDWC8wZCgA8GahrQ3f3uWpq7wE7Uc1dLBnCe1hhCZ886K6ND37memRDWqsA9HgSKDXtwh2Qxo6J"
+)
+
+func TestRun_Keys(t *testing.T) {
+	tests := map[string]struct {
+		stdin    string
+		args     []string
+		expected keysResult
+	}{
+		"xPriv from mnemonic arguments": {
+			args:     []string{"keys", "from-mnemonic", testMnemonic},
+			expected: keysResult{Mnemonic: testMnemonic, XPriv: testXPriv, XPub: testXPub},
+		},
+		"xPriv from mnemonic on stdin": {
+			stdin:    " " + testMnemonic + " \n",
+			args:     []string{"keys", "from-mnemonic"},
+			expected: keysResult{Mnemonic: testMnemonic, XPriv: testXPriv, XPub: testXPub},
+		},
+		"xPriv from mnemonic with passphrase": {
+			args:     []string{"keys", "from-mnemonic", "-passphrase", "TREZOR", trezorMnemonic},
+			expected: keysResult{Mnemonic: trezorMnemonic, XPriv: trezorXPriv, XPub: "xpub661MyMwAqRbcGB88KaFbLGiYAat55APKhtWg4uYMkXAmfuSTbq2QYsn9sKJCj1YqZPafsboef4h4YbXXhNhPwMbkHTpkf3zLhx7HvFw1NDy"},
+		},
+		"xPriv from mnemonic on testnet": {
+			args:     []string{"keys", "from-mnemonic", "-passphrase", "TREZOR", "-network", "testnet", trezorMnemonic},
+			expected: keysResult{Mnemonic: trezorMnemonic, XPriv: trezorTPriv, XPub: "tpubD6NzVbkrYhZ4XyJymmEgYC3uVhyj4YtPFX6yRTbW6RvfRC7Ag3sVhKSz7MNzFWW5MJ7aVBKXCAX7En296EYdpo43M4a4LaeaHuhhgHToSJF"},
+		},
+		"xPub from xPriv": {
+			args:     []string{"keys", "xpub", testXPriv},
+			expected: keysResult{XPub: testXPub},
+		},
+		"xPub from user xPriv": {
+			args:     []string{"-xpriv", testXPriv, "keys", "xpub"},
+			expected: keysResult{XPub: testXPub},
+		},
+		"Child key": {
+			args:     []string{"keys", "derive", "-path", "m/0/1", testXPriv},
+			expected: keysResult{Path: "m/0/1", XPriv: testChildXPriv, XPub: "xpub6B1kras4TAqmEbWxLVrABPYRsU7xMA61CU8dd17rRbpV2beWPykYC5nLNt6bXqaCTmDkckmrGnPRbuTxu5Gvak8CLBBPzU6b8aQ7MKrwE1d"},
+		},
+		"Child xPub from xPub": {
+			args:     []string{"keys", "derive", "-path", "0/1", testXPub},
+			expected: keysResult{Path: "0/1", XPub: "xpub6B1kras4TAqmEbWxLVrABPYRsU7xMA61CU8dd17rRbpV2beWPykYC5nLNt6bXqaCTmDkckmrGnPRbuTxu5Gvak8CLBBPzU6b8aQ7MKrwE1d"},
+		},
+		"Access key to WIF": {
+			args:     []string{"keys", "access-key", "to-wif", testAccessKey},
+			expected: keysResult{AccessKey: testAccessKey, WIF: testWIF},
+		},
+		"Access key from WIF": {
+			args:     []string{"keys", "access-key", "from-wif", testWIF},
+			expected: keysResult{AccessKey: testAccessKey, WIF: testWIF},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			givenNoCredentialsInEnv(t)
+
+			// when:
+			code, stdout, stderr := runCLIWithStdin(t, tc.stdin, append([]string{"-output", "json"}, tc.args...)...)
+
+			// then:
+			require.Zero(t, code, stderr)
+			var got keysResult
+			require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+			require.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("Generated keys", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+
+		// when:
+		code, stdout, stderr := runCLI(t, "-output", "json", "keys", "generate")
+
+		// then:
+		require.Zero(t, code, stderr)
+		var got keysResult
+		require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		require.NotEmpty(t, got.Mnemonic)
+		xPub, err := walletkeys.XPubFromXPriv(got.XPriv)
+		require.NoError(t, err)
+		require.Equal(t, xPub, got.XPub)
+	})
+
+	t.Run("Generated keys with options", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+		t.Setenv(envPassphrase, "secret")
+
+		// when:
+		code, stdout, stderr := runCLI(t, "-output", "json", "keys", "generate", "-entropy", "256", "-network", "regtest")
+
+		// then:
+		require.Zero(t, code, stderr)
+		var got keysResult
+		require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		require.Len(t, strings.Fields(got.Mnemonic), 24)
+		xPriv, err := walletkeys.XPrivFromMnemonic(got.Mnemonic, walletkeys.WithPassphrase("secret"), walletkeys.WithNetwork(walletkeys.RegTest))
+		require.NoError(t, err)
+		require.Equal(t, xPriv.String(), got.XPriv)
+	})
+
+	t.Run("Mnemonic checksum error", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+
+		// when:
+		code, _, stderr := runCLI(t, "keys", "from-mnemonic", strings.Replace(trezorMnemonic, "about", "abandon", 1))
+
+		// then:
+		require.Equal(t, exitCodeFailure, code)
+		require.Contains(t, stderr, "invalid mnemonic checksum")
+	})
+
+	t.Run("Unsupported network", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+
+		// when:
+		code, _, stderr := runCLI(t, "keys", "generate", "-network", "stn")
+
+		// then:
+		require.Equal(t, exitCodeFailure, code)
+		require.Contains(t, stderr, `unsupported network: "stn"`)
+	})
+
+	t.Run("Invalid access key", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+
+		// when:
+		code, _, stderr := runCLI(t, "keys", "access-key", "to-wif", "abc")
+
+		// then:
+		require.Equal(t, exitCodeFailure, code)
+		require.Contains(t, stderr, "invalid access key")
+	})
+}
+
+func TestRun_KeysSave(t *testing.T) {
+	// given:
+	givenNoCredentialsInEnv(t)
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	// when:
+	code, _, stderr := runCLIWithStdin(t, "secret\n", "-keystore", path, "keys", "from-mnemonic", "-save", "alice", testMnemonic)
+	require.Zero(t, code, stderr)
+	t.Setenv(envKeystorePass, "secret")
+	code, _, stderr = runCLI(t, "-keystore", path, "keys", "access-key", "from-wif", "-save", "alice-access-key", testWIF)
+	require.Zero(t, code, stderr)
+
+	// then:
+	code, stdout, stderr := runCLI(t, "-keystore", path, "keys", "list")
+	require.Zero(t, code, stderr)
+	require.Equal(t, "NAME\nalice\nalice-access-key\n", stdout)
+
+	ks, err := walletkeys.OpenKeystore(path, "secret")
+	require.NoError(t, err)
+	entry, err := ks.Get("alice")
+	require.NoError(t, err)
+	require.Equal(t, testMnemonic, entry.Mnemonic)
+	require.Equal(t, testXPriv, entry.XPriv)
+	entry, err = ks.Get("alice-access-key")
+	require.NoError(t, err)
+	require.Equal(t, testAccessKey, entry.AccessKey)
+}
+
+func TestRun_KeystorePasswdAndRemove(t *testing.T) {
+	// given:
+	givenNoCredentialsInEnv(t)
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := walletkeys.CreateKeystore(path, "secret")
+	require.NoError(t, err)
+	require.NoError(t, ks.Put("alice", walletkeys.KeystoreEntry{XPriv: testXPriv}))
+	require.NoError(t, ks.Put("bob", walletkeys.KeystoreEntry{AccessKey: testAccessKey}))
+
+	// when:
+	code, stdout, stderr := runCLIWithStdin(t, "secret\nnew secret\n", "-keystore", path, "keys", "passwd")
+	require.Zero(t, code, stderr)
+	require.Contains(t, stdout, "Changed password of keystore")
+	t.Setenv(envKeystorePass, "new secret")
+	code, _, stderr = runCLI(t, "-keystore", path, "keys", "remove", "bob")
+	require.Zero(t, code, stderr)
+
+	// then:
+	ks, err = walletkeys.OpenKeystore(path, "new secret")
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, ks.Names())
+	entry, err := ks.Get("alice")
+	require.NoError(t, err)
+	require.Equal(t, testXPriv, entry.XPriv)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"golang.org/x/term"
+)
+
+var (
+	errMissingKeystore  = fmt.Errorf("missing keystore: set -keystore or %s", envKeystore)
+	errPasswordMismatch = errors.New("passwords don't match")
+)
+
+// openKeystore opens the keystore, creating it if missing and create is set.
+func (c *cli) openKeystore(create bool) (*walletkeys.Keystore, error) {
+	if c.keystorePath == "" {
+		return nil, errMissingKeystore
+	}
+
+	_, err := os.Stat(c.keystorePath)
+	if create && errors.Is(err, fs.ErrNotExist) {
+		password, err := c.newPassword(envKeystorePass)
+		if err != nil {
+			return nil, err
+		}
+		return walletkeys.CreateKeystore(c.keystorePath, password)
+	}
+
+	password, err := c.password("Keystore password: ", envKeystorePass)
+	if err != nil {
+		return nil, err
+	}
+	return walletkeys.OpenKeystore(c.keystorePath, password)
+}
+
+// keystoreCredentials returns the path and the password of the keystore the API credentials are read from.
+func (c *cli) keystoreCredentials() (string, string, error) {
+	if c.keystorePath == "" {
+		return "", "", errMissingKeystore
+	}
+
+	password, err := c.password("Keystore password: ", envKeystorePass)
+	if err != nil {
+		return "", "", err
+	}
+	return c.keystorePath, password, nil
+}
+
+// saveToKeystore stores the entry under the name, creating the keystore if missing.
+func (c *cli) saveToKeystore(name string, entry walletkeys.KeystoreEntry) error {
+	ks, err := c.openKeystore(true)
+	if err != nil {
+		return err
+	}
+	if err := ks.Put(name, entry); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "Saved %q to keystore %s\n", name, ks.Path())
+	return nil
+}
+
+// newPassword reads a new keystore password from the env variable, or prompts for it,
+// asking for a confirmation when prompting in a terminal.
+func (c *cli) newPassword(env string) (string, error) {
+	password, err := c.password("New keystore password: ", env)
+	if err != nil || !c.isTerminal() || os.Getenv(env) != "" {
+		return password, err
+	}
+
+	confirmation, err := c.password("Repeat password: ", env)
+	if err != nil {
+		return "", err
+	}
+	if confirmation != password {
+		return "", errPasswordMismatch
+	}
+	return password, nil
+}
+
+// password returns the keystore password from the env variable, prompting for it
+// without echo in a terminal, or reading the next line of stdin otherwise.
+func (c *cli) password(prompt, env string) (string, error) {
+	if password, ok := os.LookupEnv(env); ok && password != "" {
+		return password, nil
+	}
+
+	if c.isTerminal() {
+		fmt.Fprint(c.stderr, prompt)
+		password, err := term.ReadPassword(int(c.stdin.(*os.File).Fd()))
+		fmt.Fprintln(c.stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	password, err := c.readLine()
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return password, nil
+}
+
+// readLine reads a line of stdin, without the line terminator.
+func (c *cli) readLine() (string, error) {
+	if c.lines == nil {
+		c.lines = bufio.NewReader(c.stdin)
+	}
+
+	line, err := c.lines.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *cli) isTerminal() bool {
+	f, ok := c.stdin.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,36 @@
+// Command spv-wallet is a command-line client of the SPV Wallet API built on UserAPI and AdminAPI.
+//
+// Usage:
+//
+//	spv-wallet [global flags] <command> [subcommand] [flags] [arguments]
+//
+// The connection settings are loaded with config.FromEnv from the SPVWALLET_* environment variables,
+// or with config.FromFile from the -config file, and overridden by the -addr and -timeout flags.
+// The credentials are read from the flags or, preferably, from the environment variables, which
+// unlike flags are not visible to the other users of the machine:
+//
+//	SPVWALLET_XPRIV, SPVWALLET_ACCESS_KEY, SPVWALLET_XPUB  user credentials, in that order of precedence
+//	SPVWALLET_ADMIN_XPRIV, SPVWALLET_ADMIN_XPUB            admin credentials, in that order of precedence
+//	SPVWALLET_KEYSTORE, SPVWALLET_KEYSTORE_PASSWORD        encrypted keystore the keys commands save to
+//	SPVWALLET_KEY, SPVWALLET_ADMIN_KEY                     keystore entries with the user and admin credentials,
+//	                                                       taking precedence over the credentials above
+//	SPVWALLET_NEW_KEYSTORE_PASSWORD                        new password set by the keys passwd command
+//	SPVWALLET_MNEMONIC_PASSPHRASE                          BIP39 passphrase of the keys generated from mnemonic phrases
+//
+// The keys commands work offline: they generate and convert keys without connecting to the SPV Wallet.
+// Results are printed as a table or, with -output json, as JSON. Run "spv-wallet help" for the list of commands.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	xPubResponse         = `{"id":"af64633f-b2ce-441e-9d61-acda0884eb53","currentBalance":315,"nextInternalNum":13,"nextExternalNum":2}`
+	transactionsResponse = `{"content":[{"id":"tx-1","status":"MINED","direction":"incoming","outputValue":100,"fee":1,"blockHeight":833505}],"page":{"size":5,"number":2}}`
+	xPubsResponse        = `{"content":[{"id":"xpub-1","currentBalance":42}],"page":{"size":50,"number":1}}`
+	notFoundResponse     = `{"code":"error-transaction-not-found","message":"transaction not found"}`
+)
+
+func TestRun_Usage(t *testing.T) {
+	tests := map[string]struct {
+		args           []string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		"Help": {
+			args:           []string{"help"},
+			expectedCode:   0,
+			expectedStdout: "Commands:",
+		},
+		"Group help": {
+			args:           []string{"admin", "help"},
+			expectedCode:   0,
+			expectedStdout: "webhooks",
+		},
+		"No command": {
+			args:           nil,
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: "Usage: spv-wallet",
+		},
+		"Unknown command": {
+			args:           []string{"wallets"},
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: `unknown command "wallets"`,
+		},
+		"Unsupported output format": {
+			args:           []string{"-output", "xml", "xpub"},
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: `unsupported output format "xml"`,
+		},
+		"Missing argument": {
+			args:           []string{"transactions", "get"},
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: "expected 1 argument(s), got 0",
+		},
+		"Send without recipients": {
+			args:           []string{"transactions", "send"},
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: "at least one -to or -op-return is required",
+		},
+		"Invalid recipient": {
+			args:           []string{"transactions", "send", "-to", "alice@example.com"},
+			expectedCode:   exitCodeWrongArgs,
+			expectedStderr: "expected paymail:satoshis",
+		},
+		"Missing user credentials": {
+			args:           []string{"xpub"},
+			expectedCode:   exitCodeFailure,
+			expectedStderr: errMissingUserCredentials.Error(),
+		},
+		"Missing admin credentials": {
+			args:           []string{"admin", "stats"},
+			expectedCode:   exitCodeFailure,
+			expectedStderr: errMissingAdminCredentials.Error(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			givenNoCredentialsInEnv(t)
+
+			// when:
+			code, stdout, stderr := runCLI(t, tc.args...)
+
+			// then:
+			require.Equal(t, tc.expectedCode, code)
+			require.Contains(t, stdout, tc.expectedStdout)
+			require.Contains(t, stderr, tc.expectedStderr)
+		})
+	}
+}
+
+func TestRun_UserCommands(t *testing.T) {
+	tests := map[string]struct {
+		args           []string
+		expectedPath   string
+		expectedQuery  url.Values
+		expectedStdout string
+	}{
+		"xPub as table": {
+			args:           []string{"xpub"},
+			expectedPath:   "/api/v1/users/current",
+			expectedStdout: "Balance:            315",
+		},
+		"Balance": {
+			args:           []string{"balance"},
+			expectedPath:   "/api/v1/users/current",
+			expectedStdout: "Balance:  315\n",
+		},
+		"Transactions page": {
+			args:           []string{"transactions", "list", "-page", "2", "-size", "5"},
+			expectedPath:   "/api/v1/transactions",
+			expectedQuery:  url.Values{"page": {"2"}, "size": {"5"}},
+			expectedStdout: "tx-1  incoming   MINED   100    1    833505",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			givenNoCredentialsInEnv(t)
+			t.Setenv(envXPriv, testutils.UserXPriv)
+			server, requests := givenSPVWalletServer(t)
+
+			// when:
+			code, stdout, stderr := runCLI(t, append([]string{"-addr", server.URL}, tc.args...)...)
+
+			// then:
+			require.Zero(t, code, stderr)
+			req := <-requests
+			require.Equal(t, tc.expectedPath, req.URL.Path)
+			require.NotEmpty(t, req.Header.Get("X-Auth-Signature"))
+			for key, values := range tc.expectedQuery {
+				require.Equal(t, values, req.URL.Query()[key])
+			}
+			require.Contains(t, stdout, tc.expectedStdout)
+		})
+	}
+
+	t.Run("xPub as JSON", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+		server, _ := givenSPVWalletServer(t)
+
+		// when:
+		code, stdout, stderr := runCLI(t, "-addr", server.URL, "-xpub", testutils.UserXPub, "-output", "json", "xpub")
+
+		// then:
+		require.Zero(t, code, stderr)
+		var xPub response.Xpub
+		require.NoError(t, json.Unmarshal([]byte(stdout), &xPub))
+		require.Equal(t, uint64(315), xPub.CurrentBalance)
+	})
+
+	t.Run("Access key takes precedence over xPub", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+		t.Setenv(envAccessKey, testutils.UserPrivAccessKey)
+		t.Setenv(envXPub, testutils.UserXPub)
+		server, requests := givenSPVWalletServer(t)
+
+		// when:
+		code, _, stderr := runCLI(t, "-addr", server.URL, "xpub")
+
+		// then:
+		require.Zero(t, code, stderr)
+		req := <-requests
+		require.NotEmpty(t, req.Header.Get(models.AuthAccessKey))
+		require.Empty(t, req.Header.Get(models.AuthHeader))
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+		t.Setenv(envAccessKey, testutils.UserPrivAccessKey)
+		server, _ := givenSPVWalletServer(t)
+
+		// when:
+		code, _, stderr := runCLI(t, "-addr", server.URL, "transactions", "get", "missing")
+
+		// then:
+		require.Equal(t, exitCodeFailure, code)
+		require.Contains(t, stderr, "HTTP 404: transaction not found")
+	})
+}
+
+func TestRun_AdminXPubs(t *testing.T) {
+	// given:
+	givenNoCredentialsInEnv(t)
+	t.Setenv(envAdminXPriv, testutils.UserXPriv)
+	server, requests := givenSPVWalletServer(t)
+
+	// when:
+	code, stdout, stderr := runCLI(t, "-addr", server.URL, "admin", "xpubs", "list", "-metadata", "team=payments")
+
+	// then:
+	require.Zero(t, code, stderr)
+	req := <-requests
+	require.Equal(t, "/api/v1/admin/users", req.URL.Path)
+	require.Equal(t, "payments", req.URL.Query().Get("metadata[team]"))
+	require.Contains(t, stdout, "xpub-1  42")
+}
+
+func TestRun_KeystoreCredentials(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		expectedPath string
+	}{
+		"User xPriv": {
+			args:         []string{"-key", "user", "xpub"},
+			expectedPath: "/api/v1/users/current",
+		},
+		"User access key": {
+			args:         []string{"-key", "access-key", "xpub"},
+			expectedPath: "/api/v1/users/current",
+		},
+		"Admin xPriv": {
+			args:         []string{"-admin-key", "user", "admin", "xpubs", "list"},
+			expectedPath: "/api/v1/admin/users",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			givenNoCredentialsInEnv(t)
+			path := testutils.GivenKeystore(t, map[string]walletkeys.KeystoreEntry{
+				"user":       {XPriv: testutils.UserXPriv},
+				"access-key": {AccessKey: testutils.UserPrivAccessKey},
+			})
+			t.Setenv(envKeystore, path)
+			server, requests := givenSPVWalletServer(t)
+
+			// when:
+			code, _, stderr := runCLIWithStdin(t, testutils.KeystorePassword+"\n", append([]string{"-addr", server.URL}, tc.args...)...)
+
+			// then:
+			require.Zero(t, code, stderr)
+			req := <-requests
+			require.Equal(t, tc.expectedPath, req.URL.Path)
+			require.NotEmpty(t, req.Header.Get("X-Auth-Signature"))
+		})
+	}
+
+	t.Run("Wrong password", func(t *testing.T) {
+		// given:
+		givenNoCredentialsInEnv(t)
+		path := testutils.GivenKeystore(t, map[string]walletkeys.KeystoreEntry{"user": {XPriv: testutils.UserXPriv}})
+		t.Setenv(envKeystorePass, "wrong password")
+
+		// when:
+		code, _, stderr := runCLI(t, "-keystore", path, "-key", "user", "xpub")
+
+		// then:
+		require.Equal(t, exitCodeFailure, code)
+		require.Contains(t, stderr, "invalid keystore password")
+	})
+}
+
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	return runCLIWithStdin(t, "", args...)
+}
+
+func runCLIWithStdin(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func givenNoCredentialsInEnv(t *testing.T) {
+	t.Helper()
+
+	for _, env := range []string{envXPriv, envXPub, envAccessKey, envAdminXPriv, envAdminXPub, envKeystore, envKeystorePass, envNewKeystorePass, envKey, envAdminKey, envPassphrase, envPrefix + "_ADDR"} {
+		t.Setenv(env, "")
+	}
+}
+
+// givenSPVWalletServer starts a fake SPV Wallet answering the requests of the tested commands.
+// Every served request is sent to the returned channel.
+func givenSPVWalletServer(t *testing.T) (*httptest.Server, <-chan *http.Request) {
+	t.Helper()
+
+	requests := make(chan *http.Request, 10)
+	routes := map[string]string{
+		"/api/v1/users/current": xPubResponse,
+		"/api/v1/transactions":  transactionsResponse,
+		"/api/v1/admin/users":   xPubsResponse,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		w.Header().Set("Content-Type", "application/json")
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			body = notFoundResponse
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// field is a labeled value of a details view.
+type field struct {
+	label string
+	value string
+}
+
+// printJSON writes v as indented JSON.
+func (c *cli) printJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTable writes v as JSON with -output json, or the rows under the header otherwise.
+func (c *cli) printTable(v any, header []string, rows [][]string) error {
+	if c.output == outputJSON {
+		return c.printJSON(v)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printDetails writes v as JSON with -output json, or one labeled field per line otherwise.
+func (c *cli) printDetails(v any, fields []field) error {
+	if c.output == outputJSON {
+		return c.printJSON(v)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", f.label, f.value)
+	}
+	return tw.Flush()
+}
+
+// printDone reports the completion of a command without a result. Nothing is written with -output json.
+func (c *cli) printDone(format string, args ...any) {
+	if c.output == outputJSON {
+		return
+	}
+	fmt.Fprintf(c.stdout, format+"\n", args...)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+func formatUint[T uint32 | uint64](n T) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
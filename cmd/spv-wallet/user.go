@@ -0,0 +1,555 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+func xPubCommand() *command {
+	return &command{
+		name:    "xpub",
+		summary: "Show the xPub of the user",
+		setup: func(*flag.FlagSet) action {
+			return func(ctx context.Context, c *cli, _ []string) error {
+				api, err := c.userAPI()
+				if err != nil {
+					return err
+				}
+
+				xPub, err := api.XPub(ctx)
+				if err != nil {
+					return err
+				}
+
+				return c.printDetails(xPub, []field{
+					{"ID", xPub.ID},
+					{"Balance", formatUint(xPub.CurrentBalance)},
+					{"Next internal num", formatUint(xPub.NextInternalNum)},
+					{"Next external num", formatUint(xPub.NextExternalNum)},
+					{"Created at", formatTime(xPub.CreatedAt)},
+				})
+			}
+		},
+	}
+}
+
+func balanceCommand() *command {
+	return &command{
+		name:    "balance",
+		summary: "Show the current balance of the user, in satoshis",
+		setup: func(*flag.FlagSet) action {
+			return func(ctx context.Context, c *cli, _ []string) error {
+				api, err := c.userAPI()
+				if err != nil {
+					return err
+				}
+
+				xPub, err := api.XPub(ctx)
+				if err != nil {
+					return err
+				}
+
+				balance := struct {
+					CurrentBalance uint64 `json:"currentBalance"`
+				}{xPub.CurrentBalance}
+				return c.printDetails(balance, []field{{"Balance", formatUint(balance.CurrentBalance)}})
+			}
+		},
+	}
+}
+
+func transactionsCommand() *command {
+	return &command{
+		name:    "transactions",
+		summary: "List, show and send transactions",
+		subcommands: []*command{
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the transactions of the user",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						txs, err := fetchList(ctx, list, api.Transactions, api.AllTransactions)
+						if err != nil {
+							return err
+						}
+
+						return c.printTransactions(txs)
+					}
+				},
+			},
+			{
+				name:    "get",
+				args:    "<id>",
+				summary: "Show a transaction of the user",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						tx, err := api.Transaction(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return c.printTransaction(tx)
+					}
+				},
+			},
+			{
+				name:    "send",
+				args:    "-to <paymail:satoshis> [-to ...] [-op-return <text> ...] [-metadata <key=value> ...]",
+				summary: "Send a transaction to the recipients; requires the user xPriv to sign it",
+				setup: func(fs *flag.FlagSet) action {
+					var (
+						recipients recipientsFlag
+						opReturn   stringsFlag
+						metadata   = metadataFlag{}
+					)
+					fs.Var(&recipients, "to", "`recipient` and amount, e.g. alice@example.com:1000, repeatable")
+					fs.Var(&opReturn, "op-return", "`text` of an OP_RETURN output, repeatable to add more parts")
+					fs.Var(metadata, "metadata", "transaction metadata `key=value`, repeatable")
+					return func(ctx context.Context, c *cli, _ []string) error {
+						if len(opReturn) > 0 {
+							recipients = append(recipients, &commands.Recipients{OpReturn: &response.OpReturn{StringParts: opReturn}})
+						}
+						if len(recipients) == 0 {
+							return c.usageError(fs.Usage, "%s: at least one -to or -op-return is required", fs.Name())
+						}
+
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						tx, err := api.SendToRecipients(ctx, &commands.SendToRecipients{Recipients: recipients, Metadata: metadata.metadata()})
+						if err != nil {
+							return err
+						}
+
+						return c.printTransaction(tx)
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printTransactions(txs []*response.Transaction) error {
+	rows := make([][]string, 0, len(txs))
+	for _, tx := range txs {
+		rows = append(rows, []string{tx.ID, tx.TransactionDirection, tx.Status, fmt.Sprint(tx.OutputValue), formatUint(tx.Fee), formatUint(tx.BlockHeight), formatTime(tx.CreatedAt)})
+	}
+	return c.printTable(txs, []string{"ID", "DIRECTION", "STATUS", "VALUE", "FEE", "BLOCK", "CREATED AT"}, rows)
+}
+
+func (c *cli) printTransaction(tx *response.Transaction) error {
+	return c.printDetails(tx, []field{
+		{"ID", tx.ID},
+		{"Direction", tx.TransactionDirection},
+		{"Status", tx.Status},
+		{"Value", fmt.Sprint(tx.OutputValue)},
+		{"Fee", formatUint(tx.Fee)},
+		{"Block height", formatUint(tx.BlockHeight)},
+		{"Block hash", tx.BlockHash},
+		{"Draft ID", tx.DraftID},
+		{"Created at", formatTime(tx.CreatedAt)},
+	})
+}
+
+func contactsCommand() *command {
+	return &command{
+		name:    "contacts",
+		summary: "Manage the contacts and invitations of the user",
+		subcommands: []*command{
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the contacts of the user",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						contacts, err := fetchList(ctx, list, api.Contacts, api.AllContacts)
+						if err != nil {
+							return err
+						}
+
+						return c.printContacts(contacts)
+					}
+				},
+			},
+			{
+				name:    "get",
+				args:    "<paymail>",
+				summary: "Show the contact with the paymail",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						contact, err := api.ContactWithPaymail(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return c.printContacts([]*response.Contact{contact})
+					}
+				},
+			},
+			{
+				name:    "upsert",
+				args:    "-name <full name> [-requester <paymail>] [-metadata <key=value> ...] <paymail>",
+				summary: "Add or update the contact with the paymail",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					var (
+						fullName  string
+						requester string
+						metadata  = metadataFlag{}
+					)
+					fs.StringVar(&fullName, "name", "", "full `name` of the contact")
+					fs.StringVar(&requester, "requester", "", "`paymail` of the user the invitation is sent from")
+					fs.Var(metadata, "metadata", "contact metadata `key=value`, repeatable")
+					return func(ctx context.Context, c *cli, args []string) error {
+						if fullName == "" {
+							return c.usageError(fs.Usage, "%s: -name is required", fs.Name())
+						}
+
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						contact, err := api.UpsertContact(ctx, commands.UpsertContact{
+							ContactPaymail:   args[0],
+							FullName:         fullName,
+							Metadata:         metadata,
+							RequesterPaymail: requester,
+						})
+						if err != nil {
+							return err
+						}
+
+						return c.printContacts([]*response.Contact{contact})
+					}
+				},
+			},
+			paymailCommand("remove", "Remove the contact with the paymail", "Contact %s removed", (*cli).removeContact),
+			paymailCommand("unconfirm", "Unconfirm the contact with the paymail", "Contact %s unconfirmed", (*cli).unconfirmContact),
+			paymailCommand("accept", "Accept the invitation from the paymail", "Invitation from %s accepted", (*cli).acceptInvitation),
+			paymailCommand("reject", "Reject the invitation from the paymail", "Invitation from %s rejected", (*cli).rejectInvitation),
+		},
+	}
+}
+
+// paymailCommand returns a command calling fn with the paymail given as the only argument.
+func paymailCommand(name, summary, done string, fn func(c *cli, ctx context.Context, paymail string) error) *command {
+	return &command{
+		name:    name,
+		args:    "<paymail>",
+		summary: summary,
+		nargs:   1,
+		setup: func(*flag.FlagSet) action {
+			return func(ctx context.Context, c *cli, args []string) error {
+				if err := fn(c, ctx, args[0]); err != nil {
+					return err
+				}
+
+				c.printDone(done, args[0])
+				return nil
+			}
+		},
+	}
+}
+
+func (c *cli) removeContact(ctx context.Context, paymail string) error {
+	api, err := c.userAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.RemoveContact(ctx, paymail); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cli) unconfirmContact(ctx context.Context, paymail string) error {
+	api, err := c.userAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.UnconfirmContact(ctx, paymail); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cli) acceptInvitation(ctx context.Context, paymail string) error {
+	api, err := c.userAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.AcceptInvitation(ctx, paymail); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cli) rejectInvitation(ctx context.Context, paymail string) error {
+	api, err := c.userAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.RejectInvitation(ctx, paymail); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cli) printContacts(contacts []*response.Contact) error {
+	rows := make([][]string, 0, len(contacts))
+	for _, contact := range contacts {
+		rows = append(rows, []string{contact.ID, contact.Paymail, contact.FullName, string(contact.Status), formatTime(contact.CreatedAt)})
+	}
+	return c.printTable(contacts, []string{"ID", "PAYMAIL", "NAME", "STATUS", "CREATED AT"}, rows)
+}
+
+func accessKeysCommand() *command {
+	return &command{
+		name:    "access-keys",
+		summary: "Manage the access keys of the user",
+		subcommands: []*command{
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the access keys of the user",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						keys, err := fetchList(ctx, list, api.AccessKeys, api.AllAccessKeys)
+						if err != nil {
+							return err
+						}
+
+						return c.printAccessKeys(keys)
+					}
+				},
+			},
+			{
+				name:    "get",
+				args:    "<id>",
+				summary: "Show the access key with the ID",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						key, err := api.AccessKey(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return c.printAccessKeys([]*response.AccessKey{key})
+					}
+				},
+			},
+			{
+				name:    "generate",
+				args:    "[-metadata <key=value> ...]",
+				summary: "Generate a new access key; its private key is shown only once",
+				setup: func(fs *flag.FlagSet) action {
+					metadata := metadataFlag{}
+					fs.Var(metadata, "metadata", "access key metadata `key=value`, repeatable")
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						key, err := api.GenerateAccessKey(ctx, &commands.GenerateAccessKey{Metadata: metadata.metadata()})
+						if err != nil {
+							return err
+						}
+
+						return c.printDetails(key, []field{
+							{"ID", key.ID},
+							{"Key", key.Key},
+							{"Created at", formatTime(key.CreatedAt)},
+						})
+					}
+				},
+			},
+			{
+				name:    "revoke",
+				args:    "<id>",
+				summary: "Revoke the access key with the ID",
+				nargs:   1,
+				setup: func(*flag.FlagSet) action {
+					return func(ctx context.Context, c *cli, args []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						if err := api.RevokeAccessKey(ctx, args[0]); err != nil {
+							return err
+						}
+
+						c.printDone("Access key %s revoked", args[0])
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printAccessKeys(keys []*response.AccessKey) error {
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{key.ID, formatTime(key.CreatedAt), formatTimePtr(key.RevokedAt)})
+	}
+	return c.printTable(keys, []string{"ID", "CREATED AT", "REVOKED AT"}, rows)
+}
+
+func utxosCommand() *command {
+	return &command{
+		name:    "utxos",
+		summary: "List the unspent transaction outputs of the user",
+		subcommands: []*command{
+			{
+				name:    "list",
+				args:    "[flags]",
+				summary: "List the unspent transaction outputs of the user",
+				setup: func(fs *flag.FlagSet) action {
+					list := addListFlags(fs)
+					return func(ctx context.Context, c *cli, _ []string) error {
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						utxos, err := fetchList(ctx, list, api.UTXOs, api.AllUTXOs)
+						if err != nil {
+							return err
+						}
+
+						return c.printUTXOs(utxos)
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printUTXOs(utxos []*response.Utxo) error {
+	rows := make([][]string, 0, len(utxos))
+	for _, utxo := range utxos {
+		rows = append(rows, []string{utxo.TransactionID, formatUint(utxo.OutputIndex), formatUint(utxo.Satoshis), utxo.Type, formatTime(utxo.CreatedAt)})
+	}
+	return c.printTable(utxos, []string{"TRANSACTION ID", "OUTPUT", "SATOSHIS", "TYPE", "CREATED AT"}, rows)
+}
+
+func merkleRootsCommand() *command {
+	return &command{
+		name:    "merkleroots",
+		summary: "Sync the Merkle roots known to the SPV Wallet",
+		subcommands: []*command{
+			{
+				name:    "sync",
+				args:    "-file <path> [-watch <interval>]",
+				summary: "Sync the Merkle roots into the file, once or every interval until interrupted",
+				setup: func(fs *flag.FlagSet) action {
+					var (
+						path     string
+						interval time.Duration
+					)
+					fs.StringVar(&path, "file", "", "`path` of the Merkle roots file, created if missing")
+					fs.DurationVar(&interval, "watch", 0, "keep syncing every `interval`, e.g. 10m")
+					return func(ctx context.Context, c *cli, _ []string) error {
+						if path == "" {
+							return c.usageError(fs.Usage, "%s: -file is required", fs.Name())
+						}
+
+						api, err := c.userAPI()
+						if err != nil {
+							return err
+						}
+
+						repo, err := merkleroots.NewFileRepository(path)
+						if err != nil {
+							return err
+						}
+						defer repo.Close()
+
+						if interval <= 0 {
+							if err := api.SyncMerkleRoots(ctx, repo); err != nil {
+								return err
+							}
+							return c.printMerkleRootsHeight(repo)
+						}
+
+						syncer := api.StartMerkleRootsSync(ctx, repo, interval,
+							merkleroots.WithOnSyncError(func(err error) {
+								fmt.Fprintf(c.stderr, "spv-wallet: failed to sync merkle roots: %v\n", err)
+							}),
+							merkleroots.WithOnReorg(func(reorg merkleroots.Reorg) {
+								fmt.Fprintf(c.stderr, "spv-wallet: chain reorganization at height %d, %d merkle roots orphaned\n", reorg.Height, len(reorg.Orphaned))
+							}))
+						<-syncer.Done()
+						syncer.Stop()
+
+						return c.printMerkleRootsHeight(repo)
+					}
+				},
+			},
+		},
+	}
+}
+
+func (c *cli) printMerkleRootsHeight(repo *merkleroots.FileRepository) error {
+	height, err := repo.LastMerkleRootHeight()
+	if err != nil {
+		return err
+	}
+
+	synced := struct {
+		LastHeight int    `json:"lastHeight"`
+		LastRoot   string `json:"lastMerkleRoot"`
+	}{height, repo.GetLastMerkleRoot()}
+	return c.printDetails(synced, []field{
+		{"Last height", fmt.Sprint(synced.LastHeight)},
+		{"Last Merkle root", synced.LastRoot},
+	})
+}
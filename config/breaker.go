@@ -0,0 +1,67 @@
+package config
+
+import (
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request fast with an *errors.CircuitOpenError.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to check whether the SPV Wallet recovered.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerPolicy describes when the HTTP client stops sending requests to an SPV Wallet which
+// is down, instead of letting every call wait for the timeout.
+//
+// The circuit opens after FailureThreshold consecutive failed request attempts, i.e. attempts ending with
+// a transport error, such as a timeout, or a 5xx response. While open, requests fail fast with an
+// *errors.CircuitOpenError. After OpenTimeout the circuit half-opens and lets up to HalfOpenProbes requests
+// through: it closes once all of them succeed and opens again as soon as one fails. Any request can serve
+// as a probe, a cheap one such as AdminAPI.Status or UserAPI.SharedConfig being a good fit.
+type CircuitBreakerPolicy struct {
+	FailureThreshold int                         // Number of consecutive failed attempts opening the circuit.
+	OpenTimeout      time.Duration               // Time the circuit stays open before it half-opens.
+	HalfOpenProbes   int                         // Number of successful probes closing the circuit.
+	OnStateChange    func(from, to CircuitState) // Optional callback invoked on every state change; must not block.
+}
+
+// DefaultCircuitBreakerPolicy returns a circuit breaker policy opening the circuit after 5 consecutive
+// failures for 30s, and closing it after a single successful probe.
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenProbes:   1,
+	}
+}
+
+// Validate checks the circuit breaker policy for invalid values.
+func (p *CircuitBreakerPolicy) Validate() error {
+	if p.FailureThreshold < 1 || p.OpenTimeout <= 0 || p.HalfOpenProbes < 1 {
+		return goclienterr.ErrConfigValidationInvalidCircuitBreaker
+	}
+
+	return nil
+}
@@ -2,32 +2,69 @@ package config
 
 import (
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
 	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config holds configuration settings for establishing a connection and handling
 // request details in the application.
 type Config struct {
-	Addr      string            // The base address of the SPV Wallet API.
-	Timeout   time.Duration     // The HTTP requests timeout duration.
-	Transport http.RoundTripper // Custom HTTP transport, allowing optional customization of the HTTP client behavior.
+	Addr           string                // The base address of the SPV Wallet API, or unix:///path/to/socket for an SPV Wallet listening on a Unix domain socket.
+	Failover       *FailoverPolicy       // Optional replicas of the SPV Wallet API to fail over to; only Addr is used when nil. Not supported with a unix:// Addr.
+	Timeout        time.Duration         // The HTTP requests timeout duration.
+	Transport      http.RoundTripper     // Custom HTTP transport, allowing optional customization of the HTTP client behavior.
+	TLS            *TLSConfig            // Optional TLS settings, applied to a clone of the Transport (see TLSTransport).
+	DialContext    DialContextFunc       // Optional dialer of the connections to the SPV Wallet; requires the Transport to be an *http.Transport.
+	Retry          *RetryPolicy          // Optional retry policy; requests are not retried when nil.
+	RateLimit      *RateLimitPolicy      // Optional rate limit; requests are not limited when nil.
+	CircuitBreaker *CircuitBreakerPolicy // Optional circuit breaker; requests always go through when nil.
+	Middleware     []Middleware          // Interceptors of the requests sent to the SPV Wallet API, in registration order.
+
+	TracerProvider trace.TracerProvider // Optional OpenTelemetry tracer provider; API method calls and HTTP requests are traced when set.
+
+	Logger     *slog.Logger // Optional logger of the HTTP requests; nothing is logged when nil.
+	LogOptions LogOptions   // Controls what is logged with the Logger.
+
+	Metrics Metrics // Optional recorder of the client metrics; nothing is recorded when nil.
+
+	tlsTransport *http.Transport // Transport with the TLS settings applied by NewE.
 }
 
 // New creates a new Config instance with optional customizations.
+// It exits the process if the configuration is invalid; use NewE to handle the error instead.
 func New(options ...Option) Config {
+	cfg, err := NewE(options...)
+	if err != nil {
+		log.Fatalf("Error creating configuration: %v", err)
+	}
+	return cfg
+}
+
+// NewE creates a new Config instance with optional customizations, like New,
+// but returns the validation error instead of exiting the process.
+func NewE(options ...Option) (Config, error) {
 	cfg := Config{}
 	for _, opt := range options {
 		opt(&cfg)
 	}
 	cfg.setDefaultValues()
 	if err := cfg.Validate(); err != nil {
-		log.Fatalf("Error creating configuration: %v", err)
+		return Config{}, err
 	}
-	return cfg
+	if cfg.TLS != nil {
+		transport, err := cfg.TLSTransport()
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Transport = transport
+		cfg.tlsTransport = transport.(*http.Transport)
+	}
+	return cfg, nil
 }
 
 // Validate checks the configuration for invalid or missing values.
@@ -36,7 +73,11 @@ func (cfg *Config) Validate() error {
 		return goclienterr.ErrConfigValidationMissingAddress
 	}
 
-	if _, err := url.ParseRequestURI(cfg.Addr); err != nil {
+	addr, err := url.ParseRequestURI(cfg.Addr)
+	if err != nil {
+		return goclienterr.ErrConfigValidationInvalidAddress
+	}
+	if addr.Scheme == UnixScheme && (addr.Path == "" || addr.Host != "") {
 		return goclienterr.ErrConfigValidationInvalidAddress
 	}
 
@@ -44,5 +85,39 @@ func (cfg *Config) Validate() error {
 		return goclienterr.ErrConfigValidationInvalidTimeout
 	}
 
+	if cfg.DialContext != nil || addr.Scheme == UnixScheme {
+		if _, ok := cfg.Transport.(*http.Transport); !ok && cfg.Transport != nil {
+			return goclienterr.ErrConfigValidationInvalidTransport
+		}
+	}
+
+	if cfg.Retry != nil {
+		if err := cfg.Retry.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.RateLimit != nil {
+		if err := cfg.RateLimit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Failover != nil {
+		// Every connection is dialed over the socket of a unix:// address, so the replicas would all reach the same SPV Wallet.
+		if addr.Scheme == UnixScheme {
+			return goclienterr.ErrConfigValidationInvalidFailoverPolicy
+		}
+		if err := cfg.Failover.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CircuitBreaker != nil {
+		if err := cfg.CircuitBreaker.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
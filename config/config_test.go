@@ -1,13 +1,17 @@
 package config_test
 
 import (
+	"io"
+	"log/slog"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
 	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/metrics"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestConfig_New(t *testing.T) {
@@ -17,6 +21,11 @@ func TestConfig_New(t *testing.T) {
 		Proxy:               http.ProxyFromEnvironment,
 	}
 
+	retryPolicy := config.DefaultRetryPolicy()
+	tracerProvider := noop.NewTracerProvider()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	collector := metrics.NewPrometheus()
+
 	tests := []struct {
 		name     string
 		options  []config.Option
@@ -55,6 +64,61 @@ func TestConfig_New(t *testing.T) {
 				Transport: transport,
 			},
 		},
+		{
+			name: "With retry policy",
+			options: []config.Option{
+				config.WithRetryPolicy(retryPolicy),
+			},
+			expected: config.Config{
+				Addr:      "http://localhost:3003",
+				Timeout:   1 * time.Minute,
+				Transport: http.DefaultTransport,
+				Retry:     &retryPolicy,
+			},
+		},
+		{
+			name: "With tracer provider",
+			options: []config.Option{
+				config.WithTracerProvider(tracerProvider),
+			},
+			expected: config.Config{
+				Addr:           "http://localhost:3003",
+				Timeout:        1 * time.Minute,
+				Transport:      http.DefaultTransport,
+				TracerProvider: tracerProvider,
+			},
+		},
+		{
+			name: "With logger",
+			options: []config.Option{
+				config.WithLogger(logger, config.WithLogPayloads(), config.WithMaskedFields("email")),
+			},
+			expected: config.Config{
+				Addr:      "http://localhost:3003",
+				Timeout:   1 * time.Minute,
+				Transport: http.DefaultTransport,
+				Logger:    logger,
+				LogOptions: config.LogOptions{
+					SuccessLevel:     slog.LevelDebug,
+					ClientErrorLevel: slog.LevelWarn,
+					ServerErrorLevel: slog.LevelError,
+					Payloads:         true,
+					MaskedFields:     []string{"email"},
+				},
+			},
+		},
+		{
+			name: "With metrics",
+			options: []config.Option{
+				config.WithMetrics(collector),
+			},
+			expected: config.Config{
+				Addr:      "http://localhost:3003",
+				Timeout:   1 * time.Minute,
+				Transport: http.DefaultTransport,
+				Metrics:   collector,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -102,6 +166,31 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: goclienterr.ErrConfigValidationInvalidAddress,
 		},
+		{
+			name: "Unix socket Addr",
+			cfg: config.Config{
+				Addr:    "unix:///var/run/spv-wallet.sock",
+				Timeout: 30 * time.Second,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unix socket Addr without path",
+			cfg: config.Config{
+				Addr:    "unix://",
+				Timeout: 30 * time.Second,
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidAddress,
+		},
+		{
+			name: "Unix socket Addr with custom round tripper",
+			cfg: config.Config{
+				Addr:      "unix:///var/run/spv-wallet.sock",
+				Timeout:   30 * time.Second,
+				Transport: config.RoundTripFunc(http.DefaultTransport.RoundTrip),
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidTransport,
+		},
 		{
 			name: "Zero Timeout - default 1m",
 			cfg: config.Config{
@@ -120,6 +209,107 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: goclienterr.ErrConfigValidationInvalidTimeout,
 		},
+		{
+			name: "Valid retry policy",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				Retry:     &config.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 2 * time.Second},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Retry policy without attempts",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				Retry:     &config.RetryPolicy{MaxAttempts: 0},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidRetryPolicy,
+		},
+		{
+			name: "Valid rate limit",
+			cfg: config.New(
+				config.WithRateLimit(config.RateLimit{RequestsPerSecond: 10, Burst: 5, MaxInFlight: 4}),
+				config.WithEndpointRateLimit("admin/xpubs", config.RateLimit{RequestsPerSecond: 1, Burst: 1}),
+			),
+			expectedErr: nil,
+		},
+		{
+			name: "Rate limit without burst",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				RateLimit: &config.RateLimitPolicy{Default: config.RateLimit{RequestsPerSecond: 10}},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidRateLimit,
+		},
+		{
+			name:        "Valid replicas",
+			cfg:         config.New(config.WithReplicas("http://wallet-2.example.com", "http://wallet-3.example.com")),
+			expectedErr: nil,
+		},
+		{
+			name:        "Invalid replica address",
+			cfg:         config.Config{Addr: "http://api.example.com", Failover: &config.FailoverPolicy{Replicas: []string{"wallet-2"}, ProbeInterval: time.Second, ProbeTimeout: time.Second}},
+			expectedErr: goclienterr.ErrConfigValidationInvalidFailoverPolicy,
+		},
+		{
+			name: "Replicas with a Unix domain socket address",
+			cfg: config.Config{
+				Addr:     "unix:///var/run/spv-wallet.sock",
+				Failover: &config.FailoverPolicy{Replicas: []string{"http://wallet-2.example.com"}, ProbeInterval: time.Second, ProbeTimeout: time.Second},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidFailoverPolicy,
+		},
+		{
+			name:        "Valid circuit breaker",
+			cfg:         config.New(config.WithCircuitBreaker(config.DefaultCircuitBreakerPolicy())),
+			expectedErr: nil,
+		},
+		{
+			name: "Circuit breaker without probes",
+			cfg: config.Config{
+				Addr:           "http://api.example.com",
+				Timeout:        30 * time.Second,
+				Transport:      http.DefaultTransport,
+				CircuitBreaker: &config.CircuitBreakerPolicy{FailureThreshold: 5, OpenTimeout: time.Second},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidCircuitBreaker,
+		},
+		{
+			name: "Endpoint rate limit with negative in-flight cap",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				RateLimit: &config.RateLimitPolicy{Endpoints: map[string]config.RateLimit{"admin/xpubs": {MaxInFlight: -1}}},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidRateLimit,
+		},
+		{
+			name: "Retry policy with max backoff lower than base backoff",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				Retry:     &config.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Millisecond},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidRetryPolicy,
+		},
+		{
+			name: "Retry policy with jitter out of range",
+			cfg: config.Config{
+				Addr:      "http://api.example.com",
+				Timeout:   30 * time.Second,
+				Transport: http.DefaultTransport,
+				Retry:     &config.RetryPolicy{MaxAttempts: 3, Jitter: 1.5},
+			},
+			expectedErr: goclienterr.ErrConfigValidationInvalidRetryPolicy,
+		},
 	}
 
 	for _, test := range tests {
@@ -129,3 +319,86 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_BaseURL(t *testing.T) {
+	tests := map[string]struct {
+		addr               string
+		expectedURL        string
+		expectedSocketPath string
+	}{
+		"HTTP address": {
+			addr:        "http://api.example.com/api",
+			expectedURL: "http://api.example.com/api",
+		},
+		"Unix socket address": {
+			addr:               "unix:///var/run/spv-wallet.sock",
+			expectedURL:        "http://localhost",
+			expectedSocketPath: "/var/run/spv-wallet.sock",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			cfg := config.New(config.WithAddr(tc.addr))
+
+			// when:
+			got, err := cfg.BaseURL()
+
+			// then:
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedURL, got.String())
+			require.Equal(t, tc.expectedSocketPath, cfg.SocketPath())
+		})
+	}
+}
+
+func TestConfig_WithMiddleware(t *testing.T) {
+	// given:
+	var calls []string
+	record := func(name string) config.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	transport := config.RoundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls = append(calls, "transport")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	// when:
+	cfg := config.New(
+		config.WithTransport(transport),
+		config.WithMiddleware(record("first")),
+		config.WithMiddleware(record("second"), record("third")),
+	)
+
+	// then:
+	require.Len(t, cfg.Middleware, 3)
+	res, err := config.Chain(cfg.Transport, cfg.Middleware...).RoundTrip(&http.Request{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, []string{"first", "second", "third", "transport"}, calls)
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+		expected   string
+	}{
+		"Success":      {statusCode: http.StatusCreated, expected: "2xx"},
+		"Redirect":     {statusCode: http.StatusFound, expected: "3xx"},
+		"Client error": {statusCode: http.StatusNotFound, expected: "4xx"},
+		"Server error": {statusCode: http.StatusBadGateway, expected: "5xx"},
+		"No response":  {statusCode: 0, expected: "error"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, config.StatusClass(tc.statusCode))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"context"
+	"net"
+	"net/url"
+)
+
+// UnixScheme is the scheme of the addresses of SPV Wallets listening on a Unix domain socket,
+// e.g. "unix:///var/run/spv-wallet.sock".
+const UnixScheme = "unix"
+
+// unixBaseURL is the base URL the API paths are joined to when Addr is a Unix domain socket address.
+const unixBaseURL = "http://localhost"
+
+// DialContextFunc establishes the connections to the SPV Wallet, like net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// BaseURL returns the URL the API paths are joined to: Addr, or http://localhost when Addr is
+// a Unix domain socket address, the requests being sent over the socket regardless of their host.
+func (cfg *Config) BaseURL() (*url.URL, error) {
+	if cfg.SocketPath() != "" {
+		return url.Parse(unixBaseURL)
+	}
+	return url.Parse(cfg.Addr)
+}
+
+// SocketPath returns the path of the Unix domain socket of a unix:// Addr, or an empty string for other addresses.
+func (cfg *Config) SocketPath() string {
+	u, err := url.Parse(cfg.Addr)
+	if err != nil || u.Scheme != UnixScheme {
+		return ""
+	}
+	return u.Path
+}
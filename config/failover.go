@@ -0,0 +1,52 @@
+package config
+
+import (
+	"net/url"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// FailoverPolicy lists the SPV Wallet replicas the client fails over to when Addr is unhealthy.
+//
+// Requests go to the first healthy address, Addr being preferred over the replicas, which are
+// preferred in the listed order. An address becomes unhealthy when a request attempt sent to it fails
+// with a connection error or a 5xx response; the attempt is then sent to the next address if it is safe
+// to do so: always for safe requests (GET, HEAD, OPTIONS), and for the other ones only when the connection
+// couldn't be established. Every ProbeInterval, an unhealthy address is probed in the background with
+// a GET request to ProbePath, and becomes healthy again once it responds without a 5xx status.
+//
+// The API paths are appended to the replica addresses the same way as to Addr, and the requests
+// are signed the same way, so UserAPI and AdminAPI callers don't notice the failover.
+type FailoverPolicy struct {
+	Replicas      []string      // Base addresses of the SPV Wallet replicas, e.g. "https://wallet-2.example.com".
+	ProbeInterval time.Duration // Time between the probes of an unhealthy address.
+	ProbeTimeout  time.Duration // Timeout of a single probe.
+	ProbePath     string        // Path of the probe request, relative to the base address.
+}
+
+// DefaultFailoverPolicy returns a failover policy with the replicas, probing unhealthy addresses
+// every 30s with a GET /health request timing out after 5s.
+func DefaultFailoverPolicy(replicas ...string) FailoverPolicy {
+	return FailoverPolicy{
+		Replicas:      replicas,
+		ProbeInterval: 30 * time.Second,
+		ProbeTimeout:  5 * time.Second,
+		ProbePath:     "/health",
+	}
+}
+
+// Validate checks the failover policy for invalid values.
+func (p *FailoverPolicy) Validate() error {
+	if len(p.Replicas) == 0 || p.ProbeInterval <= 0 || p.ProbeTimeout <= 0 {
+		return goclienterr.ErrConfigValidationInvalidFailoverPolicy
+	}
+
+	for _, addr := range p.Replicas {
+		if _, err := url.ParseRequestURI(addr); err != nil {
+			return goclienterr.ErrConfigValidationInvalidFailoverPolicy
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// fileSettings holds the settings loaded by FromFile and FromEnv. Durations are
+// strings parsed with time.ParseDuration, e.g. "30s".
+type fileSettings struct {
+	Addr     string         `json:"addr" yaml:"addr"`
+	Replicas []string       `json:"replicas" yaml:"replicas"`
+	Timeout  string         `json:"timeout" yaml:"timeout"`
+	Proxy    string         `json:"proxy" yaml:"proxy"`
+	TLS      *tlsSettings   `json:"tls" yaml:"tls"`
+	Retry    *retrySettings `json:"retry" yaml:"retry"`
+}
+
+type tlsSettings struct {
+	CAFile             string   `json:"caFile" yaml:"caFile"`
+	CertFile           string   `json:"certFile" yaml:"certFile"`
+	KeyFile            string   `json:"keyFile" yaml:"keyFile"`
+	MinVersion         string   `json:"minVersion" yaml:"minVersion"`
+	ServerName         string   `json:"serverName" yaml:"serverName"`
+	InsecureSkipVerify bool     `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+	PinnedPublicKeys   []string `json:"pinnedPublicKeys" yaml:"pinnedPublicKeys"`
+}
+
+// retrySettings override the DefaultRetryPolicy values which are set.
+type retrySettings struct {
+	MaxAttempts          *int     `json:"maxAttempts" yaml:"maxAttempts"`
+	BaseBackoff          string   `json:"baseBackoff" yaml:"baseBackoff"`
+	MaxBackoff           string   `json:"maxBackoff" yaml:"maxBackoff"`
+	Jitter               *float64 `json:"jitter" yaml:"jitter"`
+	RetryableStatusCodes []int    `json:"retryableStatusCodes" yaml:"retryableStatusCodes"`
+	HonorRetryAfter      *bool    `json:"honorRetryAfter" yaml:"honorRetryAfter"`
+	RetryNonIdempotent   *bool    `json:"retryNonIdempotent" yaml:"retryNonIdempotent"`
+}
+
+// FromFile loads the configuration from a JSON (.json) or YAML (.yaml, .yml) file, e.g.:
+//
+//	addr: https://wallet.example.com
+//	replicas:
+//	  - https://wallet-2.example.com
+//	timeout: 30s
+//	proxy: http://proxy.example.com:8080
+//	tls:
+//	  caFile: /etc/spv-wallet/ca.pem
+//	  certFile: /etc/spv-wallet/client.pem
+//	  keyFile: /etc/spv-wallet/client-key.pem
+//	  minVersion: "1.3"
+//	retry:
+//	  maxAttempts: 5
+//	  baseBackoff: 200ms
+//
+// Unknown fields are rejected. The retry settings override the DefaultRetryPolicy values; requests are
+// not retried when the retry section is missing. The options are applied on top of the loaded settings.
+func FromFile(path string, options ...Option) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", goclienterr.ErrConfigLoad, err)
+	}
+
+	var settings fileSettings
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&settings)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&settings)
+	default:
+		return Config{}, fmt.Errorf("%w: unsupported file format %q", goclienterr.ErrConfigLoad, ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: failed to decode %s: %w", goclienterr.ErrConfigLoad, path, err)
+	}
+
+	return settings.config(options)
+}
+
+// FromEnv loads the configuration from the environment variables with the prefix, e.g. for the "SPVWALLET" prefix:
+//
+//	SPVWALLET_ADDR, SPVWALLET_REPLICAS (comma separated), SPVWALLET_TIMEOUT, SPVWALLET_PROXY,
+//	SPVWALLET_TLS_CA_FILE, SPVWALLET_TLS_CERT_FILE, SPVWALLET_TLS_KEY_FILE, SPVWALLET_TLS_MIN_VERSION,
+//	SPVWALLET_TLS_SERVER_NAME, SPVWALLET_TLS_INSECURE_SKIP_VERIFY, SPVWALLET_TLS_PINNED_PUBLIC_KEYS (comma separated),
+//	SPVWALLET_RETRY_MAX_ATTEMPTS, SPVWALLET_RETRY_BASE_BACKOFF, SPVWALLET_RETRY_MAX_BACKOFF,
+//	SPVWALLET_RETRY_JITTER, SPVWALLET_RETRY_STATUS_CODES (comma separated),
+//	SPVWALLET_RETRY_HONOR_RETRY_AFTER, SPVWALLET_RETRY_NON_IDEMPOTENT.
+//
+// The settings have the same meaning as in FromFile; requests are retried when any of the retry variables is set.
+// The options are applied on top of the loaded settings.
+func FromEnv(prefix string, options ...Option) (Config, error) {
+	env := envReader{prefix: prefix}
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		env.prefix += "_"
+	}
+
+	settings := fileSettings{
+		Addr:     env.string("ADDR"),
+		Replicas: env.strings("REPLICAS"),
+		Timeout:  env.string("TIMEOUT"),
+		Proxy:    env.string("PROXY"),
+	}
+
+	settings.TLS = &tlsSettings{
+		CAFile:             env.string("TLS_CA_FILE"),
+		CertFile:           env.string("TLS_CERT_FILE"),
+		KeyFile:            env.string("TLS_KEY_FILE"),
+		MinVersion:         env.string("TLS_MIN_VERSION"),
+		ServerName:         env.string("TLS_SERVER_NAME"),
+		InsecureSkipVerify: ptrValue(env.bool("TLS_INSECURE_SKIP_VERIFY")),
+		PinnedPublicKeys:   env.strings("TLS_PINNED_PUBLIC_KEYS"),
+	}
+
+	retryEnv := retrySettings{
+		MaxAttempts:          env.int("RETRY_MAX_ATTEMPTS"),
+		BaseBackoff:          env.string("RETRY_BASE_BACKOFF"),
+		MaxBackoff:           env.string("RETRY_MAX_BACKOFF"),
+		Jitter:               env.float("RETRY_JITTER"),
+		RetryableStatusCodes: env.ints("RETRY_STATUS_CODES"),
+		HonorRetryAfter:      env.bool("RETRY_HONOR_RETRY_AFTER"),
+		RetryNonIdempotent:   env.bool("RETRY_NON_IDEMPOTENT"),
+	}
+	if env.err != nil {
+		return Config{}, env.err
+	}
+	if env.retrySet {
+		settings.Retry = &retryEnv
+	}
+
+	return settings.config(options)
+}
+
+// config turns the settings into options, applied before the given ones.
+func (s *fileSettings) config(options []Option) (Config, error) {
+	var loaded []Option
+	if s.Addr != "" {
+		loaded = append(loaded, WithAddr(s.Addr))
+	}
+	if len(s.Replicas) > 0 {
+		loaded = append(loaded, WithReplicas(s.Replicas...))
+	}
+
+	if s.Timeout != "" {
+		timeout, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: invalid timeout: %w", goclienterr.ErrConfigLoad, err)
+		}
+		loaded = append(loaded, WithTimeout(timeout))
+	}
+
+	transport, err := s.transport()
+	if err != nil {
+		return Config{}, err
+	}
+	if transport != nil {
+		loaded = append(loaded, WithTransport(transport))
+	}
+
+	if s.TLS != nil {
+		tlsOptions, err := s.TLS.options()
+		if err != nil {
+			return Config{}, err
+		}
+		loaded = append(loaded, tlsOptions...)
+	}
+
+	if s.Retry != nil {
+		policy, err := s.Retry.policy()
+		if err != nil {
+			return Config{}, err
+		}
+		loaded = append(loaded, WithRetryPolicy(policy))
+	}
+
+	return NewE(append(loaded, options...)...)
+}
+
+// transport returns the transport with the proxy, nil if none is set.
+func (s *fileSettings) transport() (*http.Transport, error) {
+	if s.Proxy == "" {
+		return nil, nil
+	}
+
+	proxy, err := url.Parse(s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid proxy: %w", goclienterr.ErrConfigLoad, err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	return transport, nil
+}
+
+func (s *tlsSettings) options() ([]Option, error) {
+	var options []Option
+	if s.CAFile != "" {
+		options = append(options, WithCAFile(s.CAFile))
+	}
+	if s.CertFile != "" || s.KeyFile != "" {
+		options = append(options, WithClientCertificateFiles(s.CertFile, s.KeyFile))
+	}
+	if s.MinVersion != "" {
+		version, ok := tlsVersions[s.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid TLS min version %q, expected one of 1.0, 1.1, 1.2, 1.3", goclienterr.ErrConfigLoad, s.MinVersion)
+		}
+		options = append(options, WithMinTLSVersion(version))
+	}
+	if s.ServerName != "" {
+		options = append(options, WithTLSServerName(s.ServerName))
+	}
+	if s.InsecureSkipVerify {
+		options = append(options, WithInsecureSkipVerify())
+	}
+	if len(s.PinnedPublicKeys) > 0 {
+		options = append(options, WithPinnedPublicKeys(s.PinnedPublicKeys...))
+	}
+	return options, nil
+}
+
+func (s *retrySettings) policy() (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+	if s.MaxAttempts != nil {
+		policy.MaxAttempts = *s.MaxAttempts
+	}
+	if s.BaseBackoff != "" {
+		backoff, err := time.ParseDuration(s.BaseBackoff)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("%w: invalid retry base backoff: %w", goclienterr.ErrConfigLoad, err)
+		}
+		policy.BaseBackoff = backoff
+	}
+	if s.MaxBackoff != "" {
+		backoff, err := time.ParseDuration(s.MaxBackoff)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("%w: invalid retry max backoff: %w", goclienterr.ErrConfigLoad, err)
+		}
+		policy.MaxBackoff = backoff
+	}
+	if s.Jitter != nil {
+		policy.Jitter = *s.Jitter
+	}
+	if s.RetryableStatusCodes != nil {
+		policy.RetryableStatusCodes = s.RetryableStatusCodes
+	}
+	if s.HonorRetryAfter != nil {
+		policy.HonorRetryAfter = *s.HonorRetryAfter
+	}
+	if s.RetryNonIdempotent != nil {
+		policy.RetryNonIdempotent = *s.RetryNonIdempotent
+	}
+	return policy, nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// envReader reads the prefixed environment variables, keeping the first parsing error.
+type envReader struct {
+	prefix   string
+	err      error
+	retrySet bool
+}
+
+func (e *envReader) lookup(name string) (string, bool) {
+	value, ok := os.LookupEnv(e.prefix + name)
+	if ok && strings.HasPrefix(name, "RETRY_") {
+		e.retrySet = true
+	}
+	return value, ok
+}
+
+func (e *envReader) string(name string) string {
+	value, _ := e.lookup(name)
+	return value
+}
+
+func (e *envReader) bool(name string) *bool {
+	return parseEnv(e, name, strconv.ParseBool)
+}
+
+func (e *envReader) int(name string) *int {
+	return parseEnv(e, name, strconv.Atoi)
+}
+
+func (e *envReader) float(name string) *float64 {
+	return parseEnv(e, name, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
+
+func (e *envReader) strings(name string) []string {
+	value, ok := e.lookup(name)
+	if !ok {
+		return nil
+	}
+
+	fields := strings.Split(value, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+func (e *envReader) ints(name string) []int {
+	value, ok := e.lookup(name)
+	if !ok {
+		return nil
+	}
+
+	ints := []int{}
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			e.fail(name, err)
+			return nil
+		}
+		ints = append(ints, n)
+	}
+	return ints
+}
+
+func (e *envReader) fail(name string, err error) {
+	if e.err == nil {
+		e.err = fmt.Errorf("%w: invalid %s%s: %w", goclienterr.ErrConfigLoad, e.prefix, name, err)
+	}
+}
+
+func parseEnv[T any](e *envReader, name string, parse func(string) (T, error)) *T {
+	value, ok := e.lookup(name)
+	if !ok {
+		return nil
+	}
+
+	v, err := parse(value)
+	if err != nil {
+		e.fail(name, err)
+		return nil
+	}
+	return &v
+}
+
+func ptrValue[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
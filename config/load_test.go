@@ -0,0 +1,232 @@
+package config_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewE(t *testing.T) {
+	// when:
+	cfg, err := config.NewE(config.WithAddr("invalid-url"))
+
+	// then:
+	require.ErrorIs(t, err, goclienterr.ErrConfigValidationInvalidAddress)
+	require.Equal(t, config.Config{}, cfg)
+}
+
+func TestFromFile(t *testing.T) {
+	retryPolicy := config.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = 5
+	retryPolicy.BaseBackoff = 200 * time.Millisecond
+	retryPolicy.RetryNonIdempotent = true
+
+	tests := map[string]struct {
+		name          string
+		content       string
+		expectedAddr  string
+		expectedRetry *config.RetryPolicy
+		expectedErr   error
+	}{
+		"JSON file": {
+			name:          "config.json",
+			content:       `{"addr":"https://wallet.example.com","timeout":"30s","retry":{"maxAttempts":5,"baseBackoff":"200ms","retryNonIdempotent":true}}`,
+			expectedAddr:  "https://wallet.example.com",
+			expectedRetry: &retryPolicy,
+		},
+		"YAML file": {
+			name:          "config.yaml",
+			content:       "addr: https://wallet.example.com\ntimeout: 30s\nretry:\n  maxAttempts: 5\n  baseBackoff: 200ms\n  retryNonIdempotent: true\n",
+			expectedAddr:  "https://wallet.example.com",
+			expectedRetry: &retryPolicy,
+		},
+		"YAML file without retry section": {
+			name:         "config.yml",
+			content:      "addr: https://wallet.example.com\ntimeout: 30s\n",
+			expectedAddr: "https://wallet.example.com",
+		},
+		"Unknown field": {
+			name:        "config.json",
+			content:     `{"address":"https://wallet.example.com"}`,
+			expectedErr: goclienterr.ErrConfigLoad,
+		},
+		"Unsupported format": {
+			name:        "config.toml",
+			content:     `addr = "https://wallet.example.com"`,
+			expectedErr: goclienterr.ErrConfigLoad,
+		},
+		"Invalid timeout": {
+			name:        "config.yaml",
+			content:     "timeout: 30\n",
+			expectedErr: goclienterr.ErrConfigLoad,
+		},
+		"Invalid address": {
+			name:        "config.yaml",
+			content:     "addr: wallet.example.com\ntimeout: 30s\n",
+			expectedErr: goclienterr.ErrConfigValidationInvalidAddress,
+		},
+		"Invalid retry policy": {
+			name:        "config.yaml",
+			content:     "timeout: 30s\nretry:\n  maxAttempts: 0\n",
+			expectedErr: goclienterr.ErrConfigValidationInvalidRetryPolicy,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			path := filepath.Join(t.TempDir(), tc.name)
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			// when:
+			cfg, err := config.FromFile(path)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+			require.Equal(t, tc.expectedAddr, cfg.Addr)
+			require.Equal(t, 30*time.Second, cfg.Timeout)
+			require.Equal(t, tc.expectedRetry, cfg.Retry)
+			require.Equal(t, http.DefaultTransport, cfg.Transport)
+		})
+	}
+
+	t.Run("Missing file", func(t *testing.T) {
+		// when:
+		_, err := config.FromFile(filepath.Join(t.TempDir(), "config.yaml"))
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrConfigLoad)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Options applied on top of the file", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("addr: https://wallet.example.com\ntimeout: 30s\n"), 0o600))
+
+		// when:
+		cfg, err := config.FromFile(path, config.WithTimeout(time.Second))
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, time.Second, cfg.Timeout)
+	})
+}
+
+func TestFromFile_Transport(t *testing.T) {
+	t.Run("Proxy", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("proxy: http://proxy.example.com:8080\n"), 0o600))
+
+		// when:
+		cfg, err := config.FromFile(path)
+
+		// then:
+		require.NoError(t, err)
+		transport, ok := cfg.Transport.(*http.Transport)
+		require.True(t, ok)
+		proxy, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "https://wallet.example.com", nil))
+		require.NoError(t, err)
+		require.Equal(t, &url.URL{Scheme: "http", Host: "proxy.example.com:8080"}, proxy)
+	})
+
+	t.Run("TLS CA file", func(t *testing.T) {
+		// given:
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		caFile := filepath.Join(dir, "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("addr: "+server.URL+"\ntls:\n  caFile: "+caFile+"\n  serverName: example.com\n"), 0o600))
+
+		// when:
+		cfg, err := config.FromFile(path)
+
+		// then:
+		require.NoError(t, err)
+		res, err := (&http.Client{Transport: cfg.Transport}).Get(cfg.Addr)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+
+	t.Run("TLS CA file without certificates", func(t *testing.T) {
+		// given:
+		dir := t.TempDir()
+		caFile := filepath.Join(dir, "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("tls:\n  caFile: "+caFile+"\n"), 0o600))
+
+		// when:
+		_, err := config.FromFile(path)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrConfigValidationInvalidTLS)
+	})
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Run("Variables with prefix", func(t *testing.T) {
+		// given:
+		t.Setenv("SPVWALLET_ADDR", "https://wallet.example.com")
+		t.Setenv("SPVWALLET_REPLICAS", "https://wallet-2.example.com, https://wallet-3.example.com")
+		t.Setenv("SPVWALLET_TIMEOUT", "15s")
+		t.Setenv("SPVWALLET_PROXY", "http://proxy.example.com:8080")
+		t.Setenv("SPVWALLET_RETRY_MAX_ATTEMPTS", "4")
+		t.Setenv("SPVWALLET_RETRY_STATUS_CODES", "503, 504")
+
+		// when:
+		cfg, err := config.FromEnv("SPVWALLET")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, "https://wallet.example.com", cfg.Addr)
+		require.Equal(t, 15*time.Second, cfg.Timeout)
+		require.Equal(t, []string{"https://wallet-2.example.com", "https://wallet-3.example.com"}, cfg.Failover.Replicas)
+		require.IsType(t, &http.Transport{}, cfg.Transport)
+
+		expectedRetry := config.DefaultRetryPolicy()
+		expectedRetry.MaxAttempts = 4
+		expectedRetry.RetryableStatusCodes = []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+		require.Equal(t, &expectedRetry, cfg.Retry)
+	})
+
+	t.Run("No variables set", func(t *testing.T) {
+		// when:
+		cfg, err := config.FromEnv("SPVWALLET_UNSET")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, config.New(), cfg)
+	})
+
+	t.Run("Invalid variable", func(t *testing.T) {
+		// given:
+		t.Setenv("SPVWALLET_RETRY_JITTER", "a lot")
+
+		// when:
+		_, err := config.FromEnv("SPVWALLET_")
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrConfigLoad)
+		require.ErrorContains(t, err, "SPVWALLET_RETRY_JITTER")
+	})
+}
@@ -0,0 +1,54 @@
+package config
+
+import "log/slog"
+
+// LogOptions controls what the client logs through the logger set with WithLogger.
+// Every HTTP request attempt produces one record with the request method and path,
+// the response status, the duration and, for failed requests, the SPV Wallet error code.
+type LogOptions struct {
+	SuccessLevel     slog.Level // Level of the records of 1xx-3xx responses.
+	ClientErrorLevel slog.Level // Level of the records of 4xx responses.
+	ServerErrorLevel slog.Level // Level of the records of 5xx responses and transport failures.
+	Payloads         bool       // Log the request and response headers and bodies, with secrets redacted.
+	MaskedFields     []string   // Names of additional JSON fields, e.g. metadata keys, masked in the logged bodies.
+}
+
+// DefaultLogOptions returns the options used by WithLogger: successful requests are logged
+// at debug level, 4xx responses at warn level, 5xx responses and transport failures at error level,
+// and payloads are not logged.
+func DefaultLogOptions() LogOptions {
+	return LogOptions{
+		SuccessLevel:     slog.LevelDebug,
+		ClientErrorLevel: slog.LevelWarn,
+		ServerErrorLevel: slog.LevelError,
+	}
+}
+
+// LogOption customizes the LogOptions.
+type LogOption func(*LogOptions)
+
+// WithLogLevels sets the levels of the records of successful requests, 4xx responses,
+// and 5xx responses or transport failures.
+func WithLogLevels(success, clientError, serverError slog.Level) LogOption {
+	return func(o *LogOptions) {
+		o.SuccessLevel = success
+		o.ClientErrorLevel = clientError
+		o.ServerErrorLevel = serverError
+	}
+}
+
+// WithLogPayloads enables logging of the request and response headers and bodies.
+// The X-Auth-* headers, extended private keys, private access keys and webhook tokens are always
+// redacted; use WithMaskedFields to mask other sensitive fields, such as transaction metadata.
+func WithLogPayloads() LogOption {
+	return func(o *LogOptions) {
+		o.Payloads = true
+	}
+}
+
+// WithMaskedFields masks the JSON fields with the given names, at any depth, in the logged bodies.
+func WithMaskedFields(fields ...string) LogOption {
+	return func(o *LogOptions) {
+		o.MaskedFields = append(o.MaskedFields, fields...)
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics records the activity of the client from its point of view, e.g. to alert on the
+// SPV Wallet latency and error rates. The endpoint is the API a request was sent through,
+// e.g. "user/transactions", and is empty for requests not sent by UserAPI or AdminAPI.
+//
+// The methods are called synchronously while a request is sent, so they must be fast
+// and safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest records an HTTP request attempt. The status code is 0 when no response was
+	// received, e.g. the connection was reset, and the duration excludes the request signing.
+	ObserveRequest(endpoint, method string, statusCode int, duration time.Duration)
+	// ObserveRetry records a request attempt being retried by the retry policy.
+	ObserveRetry(endpoint, method string)
+	// ObserveSigning records the time taken to sign a request and the signing error, if any.
+	ObserveSigning(duration time.Duration, err error)
+}
+
+// StatusClass returns the class of the status code recorded by Metrics.ObserveRequest:
+// "1xx" to "5xx", or "error" when no response was received.
+func StatusClass(statusCode int) string {
+	switch {
+	case statusCode >= http.StatusContinue && statusCode < 600:
+		return strconv.Itoa(statusCode/100) + "xx"
+	default:
+		return "error"
+	}
+}
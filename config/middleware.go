@@ -0,0 +1,40 @@
+package config
+
+import "net/http"
+
+// RoundTripFunc is an adapter allowing the use of an ordinary function as an http.RoundTripper.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware intercepts the HTTP requests sent to the SPV Wallet API. It receives the next
+// http.RoundTripper of the chain and returns one which may add headers to the request, observe
+// the response and its latency, or short-circuit the request by returning a response or an error
+// without calling next.
+//
+// Middlewares registered with WithMiddleware are guaranteed to:
+//   - run in the registration order: the first one sees the request first and the response last,
+//     while Config.Transport is the innermost round tripper sending the request,
+//   - receive the request after it has been authenticated, with the auth headers set. The signature
+//     covers only the request body, so headers may be added or changed, but changing the body
+//     makes the SPV Wallet reject the request,
+//   - run for every attempt made by the retry policy, each attempt carrying a fresh signature,
+//   - receive the raw response, before a non-2xx response is turned into an errors.APIError.
+//
+// Like any http.RoundTripper, a middleware must be safe for concurrent use.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Chain wraps the transport with the middlewares, so that the first middleware is the outermost one.
+// A nil transport is replaced with http.DefaultTransport, so the innermost middleware always has a next round tripper.
+func Chain(transport http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+	return transport
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Option defines a function signature for modifying a Config.
@@ -29,3 +32,169 @@ func WithTransport(transport http.RoundTripper) Option {
 		cfg.Transport = transport
 	}
 }
+
+// WithReplicas sets the base addresses of the SPV Wallet replicas the client fails over to when Addr is unhealthy,
+// using the DefaultFailoverPolicy probing settings.
+func WithReplicas(addrs ...string) Option {
+	return func(cfg *Config) {
+		policy := DefaultFailoverPolicy(addrs...)
+		cfg.Failover = &policy
+	}
+}
+
+// WithFailoverPolicy sets the failover policy in the configuration.
+func WithFailoverPolicy(policy FailoverPolicy) Option {
+	return func(cfg *Config) {
+		cfg.Failover = &policy
+	}
+}
+
+// WithCAFile trusts the CA certificates of the PEM file, instead of the system ones, when verifying
+// the SPV Wallet certificate. The file is read again when it changes. May be used more than once.
+func WithCAFile(path string) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.CAFiles = append(c.CAFiles, path)
+	})
+}
+
+// WithCAPEM trusts the PEM encoded CA certificates, instead of the system ones, when verifying
+// the SPV Wallet certificate. May be used more than once.
+func WithCAPEM(pem []byte) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.CAPEM = append(c.CAPEM, pem)
+	})
+}
+
+// WithClientCertificateFiles sets the client certificate presented to the SPV Wallet for mutual TLS.
+// The files are read again when they change.
+func WithClientCertificateFiles(certFile, keyFile string) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.CertFile, c.KeyFile = certFile, keyFile
+	})
+}
+
+// WithClientCertificatePEM sets the PEM encoded client certificate presented to the SPV Wallet for mutual TLS.
+func WithClientCertificatePEM(certPEM, keyPEM []byte) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.CertPEM, c.KeyPEM = certPEM, keyPEM
+	})
+}
+
+// WithMinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS13.
+func WithMinTLSVersion(version uint16) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.MinVersion = version
+	})
+}
+
+// WithTLSServerName sets the name the SPV Wallet certificate is verified against, instead of the host of the address.
+func WithTLSServerName(name string) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.ServerName = name
+	})
+}
+
+// WithInsecureSkipVerify disables the verification of the SPV Wallet certificate chain.
+// Use it only for test instances, or together with WithPinnedPublicKeys.
+func WithInsecureSkipVerify() Option {
+	return withTLS(func(c *TLSConfig) {
+		c.InsecureSkipVerify = true
+	})
+}
+
+// WithPinnedPublicKeys accepts only SPV Wallet certificate chains containing one of the public keys,
+// given as base64 encoded SHA-256 hashes of the subject public key info. See PublicKeyPin.
+func WithPinnedPublicKeys(pins ...string) Option {
+	return withTLS(func(c *TLSConfig) {
+		c.PinnedPublicKeys = append(c.PinnedPublicKeys, pins...)
+	})
+}
+
+func withTLS(fn func(c *TLSConfig)) Option {
+	return func(cfg *Config) {
+		if cfg.TLS == nil {
+			cfg.TLS = &TLSConfig{}
+		}
+		fn(cfg.TLS)
+	}
+}
+
+// WithDialContext sets the dialer of the connections to the SPV Wallet, e.g. to reach it through
+// a sidecar. For a unix:// address, it is called with the "unix" network and the socket path.
+func WithDialContext(dial DialContextFunc) Option {
+	return func(cfg *Config) {
+		cfg.DialContext = dial
+	}
+}
+
+// WithRetryPolicy sets the retry policy in the configuration.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(cfg *Config) {
+		cfg.Retry = &policy
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker policy in the configuration.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return func(cfg *Config) {
+		cfg.CircuitBreaker = &policy
+	}
+}
+
+// WithRateLimit sets the default rate limit of the requests sent to the SPV Wallet API.
+func WithRateLimit(limit RateLimit) Option {
+	return func(cfg *Config) {
+		if cfg.RateLimit == nil {
+			cfg.RateLimit = &RateLimitPolicy{}
+		}
+		cfg.RateLimit.Default = limit
+	}
+}
+
+// WithEndpointRateLimit overrides the rate limit of the requests sent through the endpoint, e.g. "admin/xpubs".
+func WithEndpointRateLimit(endpoint string, limit RateLimit) Option {
+	return func(cfg *Config) {
+		if cfg.RateLimit == nil {
+			cfg.RateLimit = &RateLimitPolicy{}
+		}
+		if cfg.RateLimit.Endpoints == nil {
+			cfg.RateLimit.Endpoints = make(map[string]RateLimit)
+		}
+		cfg.RateLimit.Endpoints[endpoint] = limit
+	}
+}
+
+// WithMiddleware appends the middlewares to the chain intercepting requests sent to the SPV Wallet API.
+// See Middleware for the ordering guarantees.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(cfg *Config) {
+		cfg.Middleware = append(cfg.Middleware, middlewares...)
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to trace the API method calls
+// and the HTTP requests sent to the SPV Wallet API, propagating the W3C trace context headers.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *Config) {
+		cfg.TracerProvider = tp
+	}
+}
+
+// WithLogger sets the logger the HTTP requests sent to the SPV Wallet API are logged with,
+// customized with the log options. See LogOptions for what is logged.
+func WithLogger(logger *slog.Logger, opts ...LogOption) Option {
+	return func(cfg *Config) {
+		cfg.Logger = logger
+		cfg.LogOptions = DefaultLogOptions()
+		for _, opt := range opts {
+			opt(&cfg.LogOptions)
+		}
+	}
+}
+
+// WithMetrics sets the recorder of the request counts, latencies, retries and signing durations.
+func WithMetrics(metrics Metrics) Option {
+	return func(cfg *Config) {
+		cfg.Metrics = metrics
+	}
+}
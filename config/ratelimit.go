@@ -0,0 +1,49 @@
+package config
+
+import goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+
+// RateLimit caps the load the client puts on the SPV Wallet, e.g. when creating xPubs or
+// upserting contacts in a loop. Requests over the limit wait, until the request context is done,
+// before they are signed and sent. Every attempt made by the retry policy counts as a request.
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained rate of the token bucket; 0 means no rate limit.
+	Burst             int     // Size of the token bucket, i.e. the number of requests that may be sent at once above the rate.
+	MaxInFlight       int     // Maximum number of requests awaiting a response at the same time; 0 means no cap.
+}
+
+// Validate checks the rate limit for invalid values.
+func (l *RateLimit) Validate() error {
+	if l.RequestsPerSecond < 0 || l.MaxInFlight < 0 {
+		return goclienterr.ErrConfigValidationInvalidRateLimit
+	}
+
+	if l.RequestsPerSecond > 0 && l.Burst < 1 {
+		return goclienterr.ErrConfigValidationInvalidRateLimit
+	}
+
+	return nil
+}
+
+// RateLimitPolicy holds the rate limit of the requests sent to the SPV Wallet API and its
+// overrides for particular endpoints, e.g. "admin/xpubs". Requests sent through an endpoint
+// with an override are limited by the override instead of the default limit, and don't
+// count towards the default limit.
+type RateLimitPolicy struct {
+	Default   RateLimit            // Limit of the requests sent through endpoints without an override.
+	Endpoints map[string]RateLimit // Overrides keyed by the endpoint name, as reported by errors.APIError.Endpoint.
+}
+
+// Validate checks the default limit and the overrides for invalid values.
+func (p *RateLimitPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return err
+	}
+
+	for _, limit := range p.Endpoints {
+		if err := limit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
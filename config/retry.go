@@ -0,0 +1,61 @@
+package config
+
+import (
+	"net/http"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// RetryPolicy describes how the HTTP client retries requests that failed due to
+// transient errors, such as connection resets or 502/503/504 responses.
+//
+// Safe and idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) are retried by default.
+// Non-idempotent requests (POST, PATCH), e.g. recording a transaction, are retried only
+// when RetryNonIdempotent is explicitly enabled. Every attempt is authenticated anew,
+// so the auth nonce and auth time are always fresh.
+type RetryPolicy struct {
+	MaxAttempts          int           // Maximum number of attempts, including the initial request.
+	BaseBackoff          time.Duration // Wait time before the first retry; doubled for every subsequent retry.
+	MaxBackoff           time.Duration // Upper bound of a single wait time, including waits requested via Retry-After.
+	Jitter               float64       // Fraction (0-1) of the wait time that is randomized to spread out retries.
+	RetryableStatusCodes []int         // HTTP response status codes which trigger a retry.
+	HonorRetryAfter      bool          // Wait as long as the Retry-After response header requests, when present.
+	RetryNonIdempotent   bool          // Allow retrying non-idempotent requests (POST, PATCH).
+}
+
+// DefaultRetryPolicy returns the recommended retry policy: up to three attempts with
+// exponential backoff between 100ms and 2s, retrying 429, 502, 503 and 504 responses
+// of idempotent requests and honoring the Retry-After header.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  2 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		HonorRetryAfter: true,
+	}
+}
+
+// Validate checks the retry policy for invalid values.
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return goclienterr.ErrConfigValidationInvalidRetryPolicy
+	}
+
+	if p.BaseBackoff < 0 || p.MaxBackoff < p.BaseBackoff {
+		return goclienterr.ErrConfigValidationInvalidRetryPolicy
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return goclienterr.ErrConfigValidationInvalidRetryPolicy
+	}
+
+	return nil
+}
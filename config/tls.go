@@ -0,0 +1,325 @@
+package config
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// TLSConfig describes the TLS settings of the connections to the SPV Wallet, e.g. one sitting behind mutual TLS.
+// It's applied to a clone of the Transport, which must be an *http.Transport, by New and NewE, or by the
+// UserAPI and AdminAPI constructors when the Config is not created by them (see Config.TLSTransport).
+//
+// The CA and client certificate files are read again on the next TLS handshake after they change,
+// so that rotated certificates are picked up without restarting the application. A file which fails
+// to load is ignored until it changes again, the previously loaded certificates being used meanwhile.
+type TLSConfig struct {
+	CAFiles            []string // PEM files with the CA certificates trusted instead of the system ones.
+	CAPEM              [][]byte // PEM encoded CA certificates trusted instead of the system ones.
+	CertFile           string   // PEM file with the client certificate, used together with KeyFile.
+	KeyFile            string   // PEM file with the private key of the client certificate.
+	CertPEM            []byte   // PEM encoded client certificate, used together with KeyPEM.
+	KeyPEM             []byte   // PEM encoded private key of the client certificate.
+	MinVersion         uint16   // Minimum TLS version, e.g. tls.VersionTLS13; TLS 1.2 when zero.
+	ServerName         string   // Name used to verify the server certificate, instead of the host of the address.
+	InsecureSkipVerify bool     // Skip the verification of the server certificate chain; the pins are still checked.
+	PinnedPublicKeys   []string // Base64 encoded SHA-256 hashes of the subject public key info of the accepted server certificates or their issuers.
+}
+
+// PublicKeyPin returns the pin of the certificate public key, as expected by WithPinnedPublicKeys.
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// TLSTransport returns the Transport with the TLS settings applied, i.e. a clone of the Transport,
+// or of http.DefaultTransport when it's nil, with the TLS settings. The Transport is returned as is
+// when TLS is nil or the Transport was returned by New or NewE, which already applied the TLS settings.
+func (cfg *Config) TLSTransport() (http.RoundTripper, error) {
+	if cfg.TLS == nil {
+		return cfg.Transport, nil
+	}
+	if transport, ok := cfg.Transport.(*http.Transport); ok && cfg.tlsTransport != nil && transport == cfg.tlsTransport {
+		return transport, nil
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	var host string
+	if addr, err := url.Parse(cfg.Addr); err == nil {
+		host = addr.Hostname()
+	}
+	tlsTransport, err := cfg.TLS.apply(transport, host)
+	if err != nil {
+		return nil, err
+	}
+	return tlsTransport, nil
+}
+
+// apply returns a clone of the transport with the TLS settings, verifying the server certificate
+// against the host when it's verified by verifyConnection and the ServerName is not set.
+func (c *TLSConfig) apply(transport http.RoundTripper, host string) (*http.Transport, error) {
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("%w: TLS settings require an *http.Transport", goclienterr.ErrConfigValidationInvalidTransport)
+	}
+
+	tlsConfig, err := c.clientConfig(host)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := base.Clone()
+	clone.TLSClientConfig = tlsConfig
+	return clone, nil
+}
+
+func (c *TLSConfig) clientConfig(host string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // explicitly requested, e.g. for a local test instance
+	}
+	if c.MinVersion != 0 {
+		tlsConfig.MinVersion = c.MinVersion
+	}
+
+	pins, err := decodePins(c.PinnedPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, err := newRootsStore(c.CAFiles, c.CAPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(c.CAFiles) > 0 || len(pins) > 0:
+		// The server certificate is verified by verifyConnection, so that the reloaded CAs are used.
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // verified by verifyConnection
+		serverName := c.ServerName
+		if serverName == "" {
+			serverName = host
+		}
+		tlsConfig.VerifyConnection = verifyConnection(roots, pins, serverName, c.InsecureSkipVerify)
+	case roots != nil:
+		tlsConfig.RootCAs = roots.pool()
+	}
+
+	certs, err := newCertificateStore(c)
+	if err != nil {
+		return nil, err
+	}
+	if certs != nil {
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certs.certificate(), nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func decodePins(encoded []string) (map[[sha256.Size]byte]struct{}, error) {
+	pins := make(map[[sha256.Size]byte]struct{}, len(encoded))
+	for _, pin := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("%w: pinned public key %q is not a base64 encoded SHA-256 hash", goclienterr.ErrConfigValidationInvalidTLS, pin)
+		}
+		pins[[sha256.Size]byte(raw)] = struct{}{}
+	}
+	return pins, nil
+}
+
+// verifyConnection verifies the server certificate chain against the current CAs,
+// or the system ones when roots is nil, and checks that the chain contains a pinned public key.
+//
+// The certificate is verified for the server name sent in the SNI extension, which is empty when
+// connecting to an IP address; serverName, the IP address of Addr in that case, is verified instead.
+func verifyConnection(roots *rootsStore, pins map[[sha256.Size]byte]struct{}, serverName string, skipChainVerify bool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("%w: no server certificate", goclienterr.ErrTLSVerification)
+		}
+
+		chain := cs.PeerCertificates
+		if !skipChainVerify {
+			name := cs.ServerName
+			if name == "" {
+				name = serverName
+			}
+			if name == "" {
+				return fmt.Errorf("%w: no server name to verify the certificate for", goclienterr.ErrTLSVerification)
+			}
+
+			opts := x509.VerifyOptions{DNSName: name, Intermediates: x509.NewCertPool()}
+			if roots != nil {
+				opts.Roots = roots.pool()
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			chains, err := cs.PeerCertificates[0].Verify(opts)
+			if err != nil {
+				return fmt.Errorf("%w: %w", goclienterr.ErrTLSVerification, err)
+			}
+			chain = chains[0]
+		}
+
+		if len(pins) == 0 {
+			return nil
+		}
+		for _, cert := range chain {
+			if _, ok := pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: no pinned public key in the server certificate chain", goclienterr.ErrTLSVerification)
+	}
+}
+
+// rootsStore holds the trusted CA certificates, reloading the files when they change.
+type rootsStore struct {
+	files  fileSet
+	static [][]byte
+
+	mu      sync.Mutex
+	current *x509.CertPool
+}
+
+func newRootsStore(files []string, static [][]byte) (*rootsStore, error) {
+	if len(files) == 0 && len(static) == 0 {
+		return nil, nil
+	}
+
+	s := &rootsStore{files: newFileSet(files...), static: static}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *rootsStore) load() error {
+	pool := x509.NewCertPool()
+	for i, pem := range s.static {
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w: no certificates found in CA PEM #%d", goclienterr.ErrConfigValidationInvalidTLS, i+1)
+		}
+	}
+
+	for _, path := range s.files.paths {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: failed to read CA file: %w", goclienterr.ErrConfigValidationInvalidTLS, err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w: no certificates found in CA file %s", goclienterr.ErrConfigValidationInvalidTLS, path)
+		}
+	}
+
+	s.current = pool
+	return nil
+}
+
+func (s *rootsStore) pool() *x509.CertPool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.files.changed() {
+		_ = s.load() // keep the previous CAs until the files are fixed
+	}
+	return s.current
+}
+
+// certificateStore holds the client certificate, reloading the files when they change.
+type certificateStore struct {
+	files    fileSet
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	current *tls.Certificate
+}
+
+func newCertificateStore(c *TLSConfig) (*certificateStore, error) {
+	switch {
+	case len(c.CertPEM) > 0 || len(c.KeyPEM) > 0:
+		cert, err := tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid client certificate: %w", goclienterr.ErrConfigValidationInvalidTLS, err)
+		}
+		return &certificateStore{current: &cert}, nil
+	case c.CertFile != "" || c.KeyFile != "":
+		s := &certificateStore{files: newFileSet(c.CertFile, c.KeyFile), certFile: c.CertFile, keyFile: c.KeyFile}
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *certificateStore) load() error {
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return fmt.Errorf("%w: failed to load client certificate: %w", goclienterr.ErrConfigValidationInvalidTLS, err)
+	}
+	s.current = &cert
+	return nil
+}
+
+func (s *certificateStore) certificate() *tls.Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.files.changed() {
+		_ = s.load() // keep the previous certificate until the files are fixed
+	}
+	return s.current
+}
+
+// fileSet detects changes of files by their modification time and size.
+type fileSet struct {
+	paths  []string
+	stamps []fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newFileSet(paths ...string) fileSet {
+	f := fileSet{paths: paths, stamps: make([]fileStamp, len(paths))}
+	f.changed()
+	return f
+}
+
+// changed reports whether any of the files changed since the previous call.
+func (f *fileSet) changed() bool {
+	changed := false
+	for i, path := range f.paths {
+		var stamp fileStamp
+		if info, err := os.Stat(path); err == nil {
+			stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		if stamp != f.stamps[i] {
+			f.stamps[i] = stamp
+			changed = true
+		}
+	}
+	return changed
+}
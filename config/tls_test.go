@@ -0,0 +1,335 @@
+package config_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_TLS(t *testing.T) {
+	ca := newTestCA(t)
+	clientCert, clientKey := ca.issue(t, 1)
+	server := newMTLSServer(t, ca)
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	serverPin := config.PublicKeyPin(server.Certificate())
+
+	tests := map[string]struct {
+		options         []config.Option
+		expectedErr     error
+		expectedFailure bool
+	}{
+		"Mutual TLS": {
+			options: []config.Option{config.WithCAPEM(serverCA), config.WithClientCertificatePEM(clientCert, clientKey)},
+		},
+		"Mutual TLS with pinned server public key": {
+			options: []config.Option{config.WithCAPEM(serverCA), config.WithClientCertificatePEM(clientCert, clientKey), config.WithPinnedPublicKeys(serverPin)},
+		},
+		"Pinned server public key without chain verification": {
+			options: []config.Option{config.WithInsecureSkipVerify(), config.WithClientCertificatePEM(clientCert, clientKey), config.WithPinnedPublicKeys(serverPin)},
+		},
+		"Server public key not pinned": {
+			options:     []config.Option{config.WithCAPEM(serverCA), config.WithClientCertificatePEM(clientCert, clientKey), config.WithPinnedPublicKeys(config.PublicKeyPin(ca.cert))},
+			expectedErr: goclienterr.ErrTLSVerification,
+		},
+		"Untrusted server certificate": {
+			options:         []config.Option{config.WithCAPEM(ca.certPEM), config.WithClientCertificatePEM(clientCert, clientKey)},
+			expectedFailure: true,
+		},
+		"Missing client certificate": {
+			options:         []config.Option{config.WithCAPEM(serverCA)},
+			expectedFailure: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			cfg, err := config.NewE(append(tc.options, config.WithAddr(server.URL))...)
+			require.NoError(t, err)
+
+			// when:
+			_, err = get(t, cfg)
+
+			// then:
+			if tc.expectedFailure {
+				require.Error(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+
+	t.Run("TLS settings are applied once", func(t *testing.T) {
+		// given:
+		cfg, err := config.NewE(config.WithAddr(server.URL), config.WithCAPEM(serverCA), config.WithClientCertificatePEM(clientCert, clientKey))
+		require.NoError(t, err)
+
+		// when:
+		transport, err := cfg.TLSTransport()
+
+		// then:
+		require.NoError(t, err)
+		require.Same(t, cfg.Transport, transport)
+	})
+
+	t.Run("TLS settings of a Config literal", func(t *testing.T) {
+		// given:
+		cfg := config.Config{
+			Addr: server.URL,
+			TLS:  &config.TLSConfig{CAPEM: [][]byte{serverCA}, CertPEM: clientCert, KeyPEM: clientKey},
+		}
+
+		// when:
+		transport, err := cfg.TLSTransport()
+		require.NoError(t, err)
+		cfg.Transport = transport
+
+		// then:
+		serial, err := get(t, cfg)
+		require.NoError(t, err)
+		require.Equal(t, "1", serial)
+	})
+
+	t.Run("Minimum TLS version", func(t *testing.T) {
+		// given:
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		server.StartTLS()
+		defer server.Close()
+		cfg, err := config.NewE(config.WithAddr(server.URL), config.WithInsecureSkipVerify(), config.WithMinTLSVersion(tls.VersionTLS13))
+		require.NoError(t, err)
+
+		// when:
+		_, err = get(t, cfg)
+
+		// then:
+		require.ErrorContains(t, err, "protocol version")
+	})
+}
+
+func TestConfig_TLSServerName(t *testing.T) {
+	// given:
+	ca := newTestCA(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{ca.issueServer(t, "wallet.example.com")}, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+
+	tests := map[string]struct {
+		options     []config.Option
+		expectedErr error
+	}{
+		"Certificate for another name than the IP address": {
+			options:     []config.Option{config.WithCAFile(caFile)},
+			expectedErr: goclienterr.ErrTLSVerification,
+		},
+		"Certificate for another name than the IP address with pinned CA": {
+			options:     []config.Option{config.WithCAPEM(ca.certPEM), config.WithPinnedPublicKeys(config.PublicKeyPin(ca.cert))},
+			expectedErr: goclienterr.ErrTLSVerification,
+		},
+		"Certificate for the server name": {
+			options: []config.Option{config.WithCAFile(caFile), config.WithTLSServerName("wallet.example.com")},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			cfg, err := config.NewE(append(tc.options, config.WithAddr(server.URL))...)
+			require.NoError(t, err)
+
+			// when:
+			_, err = get(t, cfg)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestConfig_TLSReload(t *testing.T) {
+	// given:
+	ca := newTestCA(t)
+	server := newMTLSServer(t, ca)
+	dir := t.TempDir()
+	caFile, certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	writeClientCertificate(t, ca, 1, certFile, keyFile, time.Now())
+
+	cfg, err := config.NewE(config.WithAddr(server.URL), config.WithCAFile(caFile), config.WithClientCertificateFiles(certFile, keyFile))
+	require.NoError(t, err)
+	serial, err := get(t, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "1", serial)
+
+	// when:
+	writeClientCertificate(t, ca, 2, certFile, keyFile, time.Now().Add(time.Minute))
+	cfg.Transport.(*http.Transport).CloseIdleConnections()
+
+	// then:
+	serial, err = get(t, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "2", serial)
+}
+
+func TestConfig_TLSValidation(t *testing.T) {
+	tests := map[string]struct {
+		options     []config.Option
+		expectedErr error
+	}{
+		"Transport other than *http.Transport": {
+			options:     []config.Option{config.WithTransport(config.RoundTripFunc(http.DefaultTransport.RoundTrip)), config.WithMinTLSVersion(tls.VersionTLS13)},
+			expectedErr: goclienterr.ErrConfigValidationInvalidTransport,
+		},
+		"Invalid pin": {
+			options:     []config.Option{config.WithPinnedPublicKeys("not-a-pin")},
+			expectedErr: goclienterr.ErrConfigValidationInvalidTLS,
+		},
+		"Missing CA file": {
+			options:     []config.Option{config.WithCAFile(filepath.Join(t.TempDir(), "ca.pem"))},
+			expectedErr: goclienterr.ErrConfigValidationInvalidTLS,
+		},
+		"Invalid client certificate": {
+			options:     []config.Option{config.WithClientCertificatePEM([]byte("cert"), []byte("key"))},
+			expectedErr: goclienterr.ErrConfigValidationInvalidTLS,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			_, err := config.NewE(tc.options...)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+type testCA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key: key}
+}
+
+// issue returns a PEM encoded client certificate with the serial number and its private key.
+func (ca *testCA) issue(t *testing.T, serial int64) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "wallet client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// issueServer returns a server certificate for the DNS names.
+func (ca *testCA) issueServer(t *testing.T, dnsNames ...string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(100),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func writeClientCertificate(t *testing.T, ca *testCA, serial int64, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, serial)
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+// newMTLSServer starts a server requiring client certificates issued by the CA,
+// responding with the serial number of the client certificate.
+func newMTLSServer(t *testing.T, ca *testCA) *httptest.Server {
+	t.Helper()
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Client-Serial", r.TLS.PeerCertificates[0].SerialNumber.String())
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// get sends a request to the address with the transport, returning the serial number of the client certificate.
+func get(t *testing.T, cfg config.Config) (string, error) {
+	t.Helper()
+
+	res, err := (&http.Client{Transport: cfg.Transport, Timeout: 5 * time.Second}).Get(cfg.Addr)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	return res.Header.Get("X-Client-Serial"), nil
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// RequestIDHeader is the response header the request ID of APIError is read from.
+const RequestIDHeader = "X-Request-Id"
+
+// APIError describes a non-2xx response of the SPV Wallet API. Every UserAPI and AdminAPI
+// method failing because of such a response returns an error wrapping *APIError, which can be
+// extracted with errors.As or classified with IsNotFound, IsUnauthorized, IsInsufficientFunds and IsRetryable.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response.
+	Method     string // HTTP method of the request.
+	URL        string // URL the request was sent to.
+	Endpoint   string // API the request was sent through, e.g. "user/transactions"; empty for requests not sent by UserAPI or AdminAPI.
+	Code       string // Error code reported by the SPV Wallet, e.g. "error-unauthorized"; empty if the response body is not an SPV Wallet error.
+	Message    string // Error message reported by the SPV Wallet.
+	RequestID  string // Value of the RequestIDHeader response header, if present.
+	Body       []byte // Raw response body.
+	Err        error  // The underlying *models.SPVError or ErrUnrecognizedAPIResponse.
+}
+
+// Error returns the status code followed by the message of the underlying error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d: %v", e.StatusCode, e.Err)
+}
+
+// Unwrap returns the underlying *models.SPVError or ErrUnrecognizedAPIResponse.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound reports whether err was caused by a 404 Not Found response.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err was caused by a 401 Unauthorized or 403 Forbidden response.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// insufficientFundsCodes are the error codes the SPV Wallet responds with when the user
+// doesn't have enough funds or spendable UTXOs to cover a transaction.
+var insufficientFundsCodes = map[string]bool{
+	"error-funds-not-enough": true, // spverrors.ErrNotEnoughFunds
+	"error-utxos-not-enough": true, // spverrors.ErrNotEnoughUtxos
+}
+
+// IsInsufficientFunds reports whether err was caused by the SPV Wallet rejecting a transaction
+// because the user doesn't have enough funds, i.e. a response with one of the error codes
+// of the SPV Wallet reporting missing funds or spendable UTXOs.
+func IsInsufficientFunds(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return insufficientFundsCodes[apiErr.Code]
+}
+
+// IsRetryable reports whether the failed request may succeed when sent again: err was caused by
+// a 408, 429, 502, 503 or 504 response, a timeout or a connection reset or refused by the server.
+func IsRetryable(err error) bool {
+	if hasStatus(err, http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
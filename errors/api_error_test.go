@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Predicates(t *testing.T) {
+	tests := map[string]struct {
+		err               error
+		notFound          bool
+		unauthorized      bool
+		insufficientFunds bool
+		retryable         bool
+	}{
+		"404 response": {
+			err:      wrap(&goclienterr.APIError{StatusCode: http.StatusNotFound}),
+			notFound: true,
+		},
+		"401 response": {
+			err:          wrap(&goclienterr.APIError{StatusCode: http.StatusUnauthorized, Code: "error-unauthorized"}),
+			unauthorized: true,
+		},
+		"422 response with not enough funds error code": {
+			err:               wrap(&goclienterr.APIError{StatusCode: http.StatusUnprocessableEntity, Code: "error-funds-not-enough"}),
+			insufficientFunds: true,
+		},
+		"422 response with not enough UTXOs error code": {
+			err:               wrap(&goclienterr.APIError{StatusCode: http.StatusUnprocessableEntity, Code: "error-utxos-not-enough"}),
+			insufficientFunds: true,
+		},
+		"422 response with other funds related error code": {
+			err: wrap(&goclienterr.APIError{StatusCode: http.StatusUnprocessableEntity, Code: "error-funds-invalid-amount"}),
+		},
+		"503 response": {
+			err:       wrap(&goclienterr.APIError{StatusCode: http.StatusServiceUnavailable}),
+			retryable: true,
+		},
+		"Connection reset": {
+			err:       wrap(syscall.ECONNRESET),
+			retryable: true,
+		},
+		"Context deadline exceeded": {
+			err:       wrap(context.DeadlineExceeded),
+			retryable: true,
+		},
+		"Other error": {
+			err: errors.New("unexpected failure"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.notFound, goclienterr.IsNotFound(tc.err))
+			require.Equal(t, tc.unauthorized, goclienterr.IsUnauthorized(tc.err))
+			require.Equal(t, tc.insufficientFunds, goclienterr.IsInsufficientFunds(tc.err))
+			require.Equal(t, tc.retryable, goclienterr.IsRetryable(tc.err))
+		})
+	}
+}
+
+func wrap(err error) error {
+	return fmt.Errorf("failed to send HTTP GET request: %w", err)
+}
@@ -0,0 +1,23 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitOpenError is returned without sending the request when the circuit breaker set with
+// config.WithCircuitBreaker is open, or half-open with all the probes already in flight.
+// It matches ErrCircuitOpen with errors.Is.
+type CircuitOpenError struct {
+	RetryAt time.Time // Time the circuit half-opens, or the current time if it is already half-open.
+}
+
+// Error returns the message of ErrCircuitOpen followed by the time the circuit half-opens.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v until %s", ErrCircuitOpen, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
@@ -34,6 +34,31 @@ var (
 	// ErrFailedToFetchMerkleRootsFromAPI is returned when the API fails to fetch merkle roots.
 	ErrFailedToFetchMerkleRootsFromAPI = errors.New("failed to fetch merkle roots from API")
 
+	// ErrMerkleRootNotFound is returned when no Merkle root is stored for the requested block height.
+	ErrMerkleRootNotFound = errors.New("merkle root not found")
+
+	// ErrMerkleRootsOutOfOrder is returned when saved Merkle roots are not sorted in ascending order by block height.
+	ErrMerkleRootsOutOfOrder = errors.New("merkle roots must be sorted in ascending order by block height")
+
+	// ErrMerkleRootConflict is returned when a different Merkle root is already stored for the same block height.
+	ErrMerkleRootConflict = errors.New("a different merkle root is already stored for this block height")
+
+	// ErrMerkleRootsStoreCorrupted is returned when the Merkle roots file contains an unreadable record
+	// other than an incomplete trailing write.
+	ErrMerkleRootsStoreCorrupted = errors.New("merkle roots store is corrupted")
+
+	// ErrInvalidBEEF is returned when a transaction in BEEF format cannot be parsed.
+	ErrInvalidBEEF = errors.New("failed to parse BEEF transaction")
+
+	// ErrInvalidMerklePath is returned when a Merkle path in BUMP format cannot be parsed.
+	ErrInvalidMerklePath = errors.New("failed to parse merkle path")
+
+	// ErrTransactionMissing is returned when a nil transaction record is verified.
+	ErrTransactionMissing = errors.New("missing transaction record")
+
+	// ErrTransactionIDMismatch is returned when the transaction hex doesn't hash to the ID of the transaction record.
+	ErrTransactionIDMismatch = errors.New("transaction hex doesn't match transaction ID")
+
 	// ErrFailedToParseHex is returned when NewTransactionFromHex fails to create a transaction from given hex
 	ErrFailedToParseHex = errors.New("failed to parse hex")
 
@@ -52,6 +77,44 @@ var (
 	// ErrSignTransaction is when TransactionSignedHex fails to sign the transaction
 	ErrSignTransaction = errors.New("failed to sign transaction")
 
+	// ErrNoTransactionSigner is returned when a draft transaction cannot be finalized because
+	// the client was initialized without an xPriv or a custom transaction signer.
+	ErrNoTransactionSigner = errors.New("no transaction signer capable of signing the draft transaction - xPriv or custom signer required")
+
+	// ErrDraftOutputMismatch is returned when a requested output (recipient, amount or OP_RETURN)
+	// is missing from the draft transaction returned by the server.
+	ErrDraftOutputMismatch = errors.New("draft transaction verification: requested output not found in draft")
+
+	// ErrDraftUnexpectedOutput is returned when the draft transaction pays to an output
+	// that was neither requested nor declared as change.
+	ErrDraftUnexpectedOutput = errors.New("draft transaction verification: unexpected output in draft")
+
+	// ErrDraftForeignChangeDestination is returned when a change destination of the draft transaction
+	// is not derived from the user's xPub.
+	ErrDraftForeignChangeDestination = errors.New("draft transaction verification: change destination is not derived from the user's xPub")
+
+	// ErrDraftChangeUnverified is returned when the draft transaction has change destinations
+	// but the client doesn't know the user's xPub to check that they are derived from it.
+	ErrDraftChangeUnverified = errors.New("draft transaction verification: change destinations cannot be verified without the user's xPub")
+
+	// ErrDraftMissing is returned when a nil draft transaction is verified or finalized.
+	ErrDraftMissing = errors.New("draft transaction verification: missing draft transaction")
+
+	// ErrDraftRequestMissing is returned when a draft transaction is finalized for a nil requested transaction config.
+	ErrDraftRequestMissing = errors.New("draft transaction verification: missing requested transaction config")
+
+	// ErrDraftChangeMismatch is returned when the change outputs of the draft transaction
+	// do not add up to the declared change amount.
+	ErrDraftChangeMismatch = errors.New("draft transaction verification: change outputs do not match declared change")
+
+	// ErrDraftFeeMismatch is returned when the fee paid by the draft transaction
+	// differs from the declared fee or cannot be validated against the fee unit.
+	ErrDraftFeeMismatch = errors.New("draft transaction verification: fee does not match declared fee")
+
+	// ErrDraftFeeTooHigh is returned when the fee paid by the draft transaction exceeds
+	// the fee calculated from its fee unit.
+	ErrDraftFeeTooHigh = errors.New("draft transaction verification: fee exceeds fee unit rate")
+
 	// ErrEmptyXprivKey is returned when the xpriv string is empty.
 	ErrEmptyXprivKey = errors.New("key string cannot be empty")
 
@@ -73,6 +136,60 @@ var (
 	// ErrConfigValidationInvalidTransport is returned when the transport is invalid.
 	ErrConfigValidationInvalidTransport = errors.New("configuration validation error: invalid transport")
 
+	// ErrConfigValidationInvalidRetryPolicy is returned when the retry policy is invalid.
+	ErrConfigValidationInvalidRetryPolicy = errors.New("configuration validation error: invalid retry policy")
+
+	// ErrConfigLoad is returned when the configuration can't be loaded from a file or the environment variables.
+	ErrConfigLoad = errors.New("configuration loading error")
+
+	// ErrConfigValidationInvalidRateLimit is returned when the rate limit is invalid.
+	ErrConfigValidationInvalidRateLimit = errors.New("configuration validation error: invalid rate limit")
+
+	// ErrConfigValidationInvalidTLS is returned when the TLS settings are invalid, e.g. a certificate file can't be loaded.
+	ErrConfigValidationInvalidTLS = errors.New("configuration validation error: invalid TLS settings")
+
+	// ErrTLSVerification is returned when the SPV Wallet certificate doesn't match the TLS settings.
+	ErrTLSVerification = errors.New("TLS server certificate verification failed")
+
+	// ErrConfigValidationInvalidFailoverPolicy is returned when the failover policy is invalid.
+	ErrConfigValidationInvalidFailoverPolicy = errors.New("configuration validation error: invalid failover policy")
+
+	// ErrConfigValidationInvalidCircuitBreaker is returned when the circuit breaker policy is invalid.
+	ErrConfigValidationInvalidCircuitBreaker = errors.New("configuration validation error: invalid circuit breaker policy")
+
+	// ErrCircuitOpen is returned when a request is rejected by the open circuit breaker.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
+	// ErrKeystoreExists is returned when creating a keystore at the path of an existing file.
+	ErrKeystoreExists = errors.New("keystore already exists")
+
+	// ErrKeystorePassword is returned when a keystore can't be decrypted with the given password.
+	ErrKeystorePassword = errors.New("invalid keystore password or corrupted keystore")
+
+	// ErrKeystoreFormat is returned when a keystore file is malformed or uses an unsupported version.
+	ErrKeystoreFormat = errors.New("invalid keystore format")
+
+	// ErrKeystoreEntryNotFound is returned when a keystore has no entry with the given name.
+	ErrKeystoreEntryNotFound = errors.New("keystore entry not found")
+
+	// ErrKeystoreEntryNoCredentials is returned when a keystore entry has none of the credentials required by the API client.
+	ErrKeystoreEntryNoCredentials = errors.New("keystore entry has no credentials")
+
+	// ErrInvalidAccessKey is returned when an access key is neither a 32 bytes hex string nor a valid WIF.
+	ErrInvalidAccessKey = errors.New("invalid access key")
+
+	// ErrInvalidMnemonic is returned when a mnemonic phrase has an unsupported number of words or words outside the BIP39 word list.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+	// ErrMnemonicChecksum is returned when the checksum encoded in the last word of a mnemonic phrase doesn't match its words.
+	ErrMnemonicChecksum = errors.New("invalid mnemonic checksum")
+
+	// ErrInvalidEntropy is returned when the entropy of a new mnemonic phrase is not a multiple of 32 within the range of [128, 256] bits.
+	ErrInvalidEntropy = errors.New("invalid entropy: must be a multiple of 32 within the range of [128, 256] bits")
+
+	// ErrUnsupportedNetwork is returned when the keys are requested for an unknown network.
+	ErrUnsupportedNetwork = errors.New("unsupported network")
+
 	// ErrMaxUint32LimitExceeded is returned when the max uint32 value is exceeded.
 	ErrMaxUint32LimitExceeded = errors.New("max uint32 value exceeded")
 
@@ -81,4 +198,11 @@ var (
 
 	// ErrHexHashPartIntParse is returned when the hex hash part fails to parse to int64.
 	ErrHexHashPartIntParse = errors.New("parse hex hash part to int64 failed")
+
+	// ErrWebhookEventContent is returned when the content of a webhook event cannot be decoded
+	// into the model expected by the handler registered for its type.
+	ErrWebhookEventContent = errors.New("webhook event content cannot be decoded")
+
+	// ErrWebhookHandlerPanic is returned when a webhook event handler panics.
+	ErrWebhookHandlerPanic = errors.New("webhook event handler panicked")
 )
@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	"github.com/bitcoin-sv/spv-wallet/models"
 	"github.com/go-resty/resty/v2"
 )
 
@@ -45,6 +46,20 @@ func (a *API) UnsubscribeWebhook(ctx context.Context, cmd *commands.CancelWebhoo
 	return nil
 }
 
+func (a *API) Subscriptions(ctx context.Context) ([]*models.Webhook, error) {
+	var result []*models.Webhook
+	_, err := a.httpClient.
+		R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get(a.url.String())
+	if err != nil {
+		return nil, fmt.Errorf("HTTP response failure: %w", err)
+	}
+
+	return result, nil
+}
+
 func NewAPI(url *url.URL, httpClient *resty.Client) *API {
 	return &API{url: url.JoinPath(route), httpClient: httpClient}
 }
@@ -2,16 +2,26 @@ package webhooks_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	spvwallet "github.com/bitcoin-sv/spv-wallet-go-client"
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
 	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/notifications"
+	"github.com/bitcoin-sv/spv-wallet/models"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 )
 
+var _ notifications.WebhookSubscriber = (*spvwallet.AdminAPI)(nil)
+
 const (
 	webhooksURL = "/api/v1/admin/webhooks/subscriptions"
 	url         = "http://webhook1.com"
@@ -94,3 +104,152 @@ func TestWebhooksAPI_UnsubscribeWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhooksAPI_WebhookSubscriptions(t *testing.T) {
+	subscriptions := []*models.Webhook{
+		{URL: "http://webhook1.com"},
+		{URL: "http://webhook2.com", Banned: true},
+	}
+
+	tests := map[string]struct {
+		responder        httpmock.Responder
+		expectedResponse []*models.Webhook
+		expectedErr      error
+	}{
+		"HTTP GET /api/v1/admin/webhooks/subscriptions response: 200": {
+			expectedResponse: subscriptions,
+			responder:        testutils.NewJSONBodyResponderWithStatusOK(subscriptions),
+		},
+		"HTTP GET /api/v1/admin/webhooks/subscriptions response: 400": {
+			expectedErr: testutils.NewBadRequestSPVError(),
+			responder:   testutils.NewBadRequestSPVErrorResponder(),
+		},
+		"HTTP GET /api/v1/admin/webhooks/subscriptions str response: 500": {
+			expectedErr: errors.ErrUnrecognizedAPIResponse,
+			responder:   testutils.NewInternalServerSPVErrorStringResponder("unexpected internal server failure"),
+		},
+	}
+
+	url := testutils.FullAPIURL(t, webhooksURL)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			wallet, transport := testutils.GivenSPVAdminAPI(t)
+			transport.RegisterResponder(http.MethodGet, url, tc.responder)
+
+			// when:
+			got, err := wallet.WebhookSubscriptions(context.Background())
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedResponse, got)
+		})
+	}
+}
+
+func TestWebhooksAPI_NotificationsWebhook(t *testing.T) {
+	// given:
+	const (
+		webhookURL  = "http://client.example.com/notifications"
+		tokenHeader = "X-Webhook-Token"
+		tokenValue  = "76dd388f-62de-4957-afae-967c3a424bc7"
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wallet, transport := testutils.GivenSPVAdminAPI(t)
+	server := newWebhooksServer()
+	url := testutils.FullAPIURL(t, webhooksURL)
+	transport.RegisterResponder(http.MethodPost, url, server.subscribe)
+	transport.RegisterResponder(http.MethodDelete, url, server.unsubscribe)
+	transport.RegisterResponder(http.MethodGet, url, server.list)
+
+	webhook := notifications.NewWebhook(wallet, webhookURL, notifications.WithToken(tokenHeader, tokenValue), notifications.WithRootContext(ctx))
+	events := make(chan *models.StringEvent, 1)
+	require.NoError(t, notifications.RegisterHandler(webhook, func(event *models.StringEvent) { events <- event }))
+
+	// when:
+	err := webhook.Subscribe(ctx)
+
+	// then:
+	require.NoError(t, err)
+	require.Equal(t, commands.CreateWebhookSubscription{URL: webhookURL, TokenHeader: tokenHeader, TokenValue: tokenValue}, server.subscriptions[webhookURL])
+	subscriptions, err := wallet.WebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*models.Webhook{{URL: webhookURL}}, subscriptions)
+
+	// when:
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, webhookURL, strings.NewReader(`[{"type":"StringEvent","content":{"value":"hello"}}]`))
+	req.Header.Set(tokenHeader, tokenValue)
+	webhook.HTTPHandler().ServeHTTP(rec, req)
+
+	// then:
+	require.Equal(t, http.StatusOK, rec.Code)
+	select {
+	case event := <-events:
+		require.Equal(t, "hello", event.Value)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered to the handler")
+	}
+
+	// when:
+	err = webhook.Unsubscribe(ctx)
+
+	// then:
+	require.NoError(t, err)
+	subscriptions, err = wallet.WebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
+
+// webhooksServer mocks the subscriptions kept by the SPV Wallet Admin Webhooks API.
+type webhooksServer struct {
+	mu            sync.Mutex
+	subscriptions map[string]commands.CreateWebhookSubscription
+}
+
+func newWebhooksServer() *webhooksServer {
+	return &webhooksServer{subscriptions: make(map[string]commands.CreateWebhookSubscription)}
+}
+
+func (s *webhooksServer) subscribe(req *http.Request) (*http.Response, error) {
+	var cmd commands.CreateWebhookSubscription
+	if err := json.NewDecoder(req.Body).Decode(&cmd); err != nil {
+		return testutils.NewBadRequestSPVErrorResponder()(req)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions[cmd.URL] = cmd
+
+	return httpmock.NewStringResponse(http.StatusOK, ""), nil
+}
+
+func (s *webhooksServer) unsubscribe(req *http.Request) (*http.Response, error) {
+	var cmd commands.CancelWebhookSubscription
+	if err := json.NewDecoder(req.Body).Decode(&cmd); err != nil {
+		return testutils.NewBadRequestSPVErrorResponder()(req)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[cmd.URL]; !ok {
+		return testutils.NewResourceNotFoundSPVErrorResponder()(req)
+	}
+	delete(s.subscriptions, cmd.URL)
+
+	return httpmock.NewStringResponse(http.StatusOK, ""), nil
+}
+
+func (s *webhooksServer) list(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks := make([]*models.Webhook, 0, len(s.subscriptions))
+	for url := range s.subscriptions {
+		webhooks = append(webhooks, &models.Webhook{URL: url})
+	}
+
+	return httpmock.NewJsonResponse(http.StatusOK, webhooks)
+}
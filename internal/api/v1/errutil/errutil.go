@@ -1,8 +1,11 @@
 package errutil
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 )
 
 // HTTPErrorFormatter is a utility struct that formats HTTP errors.
@@ -13,7 +16,13 @@ type HTTPErrorFormatter struct {
 }
 
 // Format creates a formatted error message for HTTP requests.
+// A wrapped *errors.APIError is annotated with the API the request was sent through.
 func (h HTTPErrorFormatter) Format(method string) error {
+	var apiErr *goclienterr.APIError
+	if errors.As(h.Err, &apiErr) && apiErr.Endpoint == "" {
+		apiErr.Endpoint = h.API
+	}
+
 	return fmt.Errorf("failed to send HTTP %s request to %s via %s: %w", method, h.Action, h.API, h.Err)
 }
 
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"testing"
 
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/errutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/constants"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,3 +33,18 @@ func TestHTTPErrorFormatter_Format(t *testing.T) {
 	// then:
 	require.Equal(t, got, expectedErr)
 }
+
+func TestHTTPErrorFormatter_FormatAPIError(t *testing.T) {
+	// given:
+	apiErr := &goclienterr.APIError{StatusCode: http.StatusNotFound}
+	formatter := errutil.NewHTTPErrorFormatter(constants.UserXPubsAPI, "retrieve xpub", fmt.Errorf("HTTP response failure: %w", apiErr))
+
+	// when:
+	got := formatter.FormatGetErr()
+
+	// then:
+	var target *goclienterr.APIError
+	require.ErrorAs(t, got, &target)
+	require.Equal(t, constants.UserXPubsAPI, target.Endpoint)
+	require.True(t, goclienterr.IsNotFound(got))
+}
@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"context"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+// PageFetcher retrieves a single page of resources for the given query options.
+type PageFetcher[F queries.QueryFilters, T any] func(ctx context.Context, opts ...queries.QueryOption[F]) (*response.PageModel[T], error)
+
+type pageResult[T any] struct {
+	page *response.PageModel[T]
+	err  error
+}
+
+// Iterate returns an iterator walking through all pages returned by the fetcher.
+// Iteration starts at the page number set in the query options (or the first page)
+// and keeps the remaining options, such as filters, metadata, page size and sorting,
+// for every subsequent request. It stops once TotalPages is reached, the consumer breaks
+// out of the loop, or the context is canceled.
+func Iterate[F queries.QueryFilters, T any](ctx context.Context, fetch PageFetcher[F, T], opts ...queries.QueryOption[F]) queries.Iterator[T] {
+	return func(yield func(*T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		query := queries.NewQuery(opts...)
+		pageFilter := query.PageFilter
+		if pageFilter.Number < 1 {
+			pageFilter.Number = 1
+		}
+
+		fetchPage := func(number int) pageResult[T] {
+			pageFilter := pageFilter
+			pageFilter.Number = number
+			pageOpts := make([]queries.QueryOption[F], 0, len(opts)+1)
+			pageOpts = append(pageOpts, opts...)
+			pageOpts = append(pageOpts, queries.QueryWithPageFilter[F](pageFilter))
+
+			page, err := fetch(ctx, pageOpts...)
+			return pageResult[T]{page: page, err: err}
+		}
+
+		number := pageFilter.Number
+		current := fetchPage(number)
+		for {
+			if current.err != nil {
+				yield(nil, current.err)
+				return
+			}
+
+			hasNext := len(current.page.Content) > 0 && number < current.page.Page.TotalPages
+			var next chan pageResult[T]
+			if hasNext && query.Prefetch {
+				next = make(chan pageResult[T], 1)
+				go func(number int) { next <- fetchPage(number) }(number + 1)
+			}
+
+			for _, item := range current.page.Content {
+				if err := ctx.Err(); err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !hasNext {
+				return
+			}
+
+			number++
+			if next != nil {
+				current = <-next
+			} else {
+				current = fetchPage(number)
+			}
+		}
+	}
+}
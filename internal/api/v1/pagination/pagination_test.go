@@ -0,0 +1,151 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/pagination"
+	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet/models/filter"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterate(t *testing.T) {
+	tests := map[string]struct {
+		opts          []queries.QueryOption[filter.TransactionFilter]
+		expectedItems []int
+		expectedPages []filter.Page
+	}{
+		"all pages from the first one": {
+			expectedItems: []int{1, 2, 3, 4, 5},
+			expectedPages: []filter.Page{{Number: 1}, {Number: 2}, {Number: 3}},
+		},
+		"all pages from the given page keeping size and sorting": {
+			opts: []queries.QueryOption[filter.TransactionFilter]{
+				queries.QueryWithPageFilter[filter.TransactionFilter](filter.Page{Number: 2, Size: 2, Sort: "asc"}),
+			},
+			expectedItems: []int{3, 4, 5},
+			expectedPages: []filter.Page{{Number: 2, Size: 2, Sort: "asc"}, {Number: 3, Size: 2, Sort: "asc"}},
+		},
+		"all pages with prefetch": {
+			opts: []queries.QueryOption[filter.TransactionFilter]{
+				queries.QueryWithPrefetch[filter.TransactionFilter](),
+			},
+			expectedItems: []int{1, 2, 3, 4, 5},
+			expectedPages: []filter.Page{{Number: 1}, {Number: 2}, {Number: 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			fetcher := newPagesFetcher([][]int{{1, 2}, {3, 4}, {5}})
+
+			// when:
+			items, err := pagination.Iterate(context.Background(), fetcher.fetch, tc.opts...).Collect()
+
+			// then:
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedItems, values(items))
+			require.Equal(t, tc.expectedPages, fetcher.requested)
+		})
+	}
+}
+
+func TestIterate_EarlyBreak(t *testing.T) {
+	// given:
+	fetcher := newPagesFetcher([][]int{{1, 2}, {3, 4}, {5}})
+	var items []int
+
+	// when:
+	pagination.Iterate(context.Background(), fetcher.fetch)(func(item *int, err error) bool {
+		require.NoError(t, err)
+		items = append(items, *item)
+		return len(items) < 3
+	})
+
+	// then:
+	require.Equal(t, []int{1, 2, 3}, items)
+	require.Len(t, fetcher.requested, 2)
+}
+
+func TestIterate_FetchError(t *testing.T) {
+	// given:
+	fetcher := newPagesFetcher([][]int{{1, 2}, {3, 4}})
+	fetcher.failOnPage = 2
+
+	// when:
+	items, err := pagination.Iterate(context.Background(), fetcher.fetch).Collect()
+
+	// then:
+	require.ErrorIs(t, err, errFetch)
+	require.Equal(t, []int{1, 2}, values(items))
+}
+
+func TestIterate_ContextCanceled(t *testing.T) {
+	// given:
+	ctx, cancel := context.WithCancel(context.Background())
+	fetcher := newPagesFetcher([][]int{{1, 2}, {3, 4}})
+	var items []int
+	var iterErr error
+
+	// when:
+	pagination.Iterate(ctx, fetcher.fetch)(func(item *int, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		items = append(items, *item)
+		cancel()
+		return true
+	})
+
+	// then:
+	require.ErrorIs(t, iterErr, context.Canceled)
+	require.Equal(t, []int{1}, items)
+}
+
+var errFetch = errors.New("fetch failure")
+
+type pagesFetcher struct {
+	mu         sync.Mutex
+	pages      [][]int
+	requested  []filter.Page
+	failOnPage int
+}
+
+func newPagesFetcher(pages [][]int) *pagesFetcher {
+	return &pagesFetcher{pages: pages}
+}
+
+func (p *pagesFetcher) fetch(ctx context.Context, opts ...queries.QueryOption[filter.TransactionFilter]) (*response.PageModel[int], error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	page := queries.NewQuery(opts...).PageFilter
+	p.requested = append(p.requested, page)
+	if page.Number == p.failOnPage {
+		return nil, errFetch
+	}
+
+	var content []*int
+	for _, v := range p.pages[page.Number-1] {
+		content = append(content, &v)
+	}
+
+	return &response.PageModel[int]{
+		Content: content,
+		Page:    response.PageDescription{Number: page.Number, TotalPages: len(p.pages)},
+	}, nil
+}
+
+func values(items []*int) []int {
+	res := make([]int, 0, len(items))
+	for _, item := range items {
+		res = append(res, *item)
+	}
+	return res
+}
@@ -0,0 +1,317 @@
+package transactions
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
+	"github.com/bitcoin-sv/go-sdk/script"
+	trx "github.com/bitcoin-sv/go-sdk/transaction"
+	"github.com/bitcoin-sv/go-sdk/transaction/template/p2pkh"
+	walleterrors "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+// p2pkhInputSize is the size in bytes of a signed P2PKH input:
+// outpoint (36) + script length (1) + unlocking script (107) + sequence (4).
+const p2pkhInputSize = 148
+
+// draftVerifier checks a draft transaction returned by the server before it is signed,
+// so that a compromised or buggy server cannot redirect funds.
+// Without the xPub the change destinations cannot be attributed to the user,
+// so drafts with change are rejected with ErrDraftChangeUnverified.
+type draftVerifier struct {
+	xPub *bip32.ExtendedKey
+}
+
+// Verify parses the draft hex and checks that:
+//   - every requested output is paid with the requested amount,
+//   - the remaining outputs are change outputs adding up to the declared change,
+//   - the change destinations are derived from the user's xPub,
+//   - the fee does not exceed the one calculated from the draft's fee unit.
+//
+// When requested is nil, the outputs declared in the draft configuration are used instead.
+// Nil drafts, outputs, inputs and destinations are reported as verification errors.
+// Recipients given as paymail addresses are resolved by the server, so only their amounts
+// can be verified offline.
+func (v *draftVerifier) Verify(draft *response.DraftTransaction, requested []*response.TransactionOutput) error {
+	if draft == nil {
+		return walleterrors.ErrDraftMissing
+	}
+
+	tx, err := trx.NewTransactionFromHex(draft.Hex)
+	if err != nil {
+		return errors.Join(walleterrors.ErrFailedToParseHex, err)
+	}
+
+	cfg := &draft.Configuration
+	change, err := v.changeScripts(cfg.ChangeDestinations)
+	if err != nil {
+		return err
+	}
+
+	var changeSats, outputSats uint64
+	payments := make([]*trx.TransactionOutput, 0, len(tx.Outputs))
+	for _, out := range tx.Outputs {
+		outputSats += out.Satoshis
+		if _, ok := change[out.LockingScript.String()]; ok {
+			changeSats += out.Satoshis
+			continue
+		}
+		payments = append(payments, out)
+	}
+	if changeSats != cfg.ChangeSatoshis {
+		return fmt.Errorf("%w: change outputs hold %d satoshis, declared %d", walleterrors.ErrDraftChangeMismatch, changeSats, cfg.ChangeSatoshis)
+	}
+
+	if requested == nil {
+		requested = paymentOutputs(cfg.Outputs, change)
+	}
+	for _, r := range requested {
+		if r == nil {
+			return fmt.Errorf("%w: nil requested output", walleterrors.ErrDraftOutputMismatch)
+		}
+		expected, err := expectedOutputs(r, cfg.Outputs)
+		if err != nil {
+			return err
+		}
+		for _, e := range expected {
+			if payments, err = e.take(payments); err != nil {
+				return err
+			}
+		}
+	}
+	if len(payments) > 0 {
+		return fmt.Errorf("%w: %d satoshis to script %s", walleterrors.ErrDraftUnexpectedOutput, payments[0].Satoshis, payments[0].LockingScript)
+	}
+
+	return verifyFee(tx, cfg, outputSats)
+}
+
+func (v *draftVerifier) changeScripts(destinations []*response.Destination) (map[string]struct{}, error) {
+	if len(destinations) > 0 && v.xPub == nil {
+		return nil, walleterrors.ErrDraftChangeUnverified
+	}
+
+	scripts := make(map[string]struct{}, len(destinations))
+	for _, dst := range destinations {
+		if dst == nil {
+			return nil, fmt.Errorf("%w: nil change destination", walleterrors.ErrDraftForeignChangeDestination)
+		}
+		owned, err := lockingScriptForDestination(v.xPub, dst)
+		if err != nil {
+			return nil, errors.Join(walleterrors.ErrGetDerivedKeyForDestination, err)
+		}
+		if owned != dst.LockingScript {
+			return nil, fmt.Errorf("%w: %s (m/%d/%d)", walleterrors.ErrDraftForeignChangeDestination, dst.LockingScript, dst.Chain, dst.Num)
+		}
+		scripts[dst.LockingScript] = struct{}{}
+	}
+
+	return scripts, nil
+}
+
+func lockingScriptForDestination(xPub *bip32.ExtendedKey, dst *response.Destination) (string, error) {
+	derivedKey, err := bip32.GetHDKeyByPath(xPub, dst.Chain, dst.Num)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive key for change destination, %w", err)
+	}
+	if dst.PaymailExternalDerivationNum != nil {
+		derivedKey, err = derivedKey.Child(*dst.PaymailExternalDerivationNum)
+		if err != nil {
+			return "", fmt.Errorf("failed to derive key for paymail change destination, %w", err)
+		}
+	}
+
+	pubKey, err := derivedKey.ECPubKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get public key for change destination, %w", err)
+	}
+	address, err := script.NewAddressFromPublicKey(pubKey, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to get address for change destination, %w", err)
+	}
+	lockingScript, err := p2pkh.Lock(address)
+	if err != nil {
+		return "", fmt.Errorf("failed to create locking script for change destination, %w", err)
+	}
+
+	return lockingScript.String(), nil
+}
+
+// paymentOutputs returns the draft outputs that are not paying to the change destinations.
+func paymentOutputs(outputs []*response.TransactionOutput, change map[string]struct{}) []*response.TransactionOutput {
+	payments := make([]*response.TransactionOutput, 0, len(outputs))
+	for _, out := range outputs {
+		if out == nil || len(out.Scripts) > 0 && allScriptsIn(out.Scripts, change) {
+			continue
+		}
+		payments = append(payments, out)
+	}
+
+	return payments
+}
+
+func allScriptsIn(scripts []*response.ScriptOutput, set map[string]struct{}) bool {
+	for _, s := range scripts {
+		if s == nil {
+			return false
+		}
+		if _, ok := set[s.Script]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+type expectedOutput struct {
+	script   string
+	satoshis uint64
+}
+
+// take removes the first output matching the expectation from outputs.
+func (e expectedOutput) take(outputs []*trx.TransactionOutput) ([]*trx.TransactionOutput, error) {
+	for i, out := range outputs {
+		if out.Satoshis == e.satoshis && out.LockingScript.String() == e.script {
+			return append(outputs[:i], outputs[i+1:]...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d satoshis to script %q", walleterrors.ErrDraftOutputMismatch, e.satoshis, e.script)
+}
+
+// expectedOutputs returns the transaction outputs the requested output must be paid with.
+// Scripts that can be built offline (addresses, raw scripts and OP_RETURN data) are built locally,
+// the remaining ones (paymails, MAP protocol) are taken from the scripts resolved by the server.
+func expectedOutputs(r *response.TransactionOutput, draftOutputs []*response.TransactionOutput) ([]expectedOutput, error) {
+	switch {
+	case r.OpReturn != nil && r.OpReturn.Map == nil:
+		s, err := opReturnScript(r.OpReturn)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid OP_RETURN: %w", walleterrors.ErrDraftOutputMismatch, err)
+		}
+		return []expectedOutput{{script: s, satoshis: r.Satoshis}}, nil
+	case r.OpReturn == nil && r.Script != "":
+		return []expectedOutput{{script: r.Script, satoshis: r.Satoshis}}, nil
+	case r.OpReturn == nil && r.To != "" && !strings.Contains(r.To, "@"):
+		address, err := script.NewAddressFromString(r.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid address %s: %w", walleterrors.ErrDraftOutputMismatch, r.To, err)
+		}
+		s, err := p2pkh.Lock(address)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid address %s: %w", walleterrors.ErrDraftOutputMismatch, r.To, err)
+		}
+		return []expectedOutput{{script: s.String(), satoshis: r.Satoshis}}, nil
+	}
+
+	resolved := findDraftOutput(r, draftOutputs)
+	if resolved == nil || len(resolved.Scripts) == 0 {
+		return nil, fmt.Errorf("%w: no scripts resolved for recipient %q", walleterrors.ErrDraftOutputMismatch, r.To)
+	}
+
+	var total uint64
+	expected := make([]expectedOutput, 0, len(resolved.Scripts))
+	for _, s := range resolved.Scripts {
+		if s == nil {
+			return nil, fmt.Errorf("%w: nil script resolved for recipient %q", walleterrors.ErrDraftOutputMismatch, r.To)
+		}
+		// MAP protocol data is built by the server, so only a data carrier output is accepted for it.
+		if r.OpReturn != nil && !isDataScript(s.Script) {
+			return nil, fmt.Errorf("%w: OP_RETURN resolved to non-data script %s", walleterrors.ErrDraftOutputMismatch, s.Script)
+		}
+		total += s.Satoshis
+		expected = append(expected, expectedOutput{script: s.Script, satoshis: s.Satoshis})
+	}
+	if total != r.Satoshis {
+		return nil, fmt.Errorf("%w: recipient %q resolved to %d satoshis, requested %d", walleterrors.ErrDraftOutputMismatch, r.To, total, r.Satoshis)
+	}
+
+	return expected, nil
+}
+
+func findDraftOutput(r *response.TransactionOutput, draftOutputs []*response.TransactionOutput) *response.TransactionOutput {
+	for _, out := range draftOutputs {
+		if out == nil {
+			continue
+		}
+		if out == r || (out.To == r.To && out.Satoshis == r.Satoshis && (out.OpReturn == nil) == (r.OpReturn == nil)) {
+			return out
+		}
+	}
+
+	return nil
+}
+
+func isDataScript(hexScript string) bool {
+	s, err := script.NewFromHex(hexScript)
+	return err == nil && s.IsData()
+}
+
+// opReturnScript builds the OP_FALSE OP_RETURN script the same way the SPV Wallet does.
+func opReturnScript(opReturn *response.OpReturn) (string, error) {
+	if opReturn.Hex != "" {
+		s, err := script.NewFromHex(opReturn.Hex)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse OP_RETURN hex: %w", err)
+		}
+		return s.String(), nil
+	}
+
+	s := &script.Script{}
+	if err := s.AppendOpcodes(script.OpFALSE, script.OpRETURN); err != nil {
+		return "", fmt.Errorf("failed to append OP_RETURN opcodes: %w", err)
+	}
+	for _, part := range opReturn.HexParts {
+		data, err := hex.DecodeString(part)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode OP_RETURN hex part: %w", err)
+		}
+		if err := s.AppendPushData(data); err != nil {
+			return "", fmt.Errorf("failed to append OP_RETURN hex part: %w", err)
+		}
+	}
+	if err := s.AppendPushDataStrings(opReturn.StringParts); err != nil {
+		return "", fmt.Errorf("failed to append OP_RETURN string parts: %w", err)
+	}
+
+	return s.String(), nil
+}
+
+// verifyFee recomputes the fee paid by the draft and checks it against the declared fee
+// and the rate defined by the draft's fee unit. The signed size is estimated from the
+// unsigned outputs and a P2PKH unlocking script per input, as the signer adds the inputs
+// from the draft configuration.
+func verifyFee(tx *trx.Transaction, cfg *response.TransactionConfig, outputSats uint64) error {
+	var inputSats uint64
+	for _, in := range cfg.Inputs {
+		if in == nil {
+			return fmt.Errorf("%w: nil input", walleterrors.ErrDraftFeeMismatch)
+		}
+		inputSats += in.Satoshis
+	}
+	if outputSats > inputSats {
+		return fmt.Errorf("%w: outputs (%d) exceed inputs (%d)", walleterrors.ErrDraftFeeMismatch, outputSats, inputSats)
+	}
+
+	fee := inputSats - outputSats
+	if cfg.Fee != 0 && cfg.Fee != fee {
+		return fmt.Errorf("%w: paid %d, declared %d", walleterrors.ErrDraftFeeMismatch, fee, cfg.Fee)
+	}
+	if cfg.FeeUnit == nil || cfg.FeeUnit.Bytes <= 0 {
+		return fmt.Errorf("%w: invalid fee unit", walleterrors.ErrDraftFeeMismatch)
+	}
+
+	tx.Inputs = nil
+	size := uint64(tx.Size() + len(cfg.Inputs)*p2pkhInputSize)
+	bytes, satoshis := uint64(cfg.FeeUnit.Bytes), uint64(cfg.FeeUnit.Satoshis)
+	maxFee := (size*satoshis + bytes - 1) / bytes
+	if fee > maxFee {
+		return fmt.Errorf("%w: paid %d, at most %d for ~%d bytes", walleterrors.ErrDraftFeeTooHigh, fee, maxFee, size)
+	}
+
+	return nil
+}
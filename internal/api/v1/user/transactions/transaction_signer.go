@@ -18,7 +18,7 @@ type noopTransactionSigner struct {
 }
 
 func (*noopTransactionSigner) TransactionSignedHex(dt *response.DraftTransaction) (string, error) {
-	return "", nil
+	return "", walleterrors.ErrNoTransactionSigner
 }
 
 type xPrivTransactionSigner struct {
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"net/url"
 
+	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/queryparams"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
 	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
 	"github.com/bitcoin-sv/spv-wallet/models/filter"
 	"github.com/bitcoin-sv/spv-wallet/models/response"
@@ -18,6 +21,7 @@ const (
 	api   = "User Transactions API"
 )
 
+// TransactionSigner signs draft transactions, returning the signed transaction in hex format.
 type TransactionSigner interface {
 	TransactionSignedHex(dt *response.DraftTransaction) (string, error)
 }
@@ -26,9 +30,57 @@ type API struct {
 	url               *url.URL
 	httpClient        *resty.Client
 	transactionSigner TransactionSigner
+	draftVerifier     *draftVerifier
 }
 
 func (a *API) FinalizeTransaction(draft *response.DraftTransaction) (string, error) {
+	return a.finalizeTransaction(draft, nil)
+}
+
+func (a *API) FinalizeTransactionFor(draft *response.DraftTransaction, requested *response.TransactionConfig) (string, error) {
+	if draft == nil {
+		return "", goclienterr.ErrDraftMissing
+	}
+	if requested == nil {
+		return "", goclienterr.ErrDraftRequestMissing
+	}
+
+	return a.finalizeTransaction(draft, requestedOutputs(draft, requested))
+}
+
+func (a *API) VerifyDraftTransaction(draft *response.DraftTransaction, requested *response.TransactionConfig) error {
+	if draft == nil {
+		return goclienterr.ErrDraftMissing
+	}
+	if requested == nil {
+		return a.draftVerifier.Verify(draft, nil)
+	}
+
+	return a.draftVerifier.Verify(draft, requestedOutputs(draft, requested))
+}
+
+// requestedOutputs returns the outputs of the requested transaction config the draft must pay.
+func requestedOutputs(draft *response.DraftTransaction, requested *response.TransactionConfig) []*response.TransactionOutput {
+	outputs := make([]*response.TransactionOutput, 0, len(requested.Outputs)+1)
+	outputs = append(outputs, requested.Outputs...)
+	if requested.SendAllTo != nil && draft.Configuration.SendAllTo != nil {
+		// The amount sent is known only after the server has selected the inputs.
+		sendAllTo := *requested.SendAllTo
+		sendAllTo.Satoshis = draft.Configuration.SendAllTo.Satoshis
+		outputs = append(outputs, &sendAllTo)
+	}
+
+	return outputs
+}
+
+func (a *API) finalizeTransaction(draft *response.DraftTransaction, requested []*response.TransactionOutput) (string, error) {
+	if _, ok := a.transactionSigner.(*noopTransactionSigner); ok {
+		return "", goclienterr.ErrNoTransactionSigner
+	}
+	if err := a.draftVerifier.Verify(draft, requested); err != nil {
+		return "", fmt.Errorf("failed to verify draft transaction: %w", err)
+	}
+
 	hex, err := a.transactionSigner.TransactionSignedHex(draft)
 	if err != nil {
 		return "", fmt.Errorf("failed to finalize transaction: %w", err)
@@ -38,40 +90,68 @@ func (a *API) FinalizeTransaction(draft *response.DraftTransaction) (string, err
 }
 
 func (a *API) DraftToRecipients(ctx context.Context, r *commands.SendToRecipients) (*response.DraftTransaction, error) {
-	outputs := make([]*response.TransactionOutput, 0)
-
-	for _, recipient := range r.Recipients {
-		outputs = append(outputs, &response.TransactionOutput{
-			To:       recipient.To,
-			Satoshis: recipient.Satoshis,
-			OpReturn: recipient.OpReturn,
-		})
-	}
-
 	return a.DraftTransaction(ctx, &commands.DraftTransaction{
 		Config: response.TransactionConfig{
-			Outputs: outputs,
+			Outputs: recipientsOutputs(r),
 		},
 		Metadata: r.Metadata,
 	})
 }
 
 func (a *API) SendToRecipients(ctx context.Context, r *commands.SendToRecipients) (*response.Transaction, error) {
-	draft, err := a.DraftToRecipients(ctx, r)
+	draft, err := a.draftToRecipients(ctx, r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send draft to recipients: %w", err)
 	}
 
-	var hex string
-	if hex, err = a.FinalizeTransaction(draft); err != nil {
+	hex, err := a.finalizeToRecipients(ctx, draft, r)
+	if err != nil {
 		return nil, fmt.Errorf("failed to finalize transaction: %w", err)
 	}
 
-	return a.RecordTransaction(ctx, &commands.RecordTransaction{
+	return a.recordToRecipients(ctx, draft, hex, r)
+}
+
+func (a *API) draftToRecipients(ctx context.Context, r *commands.SendToRecipients) (*response.DraftTransaction, error) {
+	ctx, span := tracing.StartChild(ctx, "draft")
+	defer span.End()
+
+	draft, err := a.DraftToRecipients(ctx, r)
+	return draft, tracing.Error(span, err)
+}
+
+func (a *API) finalizeToRecipients(ctx context.Context, draft *response.DraftTransaction, r *commands.SendToRecipients) (string, error) {
+	_, span := tracing.StartChild(ctx, "finalize")
+	defer span.End()
+
+	hex, err := a.finalizeTransaction(draft, recipientsOutputs(r))
+	return hex, tracing.Error(span, err)
+}
+
+func (a *API) recordToRecipients(ctx context.Context, draft *response.DraftTransaction, hex string, r *commands.SendToRecipients) (*response.Transaction, error) {
+	ctx, span := tracing.StartChild(ctx, "record")
+	defer span.End()
+
+	tx, err := a.RecordTransaction(ctx, &commands.RecordTransaction{
 		Metadata:    r.Metadata,
 		Hex:         hex,
 		ReferenceID: draft.ID,
 	})
+	return tx, tracing.Error(span, err)
+}
+
+func recipientsOutputs(r *commands.SendToRecipients) []*response.TransactionOutput {
+	outputs := make([]*response.TransactionOutput, 0)
+
+	for _, recipient := range r.Recipients {
+		outputs = append(outputs, &response.TransactionOutput{
+			To:       recipient.To,
+			Satoshis: recipient.Satoshis,
+			OpReturn: recipient.OpReturn,
+		})
+	}
+
+	return outputs
 }
 
 func (a *API) DraftTransaction(ctx context.Context, r *commands.DraftTransaction) (*response.DraftTransaction, error) {
@@ -165,17 +245,72 @@ func NewAPIWithXPriv(URL *url.URL, httpClient *resty.Client, xPriv string) (*API
 		return nil, fmt.Errorf("failed to create transactionSigner: %w", err)
 	}
 
+	xPub, err := transactionSigner.xPriv.Neuter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive xPub from xPriv: %w", err)
+	}
+
 	return &API{
 			url:               URL.JoinPath(route),
 			httpClient:        httpClient,
-			transactionSigner: transactionSigner},
+			transactionSigner: transactionSigner,
+			draftVerifier:     &draftVerifier{xPub: xPub}},
 		nil
 }
 
+func NewAPIWithSigner(URL *url.URL, httpClient *resty.Client, xPub string, transactionSigner TransactionSigner) (*API, error) {
+	if transactionSigner == nil {
+		return nil, goclienterr.ErrNoTransactionSigner
+	}
+
+	key, err := parseXPub(xPub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &API{
+		url:               URL.JoinPath(route),
+		httpClient:        httpClient,
+		transactionSigner: transactionSigner,
+		draftVerifier:     &draftVerifier{xPub: key},
+	}, nil
+}
+
+// NewAPIWithXPub creates the API of a client that can't sign transactions,
+// but can verify the change destinations of draft transactions with the xPub.
+func NewAPIWithXPub(URL *url.URL, httpClient *resty.Client, xPub string) (*API, error) {
+	key, err := parseXPub(xPub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &API{
+		url:               URL.JoinPath(route),
+		httpClient:        httpClient,
+		transactionSigner: &noopTransactionSigner{},
+		draftVerifier:     &draftVerifier{xPub: key},
+	}, nil
+}
+
+// NewAPI creates the API of a client that knows neither the xPriv nor the xPub of the user,
+// so it can't sign transactions nor verify the change destinations of draft transactions.
 func NewAPI(URL *url.URL, httpClient *resty.Client) (*API, error) {
 	return &API{
 		url:               URL.JoinPath(route),
 		httpClient:        httpClient,
 		transactionSigner: &noopTransactionSigner{},
+		draftVerifier:     &draftVerifier{},
 	}, nil
 }
+
+func parseXPub(xPub string) (*bip32.ExtendedKey, error) {
+	key, err := bip32.NewKeyFromString(xPub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse xPub: %w", err)
+	}
+	if key.IsPrivate() {
+		return nil, fmt.Errorf("failed to parse xPub: extended private key given")
+	}
+
+	return key, nil
+}
@@ -2,20 +2,29 @@ package transactions_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
 
+	spvwallet "github.com/bitcoin-sv/spv-wallet-go-client"
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
 	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/queryparams"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/transactions/transactionstest"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/constants"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
 	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
 	"github.com/bitcoin-sv/spv-wallet/models/filter"
 	"github.com/bitcoin-sv/spv-wallet/models/response"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -49,6 +58,62 @@ func TestTransactionsAPI_SendToRecipients(t *testing.T) {
 		require.Equal(t, transactionstest.ExpectedSendToRecipientsTransaction(t), result)
 	})
 
+	t.Run("SendToRecipients traced", func(t *testing.T) {
+		// given:
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		wallet, transport := testutils.GivenSPVUserAPIWithTracerProvider(t, tp)
+		var traceparents []string
+		transport.RegisterResponder(http.MethodPost, drafTransactionURL, func(req *http.Request) (*http.Response, error) {
+			traceparents = append(traceparents, req.Header.Get("traceparent"))
+			return testutils.NewJSONFileResponderWithStatusOK("transactionstest/transaction_draft_with_hex_200.json")(req)
+		})
+		transport.RegisterResponder(http.MethodPost, recordTransactionURL, testutils.NewBadRequestSPVErrorResponder())
+
+		// when:
+		_, err := wallet.SendToRecipients(context.Background(), &commands.SendToRecipients{
+			Recipients: []*commands.Recipients{
+				{
+					OpReturn: opReturn,
+				},
+			},
+		})
+
+		// then:
+		require.ErrorIs(t, err, testutils.NewBadRequestSPVError())
+		spans := exporter.GetSpans()
+		byName := make(map[string]tracetest.SpanStub, len(spans))
+		for _, span := range spans {
+			byName[span.Name] = span
+		}
+		require.Len(t, spans, 6)
+
+		root := byName["UserAPI.SendToRecipients"]
+		require.Equal(t, codes.Error, root.Status.Code)
+		require.Contains(t, root.Attributes, tracing.EndpointKey.String(constants.UserTransactionsAPI))
+		for _, step := range []string{"draft", "finalize", "record"} {
+			require.Equal(t, root.SpanContext.SpanID(), byName[step].Parent.SpanID(), step)
+		}
+		require.Equal(t, codes.Error, byName["record"].Status.Code)
+
+		var httpSpans []tracetest.SpanStub
+		for _, span := range spans {
+			if span.SpanKind == trace.SpanKindClient {
+				httpSpans = append(httpSpans, span)
+			}
+		}
+		require.Len(t, httpSpans, 2)
+		require.Equal(t, byName["draft"].SpanContext.SpanID(), httpSpans[0].Parent.SpanID())
+		require.Contains(t, httpSpans[0].Attributes, tracing.StatusCodeKey.Int(http.StatusOK))
+		require.Equal(t, byName["record"].SpanContext.SpanID(), httpSpans[1].Parent.SpanID())
+		require.Contains(t, httpSpans[1].Attributes, tracing.StatusCodeKey.Int(http.StatusBadRequest))
+		require.Contains(t, httpSpans[1].Attributes, tracing.EndpointKey.String(constants.UserTransactionsAPI))
+
+		require.Len(t, traceparents, 1)
+		require.Contains(t, traceparents[0], httpSpans[0].SpanContext.TraceID().String())
+		require.Contains(t, traceparents[0], httpSpans[0].SpanContext.SpanID().String())
+	})
+
 	t.Run("SendToRecipients - DraftToRecipients error", func(t *testing.T) {
 		// given:
 		wallet, transport := testutils.GivenSPVUserAPI(t)
@@ -89,6 +154,27 @@ func TestTransactionsAPI_SendToRecipients(t *testing.T) {
 		require.Nil(t, result)
 	})
 
+	t.Run("SendToRecipients - draft not paying requested recipient", func(t *testing.T) {
+		// given:
+		wallet, transport := testutils.GivenSPVUserAPI(t)
+		transport.RegisterResponder(http.MethodPost, drafTransactionURL, testutils.NewJSONFileResponderWithStatusOK("transactionstest/transaction_draft_with_hex_200.json"))
+		ctx := context.Background()
+
+		// when:
+		result, err := wallet.SendToRecipients(ctx, &commands.SendToRecipients{
+			Recipients: []*commands.Recipients{
+				{
+					To:       "1AKU4EU46p38GWhaEcvuLL2UK23Fv14cwn",
+					Satoshis: 1,
+				},
+			},
+		})
+
+		// then:
+		require.ErrorIs(t, err, errors.ErrDraftOutputMismatch)
+		require.Nil(t, result)
+	})
+
 	t.Run("SendToRecipients - RecordTransaction error", func(t *testing.T) {
 		// given:
 		wallet, transport := testutils.GivenSPVUserAPI(t)
@@ -119,7 +205,7 @@ func TestTransactionsAPI_FinalizeTransaction(t *testing.T) {
 	}{
 		"Finalize Transaction with proper draft": {
 			draft:       transactionstest.ExpectedDraftTransactionWithHex(t),
-			expectedHex: "01000000014c037d55e72d2ee6a95ff67bd758c4cee9c7545bb4d72ba77584152fcfa07012000000006b483045022100a01c25ad9a306f747d90a6d0e815795416ee1f004f865b0653ae3eb2939f42d90220110d994aa99f10533d2566317f55cab838b40f333bf4cdf30c82246461c31fef412102af82c4f5cac25cb5062364937c5e2286094b709610e60b7997b6715784dbf91effffffff0200000000000000000e006a0568656c6c6f05776f726c6408000000000000001976a914702cef80a7039a1aebb70dc05ce1e439646fa33788ac00000000",
+			expectedHex: "01000000014c037d55e72d2ee6a95ff67bd758c4cee9c7545bb4d72ba77584152fcfa07012000000006a473044022024030701180c3beca4c26ba9e4ca497a9e3898ab7210af0b199f3e2664db81ee02206777803b9016a85b6da6bea3e1be2134ae54a2c3457137e1e640e4f04d59af99412102af82c4f5cac25cb5062364937c5e2286094b709610e60b7997b6715784dbf91effffffff0200000000000000000e006a0568656c6c6f05776f726c6408000000000000001976a914891ff0385511453144b045b23eaf785e9018421c88ac00000000",
 		},
 		"Finalize Transaction fail to parse hex": {
 			draft:       transactionstest.ExpectedDraftTransactionWithWrongHex(t),
@@ -133,6 +219,22 @@ func TestTransactionsAPI_FinalizeTransaction(t *testing.T) {
 			draft:       transactionstest.ExpectedDraftTransactionWithWrongInputs(t),
 			expectedErr: errors.ErrAddInputsToTransaction,
 		},
+		"Finalize Transaction rejects draft with output siphoning funds": {
+			draft:       transactionstest.ExpectedDraftTransactionWithSiphonOutput(t),
+			expectedErr: errors.ErrDraftUnexpectedOutput,
+		},
+		"Finalize Transaction rejects draft with change to foreign destination": {
+			draft:       transactionstest.ExpectedDraftTransactionWithForeignChange(t),
+			expectedErr: errors.ErrDraftForeignChangeDestination,
+		},
+		"Finalize Transaction rejects draft with excessive fee": {
+			draft:       transactionstest.ExpectedDraftTransactionWithExcessiveFee(t),
+			expectedErr: errors.ErrDraftFeeTooHigh,
+		},
+		"Finalize Transaction rejects draft with tampered OP_RETURN": {
+			draft:       transactionstest.ExpectedDraftTransactionWithTamperedOpReturn(t),
+			expectedErr: errors.ErrDraftOutputMismatch,
+		},
 	}
 
 	for name, tc := range tests {
@@ -150,6 +252,194 @@ func TestTransactionsAPI_FinalizeTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionsAPI_FinalizeTransactionFor(t *testing.T) {
+	requested := &response.TransactionConfig{
+		Outputs: []*response.TransactionOutput{{OpReturn: &response.OpReturn{StringParts: []string{"hello", "world"}}}},
+	}
+	tests := map[string]struct {
+		draft       *response.DraftTransaction
+		requested   *response.TransactionConfig
+		expectedErr error
+	}{
+		"Finalize Transaction paying the requested outputs": {
+			draft:     transactionstest.ExpectedDraftTransactionWithHex(t),
+			requested: requested,
+		},
+		"Finalize Transaction rejects output tampered along with the configuration": {
+			draft:       transactionstest.ExpectedDraftTransactionWithTamperedOutputAndConfiguration(t),
+			requested:   requested,
+			expectedErr: errors.ErrDraftOutputMismatch,
+		},
+		"Finalize Transaction rejects draft with change to foreign destination": {
+			draft:       transactionstest.ExpectedDraftTransactionWithForeignChange(t),
+			requested:   requested,
+			expectedErr: errors.ErrDraftForeignChangeDestination,
+		},
+		"Finalize Transaction without requested transaction config": {
+			draft:       transactionstest.ExpectedDraftTransactionWithHex(t),
+			expectedErr: errors.ErrDraftRequestMissing,
+		},
+		"Finalize nil draft": {
+			requested:   requested,
+			expectedErr: errors.ErrDraftMissing,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			wallet, _ := testutils.GivenSPVUserAPI(t)
+
+			// when:
+			hex, err := wallet.FinalizeTransactionFor(tc.draft, tc.requested)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedErr == nil, hex != "")
+		})
+	}
+
+	t.Run("FinalizeTransaction doesn't detect output tampered along with the configuration", func(t *testing.T) {
+		// given:
+		wallet, _ := testutils.GivenSPVUserAPI(t)
+
+		// when:
+		hex, err := wallet.FinalizeTransaction(transactionstest.ExpectedDraftTransactionWithTamperedOutputAndConfiguration(t))
+
+		// then:
+		require.NoError(t, err)
+		require.NotEmpty(t, hex)
+	})
+}
+
+func TestTransactionsAPI_VerifyDraftTransaction(t *testing.T) {
+	opReturn := &response.OpReturn{StringParts: []string{"hello", "world"}}
+	tests := map[string]struct {
+		requested   *response.TransactionConfig
+		expectedErr error
+	}{
+		"Verify draft matching requested OP_RETURN": {
+			requested: &response.TransactionConfig{
+				Outputs: []*response.TransactionOutput{{OpReturn: opReturn}},
+			},
+		},
+		"Verify draft with different OP_RETURN data": {
+			requested: &response.TransactionConfig{
+				Outputs: []*response.TransactionOutput{{OpReturn: &response.OpReturn{StringParts: []string{"hello"}}}},
+			},
+			expectedErr: errors.ErrDraftOutputMismatch,
+		},
+		"Verify draft missing requested address output": {
+			requested: &response.TransactionConfig{
+				Outputs: []*response.TransactionOutput{
+					{OpReturn: opReturn},
+					{To: "1AKU4EU46p38GWhaEcvuLL2UK23Fv14cwn", Satoshis: 1},
+				},
+			},
+			expectedErr: errors.ErrDraftOutputMismatch,
+		},
+		"Verify draft paying not requested output": {
+			requested:   &response.TransactionConfig{},
+			expectedErr: errors.ErrDraftUnexpectedOutput,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			wallet, _ := testutils.GivenSPVUserAPI(t)
+
+			// when:
+			err := wallet.VerifyDraftTransaction(transactionstest.ExpectedDraftTransactionWithHex(t), tc.requested)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestTransactionsAPI_VerifyDraftTransactionNilInputs(t *testing.T) {
+	tests := map[string]struct {
+		draft       *response.DraftTransaction
+		requested   *response.TransactionConfig
+		expectedErr error
+	}{
+		"Verify nil draft": {
+			requested:   &response.TransactionConfig{},
+			expectedErr: errors.ErrDraftMissing,
+		},
+		"Verify nil draft against nil request": {
+			expectedErr: errors.ErrDraftMissing,
+		},
+		"Verify draft against nil request uses its own outputs": {
+			draft: transactionstest.ExpectedDraftTransactionWithHex(t),
+		},
+		"Verify draft against nil request detects siphoning output": {
+			draft:       transactionstest.ExpectedDraftTransactionWithSiphonOutput(t),
+			expectedErr: errors.ErrDraftUnexpectedOutput,
+		},
+		"Verify draft with empty configuration": {
+			draft:       &response.DraftTransaction{Hex: transactionstest.ExpectedDraftTransactionWithHex(t).Hex},
+			expectedErr: errors.ErrDraftUnexpectedOutput,
+		},
+		"Verify draft with nil requested output": {
+			draft:       transactionstest.ExpectedDraftTransactionWithHex(t),
+			requested:   &response.TransactionConfig{Outputs: []*response.TransactionOutput{nil}},
+			expectedErr: errors.ErrDraftOutputMismatch,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			wallet, _ := testutils.GivenSPVUserAPI(t)
+
+			// when:
+			err := wallet.VerifyDraftTransaction(tc.draft, tc.requested)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+
+	t.Run("Finalize nil draft", func(t *testing.T) {
+		// given:
+		wallet, _ := testutils.GivenSPVUserAPI(t)
+
+		// when:
+		hex, err := wallet.FinalizeTransaction(nil)
+
+		// then:
+		require.ErrorIs(t, err, errors.ErrDraftMissing)
+		require.Empty(t, hex)
+	})
+}
+
+func TestTransactionsAPI_VerifyDraftTransactionWithoutXPriv(t *testing.T) {
+	t.Run("Client with xPub verifies change", func(t *testing.T) {
+		// given:
+		wallet, _ := testutils.GivenSPVUserAPIWithXPub(t)
+
+		// when:
+		err := wallet.VerifyDraftTransaction(transactionstest.ExpectedDraftTransactionWithForeignChange(t), nil)
+
+		// then:
+		require.ErrorIs(t, err, errors.ErrDraftForeignChangeDestination)
+		require.NoError(t, wallet.VerifyDraftTransaction(transactionstest.ExpectedDraftTransactionWithHex(t), nil))
+	})
+
+	t.Run("Client with access key can't verify change", func(t *testing.T) {
+		// given:
+		wallet, _ := testutils.GivenSPVUserAPIWithAccessKey(t)
+
+		// when:
+		err := wallet.VerifyDraftTransaction(transactionstest.ExpectedDraftTransactionWithHex(t), nil)
+
+		// then:
+		require.ErrorIs(t, err, errors.ErrDraftChangeUnverified)
+	})
+}
+
 func TestTransactionsAPI_UpdateTransactionMetadata(t *testing.T) {
 	id := "1024"
 	tests := map[string]struct {
@@ -379,3 +669,152 @@ func TestTransactionsAPI_Transactions(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionsAPI_AllTransactions(t *testing.T) {
+	firstPage := &queries.TransactionPage{
+		Content: []*response.Transaction{{ID: "1"}, {ID: "2"}},
+		Page:    response.PageDescription{Number: 1, Size: 2, TotalElements: 3, TotalPages: 2},
+	}
+	secondPage := &queries.TransactionPage{
+		Content: []*response.Transaction{{ID: "3"}},
+		Page:    response.PageDescription{Number: 2, Size: 2, TotalElements: 3, TotalPages: 2},
+	}
+	url := testutils.FullAPIURL(t, transactionsURL)
+
+	t.Run("HTTP GET /api/v1/transactions all pages", func(t *testing.T) {
+		// given:
+		wallet, transport := testutils.GivenSPVUserAPI(t)
+		transport.RegisterResponderWithQuery(http.MethodGet, url, "page=1&size=2&blockHeight=100", testutils.NewJSONBodyResponderWithStatusOK(firstPage))
+		transport.RegisterResponderWithQuery(http.MethodGet, url, "page=2&size=2&blockHeight=100", testutils.NewJSONBodyResponderWithStatusOK(secondPage))
+
+		// when:
+		got, err := wallet.AllTransactions(context.Background(),
+			queries.QueryWithPageFilter[filter.TransactionFilter](filter.Page{Size: 2}),
+			queries.QueryWithFilter(filter.TransactionFilter{BlockHeight: testutils.Ptr(uint64(100))}),
+		).Collect()
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, append(firstPage.Content, secondPage.Content...), got)
+	})
+
+	t.Run("HTTP GET /api/v1/transactions page failure", func(t *testing.T) {
+		// given:
+		wallet, transport := testutils.GivenSPVUserAPI(t)
+		transport.RegisterResponderWithQuery(http.MethodGet, url, "page=1&size=2", testutils.NewJSONBodyResponderWithStatusOK(firstPage))
+		transport.RegisterResponderWithQuery(http.MethodGet, url, "page=2&size=2", testutils.NewInternalServerSPVErrorResponder())
+
+		// when:
+		got, err := wallet.AllTransactions(context.Background(),
+			queries.QueryWithPageFilter[filter.TransactionFilter](filter.Page{Size: 2}),
+			queries.QueryWithPrefetch[filter.TransactionFilter](),
+		).Collect()
+
+		// then:
+		require.ErrorIs(t, err, testutils.NewInternalServerSPVError())
+		require.Equal(t, firstPage.Content, got)
+	})
+}
+
+func TestTransactionsAPI_FinalizeTransactionWithoutSigner(t *testing.T) {
+	// given:
+	wallet, _ := testutils.GivenSPVUserAPIWithXPub(t)
+
+	// when:
+	hex, err := wallet.FinalizeTransaction(transactionstest.ExpectedDraftTransactionWithHex(t))
+
+	// then:
+	require.ErrorIs(t, err, errors.ErrNoTransactionSigner)
+	require.Empty(t, hex)
+}
+
+func TestTransactionsAPI_FinalizeTransactionWithCustomSigner(t *testing.T) {
+	t.Run("FinalizeTransaction delegates to custom signer", func(t *testing.T) {
+		// given:
+		signer := &transactionSignerMock{hex: "signed-hex"}
+		wallet, _ := testutils.GivenSPVUserAPIWithSigner(t, signer)
+		draft := transactionstest.ExpectedDraftTransactionWithHex(t)
+
+		// when:
+		hex, err := wallet.FinalizeTransaction(draft)
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, "signed-hex", hex)
+		require.Same(t, draft, signer.draft)
+	})
+
+	t.Run("FinalizeTransaction rejects change foreign to the signer xPub", func(t *testing.T) {
+		// given:
+		signer := &transactionSignerMock{hex: "signed-hex"}
+		wallet, _ := testutils.GivenSPVUserAPIWithSigner(t, signer)
+
+		// when:
+		hex, err := wallet.FinalizeTransaction(transactionstest.ExpectedDraftTransactionWithForeignChange(t))
+
+		// then:
+		require.ErrorIs(t, err, errors.ErrDraftForeignChangeDestination)
+		require.Empty(t, hex)
+		require.Nil(t, signer.draft)
+	})
+
+	t.Run("NewUserAPIWithSigner requires the user xPub", func(t *testing.T) {
+		// given:
+		cfg := config.Config{Addr: testutils.TestAPIAddr}
+
+		// when:
+		wallet, err := spvwallet.NewUserAPIWithSigner(cfg, testutils.UserPrivAccessKey, "", &transactionSignerMock{})
+
+		// then:
+		require.Error(t, err)
+		require.Nil(t, wallet)
+	})
+
+	t.Run("FinalizeTransaction custom signer failure", func(t *testing.T) {
+		// given:
+		signerErr := fmt.Errorf("signing service unavailable")
+		wallet, _ := testutils.GivenSPVUserAPIWithSigner(t, &transactionSignerMock{err: signerErr})
+
+		// when:
+		hex, err := wallet.FinalizeTransaction(transactionstest.ExpectedDraftTransactionWithHex(t))
+
+		// then:
+		require.ErrorIs(t, err, signerErr)
+		require.Empty(t, hex)
+	})
+
+	t.Run("SendToRecipients records transaction signed by custom signer", func(t *testing.T) {
+		// given:
+		signer := &transactionSignerMock{hex: "signed-hex"}
+		wallet, transport := testutils.GivenSPVUserAPIWithSigner(t, signer)
+		transport.RegisterResponder(http.MethodPost, testutils.FullAPIURL(t, transactionDraftURL), testutils.NewJSONFileResponderWithStatusOK("transactionstest/transaction_draft_with_hex_200.json"))
+		transport.RegisterResponder(http.MethodPost, testutils.FullAPIURL(t, transactionsURL), func(r *http.Request) (*http.Response, error) {
+			var cmd commands.RecordTransaction
+			if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil || cmd.Hex != signer.hex {
+				return httpmock.NewJsonResponse(http.StatusBadRequest, testutils.NewBadRequestSPVError())
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, httpmock.File("transactionstest/transaction_send_to_recipients_200.json"))
+		})
+
+		// when:
+		result, err := wallet.SendToRecipients(context.Background(), &commands.SendToRecipients{
+			Recipients: []*commands.Recipients{{OpReturn: &response.OpReturn{StringParts: []string{"hello", "world"}}}},
+		})
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, transactionstest.ExpectedSendToRecipientsTransaction(t), result)
+		require.NotNil(t, signer.draft)
+	})
+}
+
+type transactionSignerMock struct {
+	draft *response.DraftTransaction
+	hex   string
+	err   error
+}
+
+func (t *transactionSignerMock) TransactionSignedHex(draft *response.DraftTransaction) (string, error) {
+	t.draft = draft
+	return t.hex, t.err
+}
@@ -3,10 +3,75 @@ package transactionstest
 import (
 	"testing"
 
+	"github.com/bitcoin-sv/go-sdk/script"
+	trx "github.com/bitcoin-sv/go-sdk/transaction"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
 	"github.com/bitcoin-sv/spv-wallet/models/response"
+	"github.com/stretchr/testify/require"
 )
 
+// foreignLockingScript is a P2PKH locking script which is not derived from testutils.UserXPub.
+const foreignLockingScript = "76a914702cef80a7039a1aebb70dc05ce1e439646fa33788ac"
+
+func ExpectedDraftTransactionWithSiphonOutput(t *testing.T) *response.DraftTransaction {
+	draftWithSiphonOutput := ExpectedDraftTransactionWithHex(t)
+	draftWithSiphonOutput.Configuration.ChangeSatoshis = 5
+	draftWithSiphonOutput.Hex = modifyDraftHex(t, draftWithSiphonOutput.Hex, func(tx *trx.Transaction) {
+		tx.Outputs[1].Satoshis = 5
+		tx.AddOutput(&trx.TransactionOutput{Satoshis: 3, LockingScript: lockingScript(t, foreignLockingScript)})
+	})
+	return draftWithSiphonOutput
+}
+
+func ExpectedDraftTransactionWithForeignChange(t *testing.T) *response.DraftTransaction {
+	draftWithForeignChange := ExpectedDraftTransactionWithHex(t)
+	draftWithForeignChange.Configuration.ChangeDestinations[0].LockingScript = foreignLockingScript
+	draftWithForeignChange.Hex = modifyDraftHex(t, draftWithForeignChange.Hex, func(tx *trx.Transaction) {
+		tx.Outputs[1].LockingScript = lockingScript(t, foreignLockingScript)
+	})
+	return draftWithForeignChange
+}
+
+func ExpectedDraftTransactionWithExcessiveFee(t *testing.T) *response.DraftTransaction {
+	draftWithExcessiveFee := ExpectedDraftTransactionWithHex(t)
+	draftWithExcessiveFee.Configuration.ChangeSatoshis = 5
+	draftWithExcessiveFee.Configuration.Outputs[1].Satoshis = 5
+	draftWithExcessiveFee.Configuration.Outputs[1].Scripts[0].Satoshis = 5
+	draftWithExcessiveFee.Hex = modifyDraftHex(t, draftWithExcessiveFee.Hex, func(tx *trx.Transaction) {
+		tx.Outputs[1].Satoshis = 5
+	})
+	return draftWithExcessiveFee
+}
+
+func ExpectedDraftTransactionWithTamperedOpReturn(t *testing.T) *response.DraftTransaction {
+	draftWithTamperedOpReturn := ExpectedDraftTransactionWithHex(t)
+	draftWithTamperedOpReturn.Hex = modifyDraftHex(t, draftWithTamperedOpReturn.Hex, func(tx *trx.Transaction) {
+		tx.Outputs[0].LockingScript = lockingScript(t, "006a0568656c6c6f05776f726c65")
+	})
+	return draftWithTamperedOpReturn
+}
+
+// ExpectedDraftTransactionWithTamperedOutputAndConfiguration returns the draft with the OP_RETURN output
+// changed both in the transaction and in the configuration, as a compromised server would do.
+func ExpectedDraftTransactionWithTamperedOutputAndConfiguration(t *testing.T) *response.DraftTransaction {
+	draftWithTamperedOutput := ExpectedDraftTransactionWithTamperedOpReturn(t)
+	draftWithTamperedOutput.Configuration.Outputs[0].Scripts[0].Script = "006a0568656c6c6f05776f726c65"
+	return draftWithTamperedOutput
+}
+
+func modifyDraftHex(t *testing.T, hex string, modify func(tx *trx.Transaction)) string {
+	tx, err := trx.NewTransactionFromHex(hex)
+	require.NoError(t, err)
+	modify(tx)
+	return tx.String()
+}
+
+func lockingScript(t *testing.T, hex string) *script.Script {
+	s, err := script.NewFromHex(hex)
+	require.NoError(t, err)
+	return s
+}
+
 func ExpectedDraftTransactionWithWrongInputs(t *testing.T) *response.DraftTransaction {
 	draftWithWrongInputs := ExpectedDraftTransactionWithHex(t)
 	draftWithWrongInputs.Configuration.Inputs[0].TransactionID = "wrong-input-transaction-id"
@@ -32,7 +97,7 @@ func ExpectedDraftTransactionWithHex(t *testing.T) *response.DraftTransaction {
 			UpdatedAt: testutils.ParseTime(t, "2024-12-02T13:04:33.855036+01:00"),
 		},
 		ID:        "de3b8ef7041b2a528bc47ecdb3b87b06b61407fe24789bc02f9d49bfc234b4d5",
-		Hex:       "01000000014c037d55e72d2ee6a95ff67bd758c4cee9c7545bb4d72ba77584152fcfa070120100000000ffffffff0200000000000000000e006a0568656c6c6f05776f726c6408000000000000001976a914702cef80a7039a1aebb70dc05ce1e439646fa33788ac00000000",
+		Hex:       "01000000014c037d55e72d2ee6a95ff67bd758c4cee9c7545bb4d72ba77584152fcfa070120100000000ffffffff0200000000000000000e006a0568656c6c6f05776f726c6408000000000000001976a914891ff0385511453144b045b23eaf785e9018421c88ac00000000",
 		XpubID:    "55e5aeae101bf7dc49db2abfccfab9fb5f56a6b594fdcc87e5f5a94bfe94b973",
 		ExpiresAt: testutils.ParseTime(t, "2024-12-02T12:04:53.840989Z"),
 		Configuration: response.TransactionConfig{
@@ -45,11 +110,11 @@ func ExpectedDraftTransactionWithHex(t *testing.T) *response.DraftTransaction {
 					},
 					ID:            "872a51f9eed774e7e5051cec19db192783521b5a9e0d4d814d46bdce338a32dc",
 					XpubID:        "55e5aeae101bf7dc49db2abfccfab9fb5f56a6b594fdcc87e5f5a94bfe94b973",
-					LockingScript: "76a914702cef80a7039a1aebb70dc05ce1e439646fa33788ac",
+					LockingScript: "76a914891ff0385511453144b045b23eaf785e9018421c88ac",
 					Type:          "pubkeyhash",
 					Chain:         1,
 					Num:           18,
-					Address:       "1BE8WfQkDDYE3zEgxBdRNuAxsnHkDcuPdT",
+					Address:       "1DW3qQ29i3rx4d7p5yypo44zbVs3oW6ozD",
 					DraftID:       "de3b8ef7041b2a528bc47ecdb3b87b06b61407fe24789bc02f9d49bfc234b4d5",
 				},
 			},
@@ -107,13 +172,13 @@ func ExpectedDraftTransactionWithHex(t *testing.T) *response.DraftTransaction {
 					Satoshis: 8,
 					Scripts: []*response.ScriptOutput{
 						{
-							Address:    "1BE8WfQkDDYE3zEgxBdRNuAxsnHkDcuPdT",
+							Address:    "1DW3qQ29i3rx4d7p5yypo44zbVs3oW6ozD",
 							Satoshis:   8,
-							Script:     "76a914702cef80a7039a1aebb70dc05ce1e439646fa33788ac",
+							Script:     "76a914891ff0385511453144b045b23eaf785e9018421c88ac",
 							ScriptType: "pubkeyhash",
 						},
 					},
-					To:           "1BE8WfQkDDYE3zEgxBdRNuAxsnHkDcuPdT",
+					To:           "1DW3qQ29i3rx4d7p5yypo44zbVs3oW6ozD",
 					UseForChange: false,
 				},
 			},
@@ -8,6 +8,7 @@ import (
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
 	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/xpubs/xpubstest"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/constants"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
 	"github.com/bitcoin-sv/spv-wallet/models/response"
 	"github.com/jarcoal/httpmock"
@@ -99,3 +100,23 @@ func TestXPubAPI_XPub(t *testing.T) {
 		})
 	}
 }
+
+func TestXPubAPI_XPubAPIError(t *testing.T) {
+	// given:
+	wallet, transport := testutils.GivenSPVUserAPI(t)
+	transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, xpubsURL), testutils.NewUnauthorizedAccessSPVErrorResponder())
+
+	// when:
+	got, err := wallet.XPub(context.Background())
+
+	// then:
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Nil(t, got)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, http.MethodGet, apiErr.Method)
+	require.Equal(t, constants.UserXPubsAPI, apiErr.Endpoint)
+	require.Equal(t, testutils.NewUnauthorizedAccessSPVError().Code, apiErr.Code)
+	require.True(t, errors.IsUnauthorized(err))
+	require.False(t, errors.IsRetryable(err))
+}
@@ -2,8 +2,10 @@ package auth
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
@@ -13,6 +15,11 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
+const (
+	contentTypeHeader = "Content-Type"
+	jsonContentType   = "application/json"
+)
+
 type XpubAuthenticator struct {
 	hdKey *bip32.ExtendedKey
 }
@@ -38,7 +45,11 @@ func (x *XprivAuthenticator) Authenticate(r *resty.Request) error {
 		return fmt.Errorf("failed to set xpub header: %w", err)
 	}
 
-	body := bodyString(r)
+	body, err := requestBody(r)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
 	header := make(http.Header)
 	err = setSignature(&header, x.xpriv, body)
 	if err != nil {
@@ -56,7 +67,11 @@ type AccessKeyAuthenticator struct {
 
 func (a *AccessKeyAuthenticator) Authenticate(r *resty.Request) error {
 	r.Header.Set(models.AuthAccessKey, a.pubKeyHex())
-	body := bodyString(r)
+	body, err := requestBody(r)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
 	sign, err := createSignatureAccessKey(a.privKeyHex(), body)
 	if err != nil {
 		return fmt.Errorf("failed to sign request with access key: %w", err)
@@ -74,12 +89,43 @@ func (a *AccessKeyAuthenticator) pubKeyHex() string {
 	return hex.EncodeToString(a.pub.SerializeCompressed())
 }
 
-func bodyString(r *resty.Request) string {
+// requestBody returns the request payload exactly as it will be transmitted.
+// Structured bodies (structs, maps, slices) and readers are serialized up front
+// and replaced with the resulting bytes, so resty sends the very same payload
+// that was hashed into the X-Auth-Hash header.
+func requestBody(r *resty.Request) (string, error) {
 	switch r.Method {
-	case http.MethodGet:
-		return ""
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "", nil
 	}
-	return ""
+
+	var bb []byte
+	switch body := r.Body.(type) {
+	case nil:
+		return "", nil
+	case []byte:
+		return string(body), nil
+	case string:
+		return body, nil
+	case io.Reader:
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body: %w", err)
+		}
+		bb = b
+	default:
+		b, err := json.Marshal(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal body to JSON: %w", err)
+		}
+		bb = b
+		if r.Header.Get(contentTypeHeader) == "" {
+			r.SetHeader(contentTypeHeader, jsonContentType)
+		}
+	}
+
+	r.SetBody(bb)
+	return string(bb), nil
 }
 
 func NewXprivAuthenticator(xpriv string) (*XprivAuthenticator, error) {
@@ -1,13 +1,18 @@
 package auth_test
 
 import (
+	"io"
 	"net/http"
 	"testing"
 
+	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
 	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/cryptoutil"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
 	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -97,6 +102,130 @@ func TestXpubOnlyAuthenticator_Authenticate(t *testing.T) {
 	requireXpubHeaderToBeSet(t, req.Header)
 }
 
+func TestAuthenticators_SignRequestBody(t *testing.T) {
+	tests := map[string]struct {
+		method       string
+		body         any
+		expectedBody string
+		expectedHash string
+	}{
+		"GET without body": {
+			method:       http.MethodGet,
+			expectedBody: "",
+			expectedHash: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		"POST record transaction command": {
+			method: http.MethodPost,
+			body: &commands.RecordTransaction{
+				Hex:         "0100",
+				ReferenceID: "ref-1",
+				Metadata:    map[string]any{"note": "<tip>"},
+			},
+			expectedBody: `{"metadata":{"note":"\u003ctip\u003e"},"hex":"0100","referenceId":"ref-1"}`,
+			expectedHash: "eeb7eb4eb2202457ba9f6683252e166c751cffd3b3013596eefe2797ec369aca",
+		},
+		"POST draft transaction command": {
+			method: http.MethodPost,
+			body: &commands.DraftTransaction{
+				Config: response.TransactionConfig{
+					Outputs: []*response.TransactionOutput{{To: "bob@example.com", Satoshis: 1000}},
+				},
+			},
+			expectedBody: `{"config":{"changeDestinations":null,"changeDestinationsStrategy":"","changeMinimumSatoshis":0,"changeNumberOfDestinations":0,"changeSatoshis":0,"expiresIn":0,"fee":0,"feeUnit":null,"fromUtxos":null,"includeUtxos":null,"inputs":null,"outputs":[{"satoshis":1000,"script":"","to":"bob@example.com","useForChange":false}],"sendAllTo":null,"sync":null},"metadata":null}`,
+			expectedHash: "2a270b7b717fa5c981a6abe8ffe0b24aed9d0191f584737801b28d147333e10a",
+		},
+		"PATCH map body": {
+			method:       http.MethodPatch,
+			body:         map[string]any{"metadata": map[string]any{"key": "value"}},
+			expectedBody: `{"metadata":{"key":"value"}}`,
+			expectedHash: cryptoutil.Hash(`{"metadata":{"key":"value"}}`),
+		},
+		"PUT byte slice body": {
+			method:       http.MethodPut,
+			body:         []byte(`{"fullName":"John Doe"}`),
+			expectedBody: `{"fullName":"John Doe"}`,
+			expectedHash: cryptoutil.Hash(`{"fullName":"John Doe"}`),
+		},
+		"DELETE string body": {
+			method:       http.MethodDelete,
+			body:         `{"url":"http://webhook1.com"}`,
+			expectedBody: `{"url":"http://webhook1.com"}`,
+			expectedHash: cryptoutil.Hash(`{"url":"http://webhook1.com"}`),
+		},
+	}
+
+	xPrivAuth, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	accessKeyAuth, err := auth.NewAccessKeyAuthenticator(testutils.UserPrivAccessKey)
+	require.NoError(t, err)
+
+	authenticators := map[string]authenticator{
+		"xPriv":      xPrivAuth,
+		"access key": accessKeyAuth,
+	}
+
+	for authName, a := range authenticators {
+		for name, tc := range tests {
+			t.Run(authName+" "+name, func(t *testing.T) {
+				// given:
+				client, transport := givenAuthenticatedClient(t, a)
+				var received *http.Request
+				var receivedBody []byte
+				transport.RegisterResponder(tc.method, testutils.FullAPIURL(t, "/api/v1/test"), func(r *http.Request) (*http.Response, error) {
+					received = r
+					if r.Body != nil {
+						receivedBody, _ = io.ReadAll(r.Body)
+					}
+					return httpmock.NewStringResponse(http.StatusOK, ""), nil
+				})
+
+				// when:
+				_, err := client.R().SetBody(tc.body).Execute(tc.method, "/api/v1/test")
+
+				// then:
+				require.NoError(t, err)
+				require.NotNil(t, received)
+				require.Equal(t, tc.expectedBody, string(receivedBody))
+				require.Equal(t, tc.expectedHash, received.Header.Get(xAuthHashKey))
+				require.Equal(t, cryptoutil.Hash(string(receivedBody)), received.Header.Get(xAuthHashKey))
+			})
+		}
+	}
+}
+
+func TestAuthenticators_SignRequestBodyRejectsUnmarshalableBody(t *testing.T) {
+	// given:
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	req := resty.New().R().SetBody(map[string]any{"invalid": make(chan int)})
+	req.Method = http.MethodPost
+
+	// when:
+	err = authenticator.Authenticate(req)
+
+	// then:
+	require.Error(t, err)
+}
+
+type authenticator interface {
+	Authenticate(r *resty.Request) error
+}
+
+func givenAuthenticatedClient(t *testing.T, a authenticator) (*resty.Client, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	client := resty.New().
+		SetTransport(transport).
+		SetBaseURL(testutils.TestAPIAddr).
+		OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+			return a.Authenticate(r)
+		})
+
+	return client, transport
+}
+
 func requireXAuthHeaderToBeSet(t *testing.T, h http.Header) {
 	require.Equal(t, []string{testutils.UserPubAccessKey}, h[xAuthKey])
 }
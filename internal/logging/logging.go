@@ -0,0 +1,238 @@
+// Package logging logs the HTTP requests sent to the SPV Wallet API with log/slog, redacting the secrets they carry.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/go-resty/resty/v2"
+)
+
+// Redacted replaces the logged secrets.
+const Redacted = "[REDACTED]"
+
+// Record attribute keys.
+const (
+	MethodKey          = "method"
+	PathKey            = "path"
+	StatusKey          = "status"
+	DurationKey        = "duration"
+	ErrorCodeKey       = "error_code"
+	ErrorKey           = "error"
+	RequestHeadersKey  = "request_headers"
+	RequestBodyKey     = "request_body"
+	ResponseHeadersKey = "response_headers"
+	ResponseBodyKey    = "response_body"
+)
+
+// authHeaderPrefix is the prefix of the headers carrying the request signature and the key material it is made with.
+const authHeaderPrefix = "X-Auth-"
+
+// secretFields are the JSON fields holding extended private keys, private access keys and webhook tokens.
+var secretFields = []string{"xpriv", "key", "tokenValue"}
+
+// extendedPrivateKey matches serialized mainnet and testnet BIP32 extended private keys.
+var extendedPrivateKey = regexp.MustCompile(`[xt]prv[1-9A-HJ-NP-Za-km-z]{100,108}`)
+
+// Middleware logs every HTTP request attempt with its method, path, response status, duration
+// and the SPV Wallet error code of a failed request, at the level matching the response status.
+// It must be the innermost middleware so that it logs the request exactly as it is sent.
+func Middleware(logger *slog.Logger, opts config.LogOptions) config.Middleware {
+	r := newRedactor(opts.MaskedFields)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attrs := []slog.Attr{slog.String(MethodKey, req.Method), slog.String(PathKey, req.URL.Path)}
+			if opts.Payloads {
+				attrs = append(attrs, slog.Any(RequestHeadersKey, r.headers(req.Header)))
+				if body := requestBody(req); len(body) > 0 {
+					attrs = append(attrs, slog.String(RequestBodyKey, r.body(body)))
+				}
+			}
+
+			start := time.Now()
+			res, err := next.RoundTrip(req)
+			attrs = append(attrs, slog.Duration(DurationKey, time.Since(start)))
+			if err != nil {
+				attrs = append(attrs, slog.String(ErrorKey, err.Error()))
+				logger.LogAttrs(req.Context(), opts.ServerErrorLevel, "HTTP request failed", attrs...)
+				return nil, err
+			}
+
+			attrs = append(attrs, slog.Int(StatusKey, res.StatusCode))
+			failed := res.StatusCode >= http.StatusBadRequest
+			if failed || opts.Payloads {
+				body := responseBody(res)
+				if code := errorCode(body); failed && code != "" {
+					attrs = append(attrs, slog.String(ErrorCodeKey, code))
+				}
+				if opts.Payloads {
+					attrs = append(attrs, slog.Any(ResponseHeadersKey, r.headers(res.Header)))
+					if len(body) > 0 {
+						attrs = append(attrs, slog.String(ResponseBodyKey, r.body(body)))
+					}
+				}
+			}
+
+			logger.LogAttrs(req.Context(), level(opts, res.StatusCode), "HTTP request", attrs...)
+			return res, nil
+		})
+	}
+}
+
+func level(opts config.LogOptions, status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return opts.ServerErrorLevel
+	case status >= http.StatusBadRequest:
+		return opts.ClientErrorLevel
+	default:
+		return opts.SuccessLevel
+	}
+}
+
+// requestBody returns a copy of the request body, nil if the body can't be read without consuming it.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+
+	body, _ := io.ReadAll(rc)
+	return body
+}
+
+// responseBody reads the response body and replaces it with a copy, so that it can still be read by the caller.
+func responseBody(res *http.Response) []byte {
+	if res.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+
+	return body
+}
+
+// errReader passes the error of a partially read response body on to the caller.
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	return 0, io.EOF
+}
+
+func errorCode(body []byte) string {
+	var spvErr struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &spvErr); err != nil {
+		return ""
+	}
+	return spvErr.Code
+}
+
+// redactor masks the secrets in the logged headers and bodies.
+type redactor struct {
+	fields map[string]struct{}
+}
+
+func newRedactor(masked []string) *redactor {
+	r := &redactor{fields: make(map[string]struct{}, len(secretFields)+len(masked))}
+	for _, f := range append(append([]string{}, secretFields...), masked...) {
+		r.fields[strings.ToLower(f)] = struct{}{}
+	}
+	return r
+}
+
+func (r *redactor) headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), authHeaderPrefix) {
+			out[name] = Redacted
+			continue
+		}
+		out[name] = extendedPrivateKey.ReplaceAllString(strings.Join(values, ", "), Redacted)
+	}
+	return out
+}
+
+// body masks the secret fields of a JSON body at any depth. Bodies that aren't JSON
+// are logged with only the extended private keys masked.
+func (r *redactor) body(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return extendedPrivateKey.ReplaceAllString(string(body), Redacted)
+	}
+
+	out, err := json.Marshal(r.value(v))
+	if err != nil {
+		return Redacted
+	}
+	return string(out)
+}
+
+func (r *redactor) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, field := range v {
+			if _, ok := r.fields[strings.ToLower(name)]; ok {
+				v[name] = Redacted
+				continue
+			}
+			v[name] = r.value(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = r.value(item)
+		}
+	case string:
+		return extendedPrivateKey.ReplaceAllString(v, Redacted)
+	}
+	return v
+}
+
+// RestyLogger routes the messages of the resty client, such as the retry attempts, to the logger.
+func RestyLogger(logger *slog.Logger) resty.Logger {
+	return restyLogger{logger: logger}
+}
+
+type restyLogger struct {
+	logger *slog.Logger
+}
+
+func (l restyLogger) Errorf(format string, v ...any) {
+	l.log(slog.LevelError, format, v...)
+}
+
+func (l restyLogger) Warnf(format string, v ...any) {
+	l.log(slog.LevelWarn, format, v...)
+}
+
+func (l restyLogger) Debugf(format string, v ...any) {
+	l.log(slog.LevelDebug, format, v...)
+}
+
+func (l restyLogger) log(level slog.Level, format string, v ...any) {
+	if !l.logger.Enabled(context.Background(), level) {
+		return
+	}
+	msg := strings.TrimSpace(fmt.Sprintf(format, v...))
+	l.logger.Log(context.Background(), level, extendedPrivateKey.ReplaceAllString(msg, Redacted))
+}
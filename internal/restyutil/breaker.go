@@ -0,0 +1,166 @@
+package restyutil
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/go-resty/resty/v2"
+)
+
+// circuitBreaker implements config.CircuitBreakerPolicy. Requests are admitted and their outcome
+// recorded by the middleware, for every attempt; the resty hook only rejects requests early,
+// before they wait for the rate limiter and get signed.
+type circuitBreaker struct {
+	policy config.CircuitBreakerPolicy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	state     config.CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int // probes in flight
+	successes int // successful probes since the circuit half-opened
+}
+
+func newCircuitBreaker(policy config.CircuitBreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{policy: policy, now: time.Now}
+}
+
+func setCircuitBreaker(c *resty.Client, b *circuitBreaker) *resty.Client {
+	if b == nil {
+		return c
+	}
+
+	return c.OnBeforeRequest(func(*resty.Client, *resty.Request) error {
+		return b.check()
+	})
+}
+
+// middleware fails the attempt fast when the circuit is open and records the outcome otherwise.
+func (b *circuitBreaker) middleware(next http.RoundTripper) http.RoundTripper {
+	return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		probe, err := b.allow()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := next.RoundTrip(req)
+		switch {
+		case req.Context().Err() != nil:
+			b.abandon(probe) // canceled by the caller, the SPV Wallet health is unknown
+		default:
+			b.record(probe, err == nil && res.StatusCode < http.StatusInternalServerError)
+		}
+		return res, err
+	})
+}
+
+// check rejects the request if the circuit is open, without changing the state.
+func (b *circuitBreaker) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	retryAt := b.openedAt.Add(b.policy.OpenTimeout)
+	if b.state == config.CircuitOpen && b.now().Before(retryAt) {
+		return &goclienterr.CircuitOpenError{RetryAt: retryAt}
+	}
+	return nil
+}
+
+// allow admits the attempt, half-opening the circuit once the open timeout elapsed.
+// Reports whether the attempt is a probe.
+func (b *circuitBreaker) allow() (bool, error) {
+	b.mu.Lock()
+	var transition stateTransition
+	defer func() {
+		b.mu.Unlock()
+		transition.notify(b.policy.OnStateChange)
+	}()
+
+	if b.state == config.CircuitOpen {
+		retryAt := b.openedAt.Add(b.policy.OpenTimeout)
+		if b.now().Before(retryAt) {
+			return false, &goclienterr.CircuitOpenError{RetryAt: retryAt}
+		}
+		transition = b.moveTo(config.CircuitHalfOpen)
+	}
+
+	if b.state == config.CircuitClosed {
+		return false, nil
+	}
+
+	if b.probes+b.successes >= b.policy.HalfOpenProbes {
+		return false, &goclienterr.CircuitOpenError{RetryAt: b.now()}
+	}
+	b.probes++
+	return true, nil
+}
+
+// record updates the state with the outcome of an admitted attempt. Outcomes of the attempts
+// admitted before the last state change are ignored.
+func (b *circuitBreaker) record(probe, success bool) {
+	b.mu.Lock()
+	var transition stateTransition
+	defer func() {
+		b.mu.Unlock()
+		transition.notify(b.policy.OnStateChange)
+	}()
+
+	switch {
+	case b.state == config.CircuitClosed && !probe:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			transition = b.moveTo(config.CircuitOpen)
+		}
+	case b.state == config.CircuitHalfOpen && probe:
+		b.probes--
+		if !success {
+			transition = b.moveTo(config.CircuitOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.policy.HalfOpenProbes {
+			transition = b.moveTo(config.CircuitClosed)
+		}
+	}
+}
+
+// abandon frees the probe slot of an attempt without an outcome.
+func (b *circuitBreaker) abandon(probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe && b.state == config.CircuitHalfOpen {
+		b.probes--
+	}
+}
+
+// moveTo changes the state and resets the counters; must be called with the lock held.
+func (b *circuitBreaker) moveTo(state config.CircuitState) stateTransition {
+	t := stateTransition{from: b.state, to: state}
+	b.state = state
+	b.failures, b.probes, b.successes = 0, 0, 0
+	if state == config.CircuitOpen {
+		b.openedAt = b.now()
+	}
+	return t
+}
+
+// stateTransition is reported to the callback after the lock is released,
+// so that the callback may use the client.
+type stateTransition struct {
+	from, to config.CircuitState
+}
+
+func (t stateTransition) notify(fn func(from, to config.CircuitState)) {
+	if fn != nil && t.from != t.to {
+		fn(t.from, t.to)
+	}
+}
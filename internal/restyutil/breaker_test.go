@@ -0,0 +1,142 @@
+package restyutil_test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+const openTimeout = 20 * time.Millisecond
+
+func TestNewHTTPClient_CircuitBreaker(t *testing.T) {
+	t.Run("Circuit opens after consecutive failures and fails fast", func(t *testing.T) {
+		// given:
+		client, transport, states := givenHTTPClientWithCircuitBreaker(t, 2)
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+		for range 2 {
+			_, err := client.R().Get("/test")
+			require.True(t, goclienterr.IsRetryable(err))
+		}
+
+		// when:
+		_, err := client.R().Get("/test")
+
+		// then:
+		var circuitErr *goclienterr.CircuitOpenError
+		require.ErrorAs(t, err, &circuitErr)
+		require.ErrorIs(t, err, goclienterr.ErrCircuitOpen)
+		require.WithinDuration(t, time.Now().Add(openTimeout), circuitErr.RetryAt, openTimeout)
+		require.Equal(t, 2, transport.GetTotalCallCount())
+		require.Equal(t, []string{"closed->open"}, states.get())
+	})
+
+	t.Run("Client errors keep the circuit closed", func(t *testing.T) {
+		// given:
+		client, transport, states := givenHTTPClientWithCircuitBreaker(t, 1)
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+		// when:
+		for range 3 {
+			_, err := client.R().Get("/test")
+			require.True(t, goclienterr.IsNotFound(err))
+		}
+
+		// then:
+		require.Equal(t, 3, transport.GetTotalCallCount())
+		require.Empty(t, states.get())
+	})
+
+	t.Run("Successful probe closes the circuit", func(t *testing.T) {
+		// given:
+		client, transport, states := givenHTTPClientWithCircuitBreaker(t, 1)
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.ResponderFromMultipleResponses([]*http.Response{
+			httpmock.NewStringResponse(http.StatusBadGateway, ""), //nolint: bodyclose
+			httpmock.NewStringResponse(http.StatusOK, "{}"),       //nolint: bodyclose
+			httpmock.NewStringResponse(http.StatusOK, "{}"),       //nolint: bodyclose
+		}))
+		_, err := client.R().Get("/test")
+		require.Error(t, err)
+
+		// when:
+		time.Sleep(openTimeout)
+		_, probeErr := client.R().Get("/test")
+		_, err = client.R().Get("/test")
+
+		// then:
+		require.NoError(t, probeErr)
+		require.NoError(t, err)
+		require.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, states.get())
+	})
+
+	t.Run("Failed probe opens the circuit again", func(t *testing.T) {
+		// given:
+		client, transport, states := givenHTTPClientWithCircuitBreaker(t, 1)
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewErrorResponder(errors.New("connection refused")))
+		_, err := client.R().Get("/test")
+		require.Error(t, err)
+
+		// when:
+		time.Sleep(openTimeout)
+		_, probeErr := client.R().Get("/test")
+		_, err = client.R().Get("/test")
+
+		// then:
+		require.Error(t, probeErr)
+		require.NotErrorIs(t, probeErr, goclienterr.ErrCircuitOpen)
+		require.ErrorIs(t, err, goclienterr.ErrCircuitOpen)
+		require.Equal(t, 2, transport.GetTotalCallCount())
+		require.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, states.get())
+	})
+
+	t.Run("Attempts rejected by the open circuit are not retried", func(t *testing.T) {
+		// given:
+		client, transport, _ := givenHTTPClientWithCircuitBreaker(t, 1, config.WithRetryPolicy(givenRetryPolicy()))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+
+		// when:
+		_, err := client.R().Get("/test")
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrCircuitOpen)
+		require.Equal(t, 1, transport.GetTotalCallCount())
+	})
+}
+
+type stateChanges struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (s *stateChanges) record(from, to config.CircuitState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changes = append(s.changes, from.String()+"->"+to.String())
+}
+
+func (s *stateChanges) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changes
+}
+
+func givenHTTPClientWithCircuitBreaker(t *testing.T, threshold int, opts ...config.Option) (*resty.Client, *httpmock.MockTransport, *stateChanges) {
+	t.Helper()
+
+	states := &stateChanges{}
+	client, transport := givenHTTPClientWithOptions(t, nil, append(opts, config.WithCircuitBreaker(config.CircuitBreakerPolicy{
+		FailureThreshold: threshold,
+		OpenTimeout:      openTimeout,
+		HalfOpenProbes:   1,
+		OnStateChange:    states.record,
+	}))...)
+	return client, transport, states
+}
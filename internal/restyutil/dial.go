@@ -0,0 +1,49 @@
+package restyutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// dialTransport returns the transport with the TLS settings, dialing the connections with the configured
+// dialer, or over the Unix domain socket of a unix:// address. It returns ErrConfigValidationInvalidTransport
+// when a dialer is needed but the transport is not an *http.Transport, as Config.Validate does, because
+// the requests would otherwise silently be sent over TCP.
+func dialTransport(cfg config.Config) (http.RoundTripper, error) {
+	tlsTransport, err := cfg.TLSTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	socket := cfg.SocketPath()
+	if socket == "" && cfg.DialContext == nil {
+		return tlsTransport, nil
+	}
+
+	base, ok := tlsTransport.(*http.Transport)
+	if !ok {
+		if tlsTransport != nil {
+			return nil, fmt.Errorf("%w: dialing the connections requires an *http.Transport", goclienterr.ErrConfigValidationInvalidTransport)
+		}
+		base = http.DefaultTransport.(*http.Transport)
+	}
+
+	dial := cfg.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	transport := base.Clone()
+	transport.DialContext = dial
+	if socket != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx, "unix", socket)
+		}
+	}
+	return transport, nil
+}
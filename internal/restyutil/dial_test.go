@@ -0,0 +1,161 @@
+package restyutil_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_UnixSocket(t *testing.T) {
+	t.Run("Requests are sent over the socket", func(t *testing.T) {
+		// given:
+		socket, paths := givenUnixSocketServer(t)
+		client := givenHTTPClientWithDialer(t, "unix://"+socket)
+
+		// when:
+		res, err := client.R().Get("/api/v1/users/current")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode())
+		require.Equal(t, "/api/v1/users/current", <-paths)
+	})
+
+	t.Run("Custom dialer dials the socket", func(t *testing.T) {
+		// given:
+		socket, paths := givenUnixSocketServer(t)
+		var dials atomic.Int32
+		dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials.Add(1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		}
+		client := givenHTTPClientWithDialer(t, "unix://"+socket, config.WithDialContext(dial))
+
+		// when:
+		_, err := client.R().Get("/api/v1/users/current")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, "/api/v1/users/current", <-paths)
+		require.Equal(t, int32(1), dials.Load())
+	})
+}
+
+func TestNewHTTPClient_DialContext(t *testing.T) {
+	// given:
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	var dialed atomic.Value
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed.Store(addr)
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+	client := givenHTTPClientWithDialer(t, server.URL, config.WithDialContext(dial))
+
+	// when:
+	res, err := client.R().Get(server.URL + "/api/v1/users/current")
+
+	// then:
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+	require.Equal(t, server.Listener.Addr().String(), dialed.Load())
+}
+
+func TestNewHTTPClient_TLS(t *testing.T) {
+	// given:
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	t.Run("TLS settings of a Config literal are applied", func(t *testing.T) {
+		// given:
+		client, err := restyutil.NewHTTPClient(config.Config{
+			Addr:    server.URL,
+			Timeout: 5 * time.Second,
+			TLS:     &config.TLSConfig{CAPEM: [][]byte{serverCA}, PinnedPublicKeys: []string{config.PublicKeyPin(server.Certificate())}},
+		}, authenticator)
+		require.NoError(t, err)
+
+		// when:
+		res, err := client.R().Get("/api/v1/users/current")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode())
+	})
+
+	t.Run("Invalid TLS settings of a Config literal", func(t *testing.T) {
+		// when:
+		_, err := restyutil.NewHTTPClient(config.Config{
+			Addr: server.URL,
+			TLS:  &config.TLSConfig{PinnedPublicKeys: []string{"not-a-pin"}},
+		}, authenticator)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrConfigValidationInvalidTLS)
+	})
+}
+
+// givenUnixSocketServer starts an HTTP server listening on a Unix domain socket and
+// returns the socket path along with the channel receiving the paths of the served requests.
+func givenUnixSocketServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	// Socket paths are limited to about 100 bytes, too short for t.TempDir.
+	dir, err := os.MkdirTemp("", "spv")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "wallet.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	paths := make(chan string, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return socket, paths
+}
+
+func givenHTTPClientWithDialer(t *testing.T, addr string, opts ...config.Option) *resty.Client {
+	t.Helper()
+
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	cfg, err := config.NewE(append([]config.Option{config.WithAddr(addr), config.WithTimeout(5 * time.Second)}, opts...)...)
+	require.NoError(t, err)
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,171 @@
+package restyutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+)
+
+// failover implements config.FailoverPolicy, sending the request attempts built for the primary
+// address to the first healthy one.
+type failover struct {
+	policy  config.FailoverPolicy
+	primary string
+	now     func() time.Time
+
+	mu    sync.Mutex
+	addrs []*replica // the primary address first, then the replicas in order of preference
+}
+
+type replica struct {
+	base    string
+	healthy bool
+	probeAt time.Time
+	probing bool
+}
+
+func newFailover(addr string, policy config.FailoverPolicy) *failover {
+	f := &failover{policy: policy, primary: strings.TrimSuffix(addr, "/"), now: time.Now}
+	for _, base := range append([]string{addr}, policy.Replicas...) {
+		f.addrs = append(f.addrs, &replica{base: strings.TrimSuffix(base, "/"), healthy: true})
+	}
+	return f
+}
+
+func (f *failover) middleware(next http.RoundTripper) http.RoundTripper {
+	return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		path, ok := strings.CutPrefix(req.URL.String(), f.primary)
+		if !ok {
+			return next.RoundTrip(req) // not an SPV Wallet API request
+		}
+
+		candidates := f.candidates(next)
+		for i, r := range candidates {
+			attempt, err := rewrite(req, r.base+path, i > 0)
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := next.RoundTrip(attempt)
+			if req.Context().Err() != nil {
+				return res, err // canceled by the caller, the address health is unknown
+			}
+
+			failed := err != nil || res.StatusCode >= http.StatusInternalServerError
+			f.report(r, !failed)
+			if !failed || i == len(candidates)-1 || !canFailOver(req, err) {
+				return res, err
+			}
+			if res != nil {
+				_, _ = io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+			}
+		}
+		return nil, errors.New("no SPV Wallet address to send the request to") // unreachable, there is always the primary address
+	})
+}
+
+// candidates returns the healthy addresses followed by the unhealthy ones, starting
+// the probes of the unhealthy addresses whose probe is due.
+func (f *failover) candidates(next http.RoundTripper) []*replica {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	healthy := make([]*replica, 0, len(f.addrs))
+	var unhealthy []*replica
+	for _, r := range f.addrs {
+		if r.healthy {
+			healthy = append(healthy, r)
+			continue
+		}
+
+		unhealthy = append(unhealthy, r)
+		if !r.probing && !now.Before(r.probeAt) {
+			r.probing = true
+			go f.probe(next, r)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (f *failover) report(r *replica, healthy bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if healthy {
+		r.healthy = true
+		return
+	}
+	if r.healthy {
+		r.healthy = false
+		r.probeAt = f.now().Add(f.policy.ProbeInterval)
+	}
+}
+
+// probe checks whether the unhealthy address recovered.
+func (f *failover) probe(next http.RoundTripper, r *replica) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.policy.ProbeTimeout)
+	defer cancel()
+
+	healthy := false
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.base+f.policy.ProbePath, nil)
+	if err == nil {
+		res, err := next.RoundTrip(req)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+			healthy = res.StatusCode < http.StatusInternalServerError
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r.probing = false
+	r.healthy = healthy
+	if !healthy {
+		r.probeAt = f.now().Add(f.policy.ProbeInterval)
+	}
+}
+
+// rewrite clones the request for the address, with a fresh body if the original one may have been consumed.
+func rewrite(req *http.Request, rawURL string, resend bool) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := req.Clone(req.Context())
+	attempt.URL = u
+	attempt.Host = ""
+	if resend && req.Body != nil && req.Body != http.NoBody {
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
+
+// canFailOver reports whether the failed attempt may be sent to another address: safe requests
+// can always be sent again, the other ones only if they never reached the server.
+func canFailOver(req *http.Request, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,171 @@
+package restyutil_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Failover(t *testing.T) {
+	t.Run("Safe request fails over on 5xx and skips the unhealthy address", func(t *testing.T) {
+		// given:
+		primary := newReplicaServer(t, http.StatusServiceUnavailable)
+		replica := newReplicaServer(t, http.StatusOK)
+		client := givenHTTPClientWithReplicas(t, primary.URL+"/api", replica.URL+"/api")
+
+		// when:
+		for range 2 {
+			_, err := client.R().Get(primary.URL + "/api/v1/users/current")
+			require.NoError(t, err)
+		}
+
+		// then:
+		require.Equal(t, int32(1), primary.hits.Load())
+		require.Equal(t, int32(2), replica.hits.Load())
+		require.Equal(t, "/api/v1/users/current", replica.last().path)
+		require.NotEmpty(t, replica.last().signature)
+	})
+
+	t.Run("Unsafe request doesn't fail over on 5xx", func(t *testing.T) {
+		// given:
+		primary := newReplicaServer(t, http.StatusServiceUnavailable)
+		replica := newReplicaServer(t, http.StatusOK)
+		client := givenHTTPClientWithReplicas(t, primary.URL, replica.URL)
+
+		// when:
+		_, err := client.R().SetBody(map[string]string{"key": "value"}).Post(primary.URL + "/api/v1/transactions")
+
+		// then:
+		require.True(t, goclienterr.IsRetryable(err))
+		require.Equal(t, int32(1), primary.hits.Load())
+		require.Zero(t, replica.hits.Load())
+	})
+
+	t.Run("Unsafe request fails over when the connection is refused", func(t *testing.T) {
+		// given:
+		down := newReplicaServer(t, http.StatusOK)
+		down.Close()
+		replica := newReplicaServer(t, http.StatusOK)
+		client := givenHTTPClientWithReplicas(t, down.URL, replica.URL)
+
+		// when:
+		_, err := client.R().SetBody(map[string]string{"key": "value"}).Post(down.URL + "/api/v1/transactions")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, int32(1), replica.hits.Load())
+		require.JSONEq(t, `{"key":"value"}`, replica.last().body)
+	})
+
+	t.Run("Recovered address is used again after a successful probe", func(t *testing.T) {
+		// given:
+		primary := newReplicaServer(t, http.StatusServiceUnavailable)
+		replica := newReplicaServer(t, http.StatusOK)
+		client := givenHTTPClientWithReplicas(t, primary.URL, replica.URL)
+		_, err := client.R().Get(primary.URL + "/api/v1/users/current")
+		require.NoError(t, err)
+
+		// when:
+		primary.status.Store(http.StatusOK)
+
+		// then:
+		require.Eventually(t, func() bool {
+			if _, err := client.R().Get(primary.URL + "/api/v1/users/current"); err != nil {
+				return false
+			}
+			last := primary.last()
+			return last.path == "/api/v1/users/current" && last.status == http.StatusOK
+		}, time.Second, 5*time.Millisecond)
+		require.Contains(t, primary.paths(), "/health")
+	})
+}
+
+// replicaServer responds with the set status and records the requests it receives.
+type replicaServer struct {
+	*httptest.Server
+	hits   atomic.Int32 // requests other than probes
+	status atomic.Int32
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	path, signature, body string
+	status                int
+}
+
+func newReplicaServer(t *testing.T, status int) *replicaServer {
+	t.Helper()
+
+	s := &replicaServer{}
+	s.status.Store(int32(status))
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := int(s.status.Load())
+		body, _ := io.ReadAll(r.Body)
+		if !strings.HasSuffix(r.URL.Path, "/health") {
+			s.hits.Add(1)
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, recordedRequest{path: r.URL.Path, signature: r.Header.Get(models.AuthSignature), body: string(body), status: status})
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *replicaServer) last() recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return recordedRequest{}
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *replicaServer) paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := make([]string, 0, len(s.requests))
+	for _, r := range s.requests {
+		paths = append(paths, r.path)
+	}
+	return paths
+}
+
+func givenHTTPClientWithReplicas(t *testing.T, addr string, replicas ...string) *resty.Client {
+	t.Helper()
+
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	policy := config.DefaultFailoverPolicy(replicas...)
+	policy.ProbeInterval = 5 * time.Millisecond
+	cfg, err := config.NewE(config.WithAddr(addr), config.WithTimeout(5*time.Second), config.WithFailoverPolicy(policy))
+	require.NoError(t, err)
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,135 @@
+package restyutil_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/logging"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Logger(t *testing.T) {
+	tests := map[string]struct {
+		status        int
+		responseBody  string
+		expectedLevel string
+		expectedCode  string
+	}{
+		"Successful request logged at debug level": {
+			status:        http.StatusOK,
+			responseBody:  `{"id":"1"}`,
+			expectedLevel: "DEBUG",
+		},
+		"Client error logged at warn level with error code": {
+			status:        http.StatusNotFound,
+			responseBody:  `{"code":"error-not-found","message":"not found"}`,
+			expectedLevel: "WARN",
+			expectedCode:  "error-not-found",
+		},
+		"Server error logged at error level with error code": {
+			status:        http.StatusInternalServerError,
+			responseBody:  `{"code":"error-internal","message":"internal error"}`,
+			expectedLevel: "ERROR",
+			expectedCode:  "error-internal",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			var buf bytes.Buffer
+			client, transport := givenHTTPClientWithLogger(t, &buf)
+			transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(tc.status, tc.responseBody))
+
+			// when:
+			_, _ = client.R().Get("/test")
+
+			// then:
+			record := decodeLogRecord(t, &buf)
+			require.Equal(t, tc.expectedLevel, record["level"])
+			require.Equal(t, http.MethodGet, record[logging.MethodKey])
+			require.Equal(t, "/test", record[logging.PathKey])
+			require.EqualValues(t, tc.status, record[logging.StatusKey])
+			require.Contains(t, record, logging.DurationKey)
+			if tc.expectedCode == "" {
+				require.NotContains(t, record, logging.ErrorCodeKey)
+			} else {
+				require.Equal(t, tc.expectedCode, record[logging.ErrorCodeKey])
+			}
+		})
+	}
+
+	t.Run("Secrets redacted from the logged payloads", func(t *testing.T) {
+		// given:
+		var buf bytes.Buffer
+		client, transport := givenHTTPClientWithLogger(t, &buf, config.WithLogPayloads(), config.WithMaskedFields("email"))
+		transport.RegisterResponder(http.MethodPost, testutils.FullAPIURL(t, "/test"),
+			httpmock.NewStringResponder(http.StatusCreated, `{"key":"access-key-secret","metadata":{"email":"john@example.com","note":"ok"}}`))
+
+		// when:
+		res, err := client.R().
+			SetBody(map[string]any{
+				"xpriv":      testutils.UserXPriv,
+				"tokenValue": "webhook-secret",
+				"metadata":   map[string]any{"Email": "john@example.com", "note": "key " + testutils.UserXPriv},
+			}).
+			Post("/test")
+
+		// then:
+		require.NoError(t, err)
+		require.Contains(t, res.String(), "access-key-secret", "response body consumed by the logger")
+
+		logged := buf.String()
+		for _, secret := range []string{testutils.UserXPriv, "webhook-secret", "access-key-secret", "john@example.com", res.Request.Header.Get(models.AuthSignature)} {
+			require.NotContains(t, logged, secret)
+		}
+
+		record := decodeLogRecord(t, &buf)
+		headers, ok := record[logging.RequestHeadersKey].(map[string]any)
+		require.True(t, ok)
+		require.Equal(t, logging.Redacted, headers["X-Auth-Signature"])
+		require.Equal(t, logging.Redacted, headers["X-Auth-Xpub"])
+		require.Equal(t, `{"metadata":{"Email":"[REDACTED]","note":"key [REDACTED]"},"tokenValue":"[REDACTED]","xpriv":"[REDACTED]"}`, record[logging.RequestBodyKey])
+		require.Equal(t, `{"key":"[REDACTED]","metadata":{"email":"[REDACTED]","note":"ok"}}`, record[logging.ResponseBodyKey])
+	})
+}
+
+func givenHTTPClientWithLogger(t *testing.T, buf *bytes.Buffer, opts ...config.LogOption) (*resty.Client, *httpmock.MockTransport) {
+	t.Helper()
+
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	transport := httpmock.NewMockTransport()
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg := config.New(
+		config.WithAddr(testutils.TestAPIAddr),
+		config.WithTimeout(5*time.Second),
+		config.WithTransport(transport),
+		config.WithLogger(logger, opts...),
+	)
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client, transport
+}
+
+func decodeLogRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var record map[string]any
+	require.NoError(t, json.NewDecoder(buf).Decode(&record))
+	return record
+}
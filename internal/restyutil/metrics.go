@@ -0,0 +1,53 @@
+package restyutil
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
+	"github.com/go-resty/resty/v2"
+)
+
+// metricsMiddleware records every request attempt, including the ones short-circuited by the middlewares it wraps.
+func metricsMiddleware(m config.Metrics) config.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			endpoint, _ := tracing.Endpoint(req.Context())
+			start := time.Now()
+			res, err := next.RoundTrip(req)
+
+			status := 0
+			if err == nil {
+				status = res.StatusCode
+			}
+			m.ObserveRequest(endpoint, req.Method, status, time.Since(start))
+			return res, err
+		})
+	}
+}
+
+func authenticate(auth Authenticator, m config.Metrics) resty.RequestMiddleware {
+	return func(_ *resty.Client, r *resty.Request) error {
+		if m == nil {
+			return auth.Authenticate(r)
+		}
+
+		start := time.Now()
+		err := auth.Authenticate(r)
+		m.ObserveSigning(time.Since(start), err)
+		return err
+	}
+}
+
+// observeRetry records the retried attempts. Resty runs the retry hooks for the last attempt too,
+// and for transport failures even without a retry policy, so those are skipped.
+func observeRetry(c *resty.Client, m config.Metrics) resty.OnRetryFunc {
+	return func(r *resty.Response, _ error) {
+		if r == nil || r.Request == nil || r.Request.Attempt > c.RetryCount {
+			return
+		}
+		endpoint, _ := tracing.Endpoint(r.Request.Context())
+		m.ObserveRetry(endpoint, r.Request.Method)
+	}
+}
@@ -0,0 +1,112 @@
+package restyutil_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Metrics(t *testing.T) {
+	tests := map[string]struct {
+		responses        []*http.Response
+		policy           *config.RetryPolicy
+		expectedStatuses []int
+		expectedRetries  int
+	}{
+		"Successful request": {
+			responses:        []*http.Response{httpmock.NewStringResponse(http.StatusOK, "{}")}, //nolint: bodyclose
+			expectedStatuses: []int{http.StatusOK},
+		},
+		"Failed request without retry policy": {
+			responses:        []*http.Response{httpmock.NewStringResponse(http.StatusServiceUnavailable, "")}, //nolint: bodyclose
+			expectedStatuses: []int{http.StatusServiceUnavailable},
+		},
+		"Request retried until success": {
+			responses: []*http.Response{
+				httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), //nolint: bodyclose
+				httpmock.NewStringResponse(http.StatusOK, "{}"),               //nolint: bodyclose
+			},
+			policy:           givenRetryPolicyPtr(),
+			expectedStatuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRetries:  1,
+		},
+		"Retries exhausted": {
+			responses: []*http.Response{
+				httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), //nolint: bodyclose
+				httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), //nolint: bodyclose
+				httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), //nolint: bodyclose
+			},
+			policy:           givenRetryPolicyPtr(),
+			expectedStatuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			expectedRetries:  2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+			require.NoError(t, err)
+
+			recorder := &recordingMetrics{}
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.ResponderFromMultipleResponses(tc.responses))
+			client, err := restyutil.NewHTTPClient(config.Config{
+				Addr:      testutils.TestAPIAddr,
+				Timeout:   5 * time.Second,
+				Transport: transport,
+				Retry:     tc.policy,
+				Metrics:   recorder,
+			}, authenticator)
+			require.NoError(t, err)
+
+			// when:
+			_, _ = client.R().Get("/test")
+
+			// then:
+			require.Equal(t, tc.expectedStatuses, recorder.statuses)
+			require.Equal(t, tc.expectedRetries, recorder.retries)
+			require.Equal(t, len(tc.expectedStatuses), recorder.signings)
+		})
+	}
+}
+
+func givenRetryPolicyPtr() *config.RetryPolicy {
+	policy := givenRetryPolicy()
+	return &policy
+}
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	statuses []int
+	retries  int
+	signings int
+}
+
+func (m *recordingMetrics) ObserveRequest(_, _ string, statusCode int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, statusCode)
+}
+
+func (m *recordingMetrics) ObserveRetry(_, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *recordingMetrics) ObserveSigning(_ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		m.signings++
+	}
+}
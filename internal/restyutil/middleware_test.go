@@ -0,0 +1,154 @@
+package restyutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Middleware(t *testing.T) {
+	t.Run("Middlewares run in registration order after signing", func(t *testing.T) {
+		// given:
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+		record := func(name string) config.Middleware {
+			return func(next http.RoundTripper) http.RoundTripper {
+				return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+					require.NotEmpty(t, req.Header.Get(models.AuthSignature), "middleware called before signing")
+					mu.Lock()
+					calls = append(calls, name+" request")
+					mu.Unlock()
+
+					req.Header.Set("X-Tenant", name)
+					res, err := next.RoundTrip(req)
+
+					mu.Lock()
+					calls = append(calls, name+" response")
+					mu.Unlock()
+					return res, err
+				})
+			}
+		}
+		client, transport := givenHTTPClientWithMiddleware(t, nil, record("first"), record("second"))
+		transport.RegisterResponder(http.MethodPost, testutils.FullAPIURL(t, "/test"), func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "second", req.Header.Get("X-Tenant"))
+			return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+		})
+
+		// when:
+		_, err := client.R().SetBody(map[string]string{"key": "value"}).Post("/test")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, []string{"first request", "second request", "second response", "first response"}, calls)
+	})
+
+	t.Run("Short-circuit response is mapped to APIError", func(t *testing.T) {
+		// given:
+		reject := func(http.RoundTripper) http.RoundTripper {
+			return config.RoundTripFunc(func(*http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusForbidden, "tenant suspended"), nil
+			})
+		}
+		client, transport := givenHTTPClientWithMiddleware(t, nil, reject)
+
+		// when:
+		_, err := client.R().Get("/test")
+
+		// then:
+		require.True(t, goclienterr.IsUnauthorized(err))
+		require.Empty(t, transport.GetCallCountInfo())
+	})
+
+	t.Run("Middlewares run for every retry attempt", func(t *testing.T) {
+		// given:
+		var attempts int
+		count := func(next http.RoundTripper) http.RoundTripper {
+			return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return next.RoundTrip(req)
+			})
+		}
+		policy := givenRetryPolicy()
+		client, transport := givenHTTPClientWithMiddleware(t, &policy, count)
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.ResponderFromMultipleResponses([]*http.Response{
+			httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), //nolint: bodyclose
+			httpmock.NewStringResponse(http.StatusOK, "{}"),               //nolint: bodyclose
+		}))
+
+		// when:
+		_, err := client.R().Get("/test")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("Middlewares wrap the default transport when none is configured", func(t *testing.T) {
+		// given:
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("{}"))
+		}))
+		defer server.Close()
+
+		var calls int
+		count := func(next http.RoundTripper) http.RoundTripper {
+			return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return next.RoundTrip(req)
+			})
+		}
+		authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+		require.NoError(t, err)
+		client, err := restyutil.NewHTTPClient(config.Config{
+			Addr:       server.URL,
+			Timeout:    5 * time.Second,
+			Middleware: []config.Middleware{count},
+		}, authenticator)
+		require.NoError(t, err)
+
+		// when:
+		res, err := client.R().Get("/test")
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode())
+		require.Equal(t, 1, calls)
+	})
+}
+
+func givenHTTPClientWithMiddleware(t *testing.T, policy *config.RetryPolicy, middlewares ...config.Middleware) (*resty.Client, *httpmock.MockTransport) {
+	t.Helper()
+
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:       testutils.TestAPIAddr,
+		Timeout:    5 * time.Second,
+		Transport:  transport,
+		Retry:      policy,
+		Middleware: middlewares,
+	}
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client, transport
+}
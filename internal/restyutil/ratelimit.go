@@ -0,0 +1,131 @@
+package restyutil
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+type releaseKey struct{}
+
+// rateLimiter holds back the requests over the rate limit policy. A request waits for
+// a free in-flight slot and a token before it is signed, so that its auth time doesn't
+// get stale while waiting, and gives the slot back once its response is received.
+type rateLimiter struct {
+	fallback  *limiter
+	endpoints map[string]*limiter
+}
+
+func newRateLimiter(p config.RateLimitPolicy) *rateLimiter {
+	rl := &rateLimiter{fallback: newLimiter(p.Default), endpoints: make(map[string]*limiter, len(p.Endpoints))}
+	for endpoint, limit := range p.Endpoints {
+		rl.endpoints[endpoint] = newLimiter(limit)
+	}
+	return rl
+}
+
+func setRateLimiter(c *resty.Client, p *config.RateLimitPolicy) *resty.Client {
+	if p == nil {
+		return c
+	}
+
+	rl := newRateLimiter(*p)
+	return c.
+		OnBeforeRequest(rl.wait).
+		OnError(func(r *resty.Request, _ error) {
+			release(r.Context()) // the request failed before it was sent, e.g. authentication failed
+		})
+}
+
+// wait blocks the request until it fits in the limit of its endpoint or its context is done.
+func (rl *rateLimiter) wait(_ *resty.Client, r *resty.Request) error {
+	ctx := r.Context()
+	l := rl.fallback
+	if endpoint, ok := tracing.Endpoint(ctx); ok {
+		if override, ok := rl.endpoints[endpoint]; ok {
+			l = override
+		}
+	}
+
+	releaseFn, err := l.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.SetContext(context.WithValue(ctx, releaseKey{}, releaseFn))
+	return nil
+}
+
+// releaseMiddleware gives the in-flight slot back once the response is received.
+// It is the outermost middleware, so it releases the slot of short-circuited requests too.
+func releaseMiddleware(next http.RoundTripper) http.RoundTripper {
+	return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		defer release(req.Context())
+		return next.RoundTrip(req)
+	})
+}
+
+func release(ctx context.Context) {
+	if fn, ok := ctx.Value(releaseKey{}).(func()); ok {
+		fn()
+	}
+}
+
+type limiter struct {
+	bucket *rate.Limiter
+	slots  chan struct{}
+}
+
+func newLimiter(l config.RateLimit) *limiter {
+	lim := &limiter{}
+	if l.RequestsPerSecond > 0 {
+		lim.bucket = rate.NewLimiter(rate.Limit(l.RequestsPerSecond), l.Burst)
+	}
+	if l.MaxInFlight > 0 {
+		lim.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// acquire waits for a free in-flight slot, then for a token. It returns the function giving
+// the slot back, safe to call more than once, or the context error if the context is done first.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	releaseFn := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		var once sync.Once
+		releaseFn = func() { once.Do(func() { <-l.slots }) }
+	}
+
+	if l.bucket == nil {
+		return releaseFn, nil
+	}
+
+	reservation := l.bucket.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return releaseFn, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return releaseFn, nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		releaseFn()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,162 @@
+package restyutil_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_RateLimit(t *testing.T) {
+	t.Run("Requests over the rate wait for a token", func(t *testing.T) {
+		// given:
+		client, transport := givenHTTPClientWithOptions(t, nil, config.WithRateLimit(config.RateLimit{RequestsPerSecond: 50, Burst: 1}))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusOK, "{}"))
+
+		// when:
+		start := time.Now()
+		for range 3 {
+			_, err := client.R().Get("/test")
+			require.NoError(t, err)
+		}
+
+		// then:
+		require.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	})
+
+	t.Run("Waiting stops when the context is done", func(t *testing.T) {
+		// given:
+		client, transport := givenHTTPClientWithOptions(t, nil, config.WithRateLimit(config.RateLimit{RequestsPerSecond: 1, Burst: 1}))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusOK, "{}"))
+		_, err := client.R().Get("/test")
+		require.NoError(t, err)
+
+		// when:
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = client.R().SetContext(ctx).Get("/test")
+
+		// then:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("Requests in flight capped", func(t *testing.T) {
+		// given:
+		var inFlight, maxInFlight atomic.Int32
+		client, transport := givenHTTPClientWithOptions(t, nil, config.WithRateLimit(config.RateLimit{MaxInFlight: 2}))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), func(*http.Request) (*http.Response, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+		})
+
+		// when:
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.R().Get("/test")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// then:
+		require.Equal(t, int32(2), maxInFlight.Load())
+		require.Equal(t, 6, transport.GetTotalCallCount())
+	})
+
+	t.Run("Endpoint override replaces the default limit", func(t *testing.T) {
+		// given:
+		client, transport := givenHTTPClientWithOptions(t, nil,
+			config.WithRateLimit(config.RateLimit{RequestsPerSecond: 1, Burst: 1}),
+			config.WithEndpointRateLimit("admin/xpubs", config.RateLimit{MaxInFlight: 1}))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusOK, "{}"))
+		ctx, span := tracing.NewTracer(nil).Start(context.Background(), "AdminAPI.XPubs", "admin/xpubs")
+		defer span.End()
+
+		// when:
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		for range 3 {
+			_, err := client.R().SetContext(ctx).Get("/test")
+			require.NoError(t, err)
+		}
+
+		// then:
+		require.Equal(t, 3, transport.GetTotalCallCount())
+	})
+
+	t.Run("In-flight slot released when signing fails", func(t *testing.T) {
+		// given:
+		authenticator := &failingOnceAuthenticator{err: errors.New("signing failed")}
+		client, transport := givenHTTPClientWithOptions(t, authenticator, config.WithRateLimit(config.RateLimit{MaxInFlight: 1}))
+		transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewStringResponder(http.StatusOK, "{}"))
+		_, err := client.R().Get("/test")
+		require.ErrorIs(t, err, authenticator.err)
+
+		// when:
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = client.R().SetContext(ctx).Get("/test")
+
+		// then:
+		require.NoError(t, err)
+	})
+}
+
+type failingOnceAuthenticator struct {
+	err    error
+	failed atomic.Bool
+}
+
+func (a *failingOnceAuthenticator) Authenticate(*resty.Request) error {
+	if a.failed.CompareAndSwap(false, true) {
+		return a.err
+	}
+	return nil
+}
+
+func givenHTTPClientWithOptions(t *testing.T, authenticator restyutil.Authenticator, opts ...config.Option) (*resty.Client, *httpmock.MockTransport) {
+	t.Helper()
+
+	if authenticator == nil {
+		xprivAuth, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+		require.NoError(t, err)
+		authenticator = xprivAuth
+	}
+
+	transport := httpmock.NewMockTransport()
+	cfg := config.New(append([]config.Option{
+		config.WithAddr(testutils.TestAPIAddr),
+		config.WithTimeout(5 * time.Second),
+		config.WithTransport(transport),
+	}, opts...)...)
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client, transport
+}
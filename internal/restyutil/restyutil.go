@@ -5,6 +5,8 @@ import (
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
 	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/logging"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
 	"github.com/bitcoin-sv/spv-wallet/models"
 	"github.com/go-resty/resty/v2"
 )
@@ -13,24 +15,85 @@ type Authenticator interface {
 	Authenticate(r *resty.Request) error
 }
 
-func NewHTTPClient(cfg config.Config, auth Authenticator) *resty.Client {
-	return resty.New().
-		SetTransport(cfg.Transport).
-		SetBaseURL(cfg.Addr).
-		SetTimeout(cfg.Timeout).
-		OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
-			return auth.Authenticate(r)
-		}).
+// NewHTTPClient returns the HTTP client sending the requests to the SPV Wallet API with the configuration.
+// It returns an error if the TLS settings of the configuration can't be applied.
+func NewHTTPClient(cfg config.Config, auth Authenticator) (*resty.Client, error) {
+	transport, err := dialTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := cfg.Addr
+	if u, err := cfg.BaseURL(); err == nil {
+		baseURL = u.String()
+	}
+
+	var breaker *circuitBreaker
+	var middleware []config.Middleware
+	if cfg.RateLimit != nil {
+		middleware = append(middleware, releaseMiddleware)
+	}
+	if cfg.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(*cfg.CircuitBreaker)
+		middleware = append(middleware, breaker.middleware)
+	}
+	if cfg.TracerProvider != nil {
+		middleware = append(middleware, tracing.Middleware(cfg.TracerProvider))
+	}
+	if cfg.Metrics != nil {
+		middleware = append(middleware, metricsMiddleware(cfg.Metrics))
+	}
+	middleware = append(middleware, cfg.Middleware...)
+	if cfg.Failover != nil {
+		middleware = append(middleware, newFailover(baseURL, *cfg.Failover).middleware)
+	}
+	if cfg.Logger != nil {
+		middleware = append(middleware, logging.Middleware(cfg.Logger, cfg.LogOptions))
+	}
+
+	c := resty.New().
+		SetTransport(config.Chain(transport, middleware...)).
+		SetBaseURL(baseURL).
+		SetTimeout(cfg.Timeout)
+
+	c = setCircuitBreaker(c, breaker)
+	c = setRateLimiter(c, cfg.RateLimit).
+		OnBeforeRequest(authenticate(auth, cfg.Metrics)).
 		SetError(&models.SPVError{}).
 		OnAfterResponse(func(_ *resty.Client, r *resty.Response) error {
 			if r.IsSuccess() {
 				return nil
 			}
 
-			if spvError, ok := r.Error().(*models.SPVError); ok && len(spvError.Code) > 0 {
-				return spvError
-			}
-
-			return fmt.Errorf("%w: %s", goclienterr.ErrUnrecognizedAPIResponse, r.Body())
+			return newAPIError(r)
 		})
+
+	if cfg.Metrics != nil {
+		c.AddRetryHook(observeRetry(c, cfg.Metrics))
+	}
+	if cfg.Logger != nil {
+		c.SetLogger(logging.RestyLogger(cfg.Logger))
+	}
+
+	return setRetryPolicy(c, cfg.Retry), nil
+}
+
+func newAPIError(r *resty.Response) *goclienterr.APIError {
+	apiErr := &goclienterr.APIError{
+		StatusCode: r.StatusCode(),
+		Method:     r.Request.Method,
+		URL:        r.Request.URL,
+		RequestID:  r.Header().Get(goclienterr.RequestIDHeader),
+		Body:       r.Body(),
+	}
+
+	if spvError, ok := r.Error().(*models.SPVError); ok && len(spvError.Code) > 0 {
+		apiErr.Code = spvError.Code
+		apiErr.Message = spvError.Message
+		apiErr.Err = spvError
+		return apiErr
+	}
+
+	apiErr.Err = fmt.Errorf("%w: %s", goclienterr.ErrUnrecognizedAPIResponse, r.Body())
+	return apiErr
 }
@@ -1,9 +1,12 @@
 package restyutil_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
 	"github.com/bitcoin-sv/spv-wallet/models"
@@ -63,14 +66,58 @@ func TestNewHTTPClient_OnAfterResponse(t *testing.T) {
 	}
 }
 
+func TestNewHTTPClient_APIError(t *testing.T) {
+	tests := map[string]struct {
+		statusCode   int
+		responseBody interface{}
+		expectedCode string
+		expectedErr  error
+	}{
+		"SPV Wallet error response": {
+			statusCode:   http.StatusUnauthorized,
+			responseBody: testutils.NewUnauthorizedAccessSPVError(),
+			expectedCode: testutils.NewUnauthorizedAccessSPVError().Code,
+			expectedErr:  testutils.NewUnauthorizedAccessSPVError(),
+		},
+		"Unrecognized error response": {
+			statusCode:   http.StatusBadGateway,
+			responseBody: "bad gateway",
+			expectedErr:  goclienterr.ErrUnrecognizedAPIResponse,
+		},
+	}
+
+	client := setupMockHTTPClient(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			responder := httpmock.NewJsonResponderOrPanic(tc.statusCode, tc.responseBody).HeaderSet(http.Header{goclienterr.RequestIDHeader: {"req-1"}})
+			httpmock.RegisterResponder(http.MethodDelete, "http://mock-api/test", responder)
+
+			// when:
+			_, err := client.R().Delete("/test")
+
+			// then:
+			var apiErr *goclienterr.APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.statusCode, apiErr.StatusCode)
+			require.Equal(t, http.MethodDelete, apiErr.Method)
+			require.Equal(t, tc.expectedCode, apiErr.Code)
+			require.Equal(t, "req-1", apiErr.RequestID)
+			require.NotEmpty(t, apiErr.Body)
+		})
+	}
+}
+
 // setupMockHTTPClient initializes an HTTP client with a mock configuration and authenticator
 func setupMockHTTPClient(t *testing.T) *resty.Client {
 	cfg := config.Config{
 		Addr:      "http://mock-api",
-		Timeout:   5,
+		Timeout:   5 * time.Second,
 		Transport: httpmock.DefaultTransport,
 	}
-	client := restyutil.NewHTTPClient(cfg, &mockAuthenticator{})
+	client, err := restyutil.NewHTTPClient(cfg, &mockAuthenticator{})
+	require.NoError(t, err)
 	httpmock.ActivateNonDefault(client.GetClient())
 	t.Cleanup(httpmock.DeactivateAndReset)
 	return client
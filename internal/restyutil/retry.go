@@ -0,0 +1,92 @@
+package restyutil
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/go-resty/resty/v2"
+)
+
+type retryPolicy struct {
+	config.RetryPolicy
+}
+
+func setRetryPolicy(c *resty.Client, p *config.RetryPolicy) *resty.Client {
+	if p == nil || p.MaxAttempts <= 1 {
+		return c
+	}
+
+	policy := retryPolicy{RetryPolicy: *p}
+	return c.
+		SetRetryCount(p.MaxAttempts - 1).
+		SetRetryWaitTime(0).
+		SetRetryMaxWaitTime(p.MaxBackoff).
+		SetRetryAfter(policy.waitTime).
+		AddRetryCondition(policy.shouldRetry)
+}
+
+func (p retryPolicy) shouldRetry(r *resty.Response, err error) bool {
+	if r == nil || r.Request == nil {
+		return false // the request was never sent, e.g. authentication failed
+	}
+
+	if !p.RetryNonIdempotent && !isIdempotent(r.Request.Method) {
+		return false
+	}
+
+	if r.RawResponse == nil {
+		// transport level failure, e.g. connection reset, unless the circuit breaker failed the attempt fast
+		return err != nil && !errors.Is(err, goclienterr.ErrCircuitOpen)
+	}
+
+	return slices.Contains(p.RetryableStatusCodes, r.StatusCode())
+}
+
+func (p retryPolicy) waitTime(_ *resty.Client, r *resty.Response) (time.Duration, error) {
+	if p.HonorRetryAfter {
+		if d, ok := retryAfter(r.Header()); ok {
+			return d, nil
+		}
+	}
+
+	attempt := max(r.Request.Attempt, 1)
+	backoff := p.BaseBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	jitter := time.Duration(p.Jitter * rand.Float64() * float64(backoff))
+	return backoff - jitter, nil
+}
+
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package restyutil_test
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_RetryPolicy(t *testing.T) {
+	serviceUnavailable := httpmock.NewJsonResponderOrPanic(http.StatusServiceUnavailable, testutils.NewInternalServerSPVError())
+	ok := httpmock.NewStringResponder(http.StatusOK, "{}")
+	connectionReset := httpmock.NewErrorResponder(syscall.ECONNRESET)
+	badRequest := testutils.NewBadRequestSPVErrorResponder()
+
+	tests := map[string]struct {
+		method           string
+		policy           func(p *config.RetryPolicy)
+		responders       []httpmock.Responder
+		expectedAttempts int
+		expectErr        bool
+	}{
+		"GET retried until success": {
+			method:           http.MethodGet,
+			responders:       []httpmock.Responder{serviceUnavailable, serviceUnavailable, ok},
+			expectedAttempts: 3,
+		},
+		"GET retried after connection reset": {
+			method:           http.MethodGet,
+			responders:       []httpmock.Responder{connectionReset, ok},
+			expectedAttempts: 2,
+		},
+		"GET stops after max attempts": {
+			method:           http.MethodGet,
+			responders:       []httpmock.Responder{serviceUnavailable, serviceUnavailable, serviceUnavailable, ok},
+			expectedAttempts: 3,
+			expectErr:        true,
+		},
+		"GET not retried on non-retryable status code": {
+			method:           http.MethodGet,
+			responders:       []httpmock.Responder{badRequest, ok},
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+		"DELETE retried as idempotent request": {
+			method:           http.MethodDelete,
+			responders:       []httpmock.Responder{serviceUnavailable, ok},
+			expectedAttempts: 2,
+		},
+		"POST not retried by default": {
+			method:           http.MethodPost,
+			responders:       []httpmock.Responder{serviceUnavailable, ok},
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+		"POST retried when non-idempotent retries enabled": {
+			method:           http.MethodPost,
+			policy:           func(p *config.RetryPolicy) { p.RetryNonIdempotent = true },
+			responders:       []httpmock.Responder{connectionReset, serviceUnavailable, ok},
+			expectedAttempts: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			policy := givenRetryPolicy()
+			if tc.policy != nil {
+				tc.policy(&policy)
+			}
+
+			client, transport := givenHTTPClientWithRetryPolicy(t, policy)
+			var nonces []string
+			attempt := 0
+			transport.RegisterResponder(tc.method, testutils.FullAPIURL(t, "/test"), func(r *http.Request) (*http.Response, error) {
+				nonces = append(nonces, r.Header.Get(models.AuthHeaderNonce))
+				responder := tc.responders[attempt]
+				attempt++
+				return responder(r)
+			})
+
+			// when:
+			_, err := client.R().SetBody(map[string]string{"key": "value"}).Execute(tc.method, "/test")
+
+			// then:
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedAttempts, attempt)
+			requireDistinctNonces(t, nonces)
+		})
+	}
+}
+
+func TestNewHTTPClient_RetryPolicyHonorsRetryAfter(t *testing.T) {
+	// given:
+	policy := givenRetryPolicy()
+	policy.BaseBackoff = 5 * time.Second
+	policy.MaxBackoff = 5 * time.Second
+
+	client, transport := givenHTTPClientWithRetryPolicy(t, policy)
+	tooManyRequests := httpmock.NewStringResponse(http.StatusTooManyRequests, "") //nolint: bodyclose
+	tooManyRequests.Header.Set("Retry-After", "0")
+	transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.ResponderFromMultipleResponses([]*http.Response{
+		tooManyRequests,
+		httpmock.NewStringResponse(http.StatusOK, "{}"), //nolint: bodyclose
+	}))
+
+	// when:
+	start := time.Now()
+	_, err := client.R().Get("/test")
+
+	// then:
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestNewHTTPClient_RetryPolicyReturnsLastError(t *testing.T) {
+	// given:
+	client, transport := givenHTTPClientWithRetryPolicy(t, givenRetryPolicy())
+	transport.RegisterResponder(http.MethodGet, testutils.FullAPIURL(t, "/test"), httpmock.NewErrorResponder(syscall.ECONNRESET))
+
+	// when:
+	_, err := client.R().Get("/test")
+
+	// then:
+	require.True(t, errors.Is(err, syscall.ECONNRESET))
+}
+
+func givenRetryPolicy() config.RetryPolicy {
+	policy := config.DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func givenHTTPClientWithRetryPolicy(t *testing.T, policy config.RetryPolicy) (*resty.Client, *httpmock.MockTransport) {
+	t.Helper()
+	authenticator, err := auth.NewXprivAuthenticator(testutils.UserXPriv)
+	require.NoError(t, err)
+
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:      testutils.TestAPIAddr,
+		Timeout:   5 * time.Second,
+		Transport: transport,
+		Retry:     &policy,
+	}
+
+	client, err := restyutil.NewHTTPClient(cfg, authenticator)
+	require.NoError(t, err)
+
+	return client, transport
+}
+
+func requireDistinctNonces(t *testing.T, nonces []string) {
+	t.Helper()
+	seen := make(map[string]bool, len(nonces))
+	for _, n := range nonces {
+		require.NotEmpty(t, n)
+		require.False(t, seen[n], "auth nonce reused between attempts")
+		seen[n] = true
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"encoding/hex"
 	"net/url"
 	"path"
+	"path/filepath"
 	"testing"
 	"time"
 
 	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
 	spvwallet "github.com/bitcoin-sv/spv-wallet-go-client"
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
 	"github.com/jarcoal/httpmock"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const TestAPIAddr = "http://localhost:3003"
@@ -45,6 +48,93 @@ func GivenSPVUserAPI(t *testing.T) (*spvwallet.UserAPI, *httpmock.MockTransport)
 	return spv, transport
 }
 
+func GivenSPVUserAPIWithTracerProvider(t *testing.T, tp trace.TracerProvider) (*spvwallet.UserAPI, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:           TestAPIAddr,
+		Timeout:        5 * time.Second,
+		Transport:      transport,
+		TracerProvider: tp,
+	}
+
+	spv, err := spvwallet.NewUserAPIWithXPriv(cfg, UserXPriv)
+	if err != nil {
+		t.Fatalf("test helper - spv wallet client with tracer provider: %s", err)
+	}
+
+	return spv, transport
+}
+
+func GivenSPVUserAPIWithMetrics(t *testing.T, metrics config.Metrics) (*spvwallet.UserAPI, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:      TestAPIAddr,
+		Timeout:   5 * time.Second,
+		Transport: transport,
+		Metrics:   metrics,
+	}
+
+	spv, err := spvwallet.NewUserAPIWithXPriv(cfg, UserXPriv)
+	if err != nil {
+		t.Fatalf("test helper - spv wallet client with metrics: %s", err)
+	}
+
+	return spv, transport
+}
+
+func GivenSPVUserAPIWithSigner(t *testing.T, signer spvwallet.TransactionSigner) (*spvwallet.UserAPI, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:      TestAPIAddr,
+		Timeout:   5 * time.Second,
+		Transport: transport,
+	}
+
+	spv, err := spvwallet.NewUserAPIWithSigner(cfg, UserPrivAccessKey, UserXPub, signer)
+	if err != nil {
+		t.Fatalf("test helper - spv wallet client with signer: %s", err)
+	}
+
+	return spv, transport
+}
+
+func GivenSPVUserAPIWithXPub(t *testing.T) (*spvwallet.UserAPI, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:      TestAPIAddr,
+		Timeout:   5 * time.Second,
+		Transport: transport,
+	}
+
+	spv, err := spvwallet.NewUserAPIWithXPub(cfg, UserXPub)
+	if err != nil {
+		t.Fatalf("test helper - spv wallet client with xpub: %s", err)
+	}
+
+	return spv, transport
+}
+
+func GivenSPVUserAPIWithAccessKey(t *testing.T) (*spvwallet.UserAPI, *httpmock.MockTransport) {
+	t.Helper()
+	transport := httpmock.NewMockTransport()
+	cfg := config.Config{
+		Addr:      TestAPIAddr,
+		Timeout:   5 * time.Second,
+		Transport: transport,
+	}
+
+	spv, err := spvwallet.NewUserAPIWithAccessKey(cfg, UserPrivAccessKey)
+	if err != nil {
+		t.Fatalf("test helper - spv wallet client with access key: %s", err)
+	}
+
+	return spv, transport
+}
+
 func GivenSPVAdminAPI(t *testing.T) (*spvwallet.AdminAPI, *httpmock.MockTransport) {
 	t.Helper()
 	transport := httpmock.NewMockTransport()
@@ -62,6 +152,28 @@ func GivenSPVAdminAPI(t *testing.T) (*spvwallet.AdminAPI, *httpmock.MockTranspor
 	return api, transport
 }
 
+// KeystorePassword is the password of the keystores created with GivenKeystore.
+const KeystorePassword = "correct horse battery staple"
+
+// GivenKeystore creates a keystore in a temporary directory with the entries, encrypted with KeystorePassword,
+// and returns the path of the keystore file.
+func GivenKeystore(t *testing.T, entries map[string]walletkeys.KeystoreEntry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := walletkeys.CreateKeystore(path, KeystorePassword)
+	if err != nil {
+		t.Fatalf("test helper - create keystore: %s", err)
+	}
+
+	for name, entry := range entries {
+		if err := ks.Put(name, entry); err != nil {
+			t.Fatalf("test helper - put keystore entry: %s", err)
+		}
+	}
+
+	return path
+}
+
 func MockPKI(t *testing.T, xpub string) string {
 	t.Helper()
 	xPub, _ := bip32.NewKeyFromString(xpub)
@@ -0,0 +1,107 @@
+// Package tracing instruments the UserAPI and AdminAPI calls with OpenTelemetry spans.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName is the name of the tracer creating the spans.
+const InstrumentationName = "github.com/bitcoin-sv/spv-wallet-go-client"
+
+// Span attribute keys.
+const (
+	// EndpointKey holds the API the call is sent through, e.g. "user/transactions".
+	EndpointKey = attribute.Key("spvwallet.endpoint")
+	// MethodKey holds the HTTP method of the request.
+	MethodKey = attribute.Key("http.request.method")
+	// URLKey holds the URL of the request.
+	URLKey = attribute.Key("url.full")
+	// StatusCodeKey holds the HTTP status code of the response.
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+type endpointKey struct{}
+
+// propagator injects the W3C trace context and baggage headers into the requests.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Tracer starts the spans of the high level API methods.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a Tracer creating spans with the provider, or a no-op Tracer if the provider is nil.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+// Start starts the span of an API method, e.g. "UserAPI.XPub", sending requests through the endpoint.
+// The endpoint is recorded on the HTTP request spans started within the returned context too.
+func (t *Tracer) Start(ctx context.Context, name, endpoint string) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, endpointKey{}, endpoint)
+	return t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(EndpointKey.String(endpoint)))
+}
+
+// Endpoint returns the endpoint of the API method traced in the context, if any.
+func Endpoint(ctx context.Context) (string, bool) {
+	endpoint, ok := ctx.Value(endpointKey{}).(string)
+	return endpoint, ok
+}
+
+// StartChild starts a span for a step of the API method traced in the context,
+// using the tracer provider of its span.
+func StartChild(ctx context.Context, name string) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(InstrumentationName).Start(ctx, name)
+}
+
+// Error records the error on the span, marks the span as failed and returns the error.
+func Error(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Middleware starts a client span for every HTTP request attempt and propagates
+// the trace context to the SPV Wallet in the W3C Trace Context headers.
+func Middleware(tp trace.TracerProvider) config.Middleware {
+	tracer := tp.Tracer(InstrumentationName)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return config.RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attrs := []attribute.KeyValue{MethodKey.String(req.Method), URLKey.String(req.URL.String())}
+			if endpoint, ok := Endpoint(req.Context()); ok {
+				attrs = append(attrs, EndpointKey.String(endpoint))
+			}
+
+			ctx, span := tracer.Start(req.Context(), "HTTP "+req.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			req = req.Clone(ctx)
+			propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			res, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, Error(span, err)
+			}
+
+			span.SetAttributes(StatusCodeKey.Int(res.StatusCode))
+			if res.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, strconv.Itoa(res.StatusCode))
+			}
+			return res, nil
+		})
+	}
+}
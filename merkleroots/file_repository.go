@@ -0,0 +1,262 @@
+package merkleroots
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet/models"
+)
+
+// FileRepository is an append-only, file-backed ReorgRepository.
+//
+// Every SaveMerkleRoots call appends a single line holding the whole batch as JSON and syncs the file,
+// so a batch is either stored entirely or not at all. DeleteMerkleRootsFrom appends a line marking
+// the removed heights instead of rewriting the file. An incomplete trailing line left by a crash
+// is discarded when the file is opened. All stored roots are indexed in memory.
+type FileRepository struct {
+	mu         sync.RWMutex
+	file       *os.File
+	byHeight   map[int]string
+	byRoot     map[string]int
+	lastHeight int
+}
+
+// NewFileRepository opens the Merkle roots file at the given path, creating it if it doesn't exist,
+// and loads the stored roots. Returns errors.ErrMerkleRootsStoreCorrupted if a record other than
+// the last one cannot be read.
+func NewFileRepository(path string) (*FileRepository, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open merkle roots file: %w", err)
+	}
+
+	repo := &FileRepository{
+		file:       f,
+		byHeight:   make(map[int]string),
+		byRoot:     make(map[string]int),
+		lastHeight: -1,
+	}
+	if err := repo.load(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// load reads all batches from the file and truncates an incomplete trailing batch.
+func (r *FileRepository) load() error {
+	var offset int64
+	reader := bufio.NewReader(r.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return r.truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read merkle roots file: %w", err)
+		}
+
+		if err := r.apply(bytes.TrimSpace(line)); err != nil {
+			if _, peekErr := reader.Peek(1); errors.Is(peekErr, io.EOF) {
+				return r.truncate(offset)
+			}
+			return fmt.Errorf("%w: invalid record at offset %d: %w", goclienterr.ErrMerkleRootsStoreCorrupted, offset, err)
+		}
+		offset += int64(len(line))
+	}
+
+	if _, err := r.file.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to seek merkle roots file: %w", err)
+	}
+
+	return nil
+}
+
+// deleteRecord is the record appended by DeleteMerkleRootsFrom.
+type deleteRecord struct {
+	DeleteFrom int `json:"deleteFrom"`
+}
+
+// apply indexes a batch record or applies a delete record read from the file.
+func (r *FileRepository) apply(record []byte) error {
+	if bytes.HasPrefix(record, []byte("{")) {
+		var del deleteRecord
+		if err := json.Unmarshal(record, &del); err != nil {
+			return fmt.Errorf("failed to decode delete record: %w", err)
+		}
+		r.deleteFrom(del.DeleteFrom)
+		return nil
+	}
+
+	var batch []models.MerkleRoot
+	if err := json.Unmarshal(record, &batch); err != nil {
+		return fmt.Errorf("failed to decode merkle roots batch: %w", err)
+	}
+	r.index(batch)
+	return nil
+}
+
+func (r *FileRepository) truncate(size int64) error {
+	if err := r.file.Truncate(size); err != nil {
+		return fmt.Errorf("failed to discard incomplete merkle roots batch: %w", err)
+	}
+	if _, err := r.file.Seek(size, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek merkle roots file: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FileRepository) index(batch []models.MerkleRoot) {
+	for _, root := range batch {
+		r.byHeight[root.BlockHeight] = root.MerkleRoot
+		r.byRoot[root.MerkleRoot] = root.BlockHeight
+		if root.BlockHeight > r.lastHeight {
+			r.lastHeight = root.BlockHeight
+		}
+	}
+}
+
+// GetLastMerkleRoot returns the Merkle root with the highest block height, or an empty string if none is stored.
+func (r *FileRepository) GetLastMerkleRoot() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.byHeight[r.lastHeight]
+}
+
+// SaveMerkleRoots appends the batch to the file as a single record and syncs it to disk.
+// Roots already stored with the same value are skipped. Returns errors.ErrMerkleRootsOutOfOrder
+// for an unsorted batch and errors.ErrMerkleRootConflict if a different root is stored for one of the heights.
+func (r *FileRepository) SaveMerkleRoots(syncedMerkleRoots []models.MerkleRoot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	batch, err := unsavedRoots(syncedMerkleRoots, r.storedRoot)
+	if err != nil {
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	record, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode merkle roots batch: %w", err)
+	}
+	if err := r.append(record); err != nil {
+		return err
+	}
+
+	r.index(batch)
+	return nil
+}
+
+// LastMerkleRootHeight returns the highest stored block height, or -1 if no Merkle root is stored.
+func (r *FileRepository) LastMerkleRootHeight() (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastHeight, nil
+}
+
+// DeleteMerkleRootsFrom removes the Merkle roots stored for the block height and all higher ones
+// by appending a delete record to the file.
+func (r *FileRepository) DeleteMerkleRootsFrom(height int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if height > r.lastHeight {
+		return nil
+	}
+
+	record, err := json.Marshal(deleteRecord{DeleteFrom: height})
+	if err != nil {
+		return fmt.Errorf("failed to encode merkle roots delete record: %w", err)
+	}
+	if err := r.append(record); err != nil {
+		return err
+	}
+
+	r.deleteFrom(height)
+	return nil
+}
+
+// append writes the record as a single line and syncs the file, discarding partially written data on failure.
+func (r *FileRepository) append(record []byte) error {
+	offset, err := r.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to seek merkle roots file: %w", err)
+	}
+	if _, err := r.file.Write(append(record, '\n')); err != nil {
+		return errors.Join(fmt.Errorf("failed to write merkle roots record: %w", err), r.truncate(offset))
+	}
+	if err := r.file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync merkle roots file: %w", err), r.truncate(offset))
+	}
+
+	return nil
+}
+
+func (r *FileRepository) deleteFrom(height int) {
+	r.lastHeight = -1
+	for h, root := range r.byHeight {
+		if h >= height {
+			delete(r.byHeight, h)
+			delete(r.byRoot, root)
+		} else if h > r.lastHeight {
+			r.lastHeight = h
+		}
+	}
+}
+
+// HasMerkleRoot reports whether the Merkle root is stored at any block height.
+func (r *FileRepository) HasMerkleRoot(root string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.byRoot[root]
+	return ok, nil
+}
+
+// MerkleRootAtHeight returns the Merkle root stored for the block height
+// or errors.ErrMerkleRootNotFound if there is none.
+func (r *FileRepository) MerkleRootAtHeight(height int) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	root, ok := r.byHeight[height]
+	if !ok {
+		return "", fmt.Errorf("%w: height %d", goclienterr.ErrMerkleRootNotFound, height)
+	}
+
+	return root, nil
+}
+
+// Close closes the underlying file.
+func (r *FileRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close merkle roots file: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FileRepository) storedRoot(height int) (string, bool, error) {
+	root, ok := r.byHeight[height]
+	return root, ok, nil
+}
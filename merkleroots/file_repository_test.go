@@ -0,0 +1,234 @@
+package merkleroots_test
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	apimerkleroots "github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/merkleroots/merklerootstest"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var _ merkleroots.ReorgRepository = (*merkleroots.FileRepository)(nil)
+
+func TestFileRepository_SaveMerkleRoots(t *testing.T) {
+	roots := merklerootstest.MockedSPVWalletData[:3]
+
+	tests := map[string]struct {
+		batch       []models.MerkleRoot
+		expectedErr error
+	}{
+		"Save next batch": {
+			batch: merklerootstest.MockedSPVWalletData[3:5],
+		},
+		"Save already stored batch": {
+			batch: roots,
+		},
+		"Save batch overlapping stored roots": {
+			batch: merklerootstest.MockedSPVWalletData[2:5],
+		},
+		"Save batch out of order": {
+			batch:       []models.MerkleRoot{merklerootstest.MockedSPVWalletData[4], merklerootstest.MockedSPVWalletData[3]},
+			expectedErr: goclienterr.ErrMerkleRootsOutOfOrder,
+		},
+		"Save batch with conflicting root": {
+			batch:       []models.MerkleRoot{{BlockHeight: 1, MerkleRoot: merklerootstest.MockedSPVWalletData[4].MerkleRoot}},
+			expectedErr: goclienterr.ErrMerkleRootConflict,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+			repo := givenFileRepository(t, path)
+			require.NoError(t, repo.SaveMerkleRoots(roots))
+			sizeBefore := fileSize(t, path)
+
+			// when:
+			err := repo.SaveMerkleRoots(tc.batch)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.Equal(t, sizeBefore, fileSize(t, path))
+				require.Equal(t, roots[len(roots)-1].MerkleRoot, repo.GetLastMerkleRoot())
+				return
+			}
+			require.Equal(t, tc.batch[len(tc.batch)-1].MerkleRoot, repo.GetLastMerkleRoot())
+		})
+	}
+}
+
+func TestFileRepository_Reopen(t *testing.T) {
+	t.Run("Reopened repository resumes from stored roots", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+		repo := givenFileRepository(t, path)
+		require.NoError(t, repo.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[:2]))
+		require.NoError(t, repo.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[2:4]))
+		require.NoError(t, repo.Close())
+
+		// when:
+		reopened := givenFileRepository(t, path)
+
+		// then:
+		require.Equal(t, merklerootstest.MockedSPVWalletData[3].MerkleRoot, reopened.GetLastMerkleRoot())
+		root, err := reopened.MerkleRootAtHeight(merklerootstest.MockedSPVWalletData[1].BlockHeight)
+		require.NoError(t, err)
+		require.Equal(t, merklerootstest.MockedSPVWalletData[1].MerkleRoot, root)
+	})
+
+	t.Run("Incomplete trailing batch is discarded", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+		repo := givenFileRepository(t, path)
+		require.NoError(t, repo.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[:2]))
+		require.NoError(t, repo.Close())
+		sizeBefore := fileSize(t, path)
+		appendToFile(t, path, `[{"merkleRoot":"0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098","blockH`)
+
+		// when:
+		reopened := givenFileRepository(t, path)
+
+		// then:
+		require.Equal(t, sizeBefore, fileSize(t, path))
+		require.Equal(t, merklerootstest.MockedSPVWalletData[1].MerkleRoot, reopened.GetLastMerkleRoot())
+		require.NoError(t, reopened.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[2:3]))
+		require.Equal(t, merklerootstest.MockedSPVWalletData[2].MerkleRoot, reopened.GetLastMerkleRoot())
+	})
+
+	t.Run("Corrupted record in the middle of the file", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+		appendToFile(t, path, "garbage\n[]\n")
+
+		// when:
+		repo, err := merkleroots.NewFileRepository(path)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrMerkleRootsStoreCorrupted)
+		require.Nil(t, repo)
+	})
+}
+
+func TestFileRepository_DeleteMerkleRootsFrom(t *testing.T) {
+	// given:
+	path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+	repo := givenFileRepository(t, path)
+	require.NoError(t, repo.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[:5]))
+
+	// when:
+	err := repo.DeleteMerkleRootsFrom(3)
+
+	// then:
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+	reopened := givenFileRepository(t, path)
+	height, err := reopened.LastMerkleRootHeight()
+	require.NoError(t, err)
+	require.Equal(t, 2, height)
+	require.Equal(t, merklerootstest.MockedSPVWalletData[2].MerkleRoot, reopened.GetLastMerkleRoot())
+	ok, err := reopened.HasMerkleRoot(merklerootstest.MockedSPVWalletData[3].MerkleRoot)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, reopened.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[3:4]))
+}
+
+func TestFileRepository_Lookups(t *testing.T) {
+	// given:
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	stored := merklerootstest.MockedSPVWalletData[:3]
+	require.NoError(t, repo.SaveMerkleRoots(stored))
+
+	t.Run("HasMerkleRoot for stored root", func(t *testing.T) {
+		// when:
+		ok, err := repo.HasMerkleRoot(stored[1].MerkleRoot)
+
+		// then:
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("HasMerkleRoot for unknown root", func(t *testing.T) {
+		// when:
+		ok, err := repo.HasMerkleRoot(merklerootstest.LastMockedMerkleRoot().MerkleRoot)
+
+		// then:
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("MerkleRootAtHeight for unknown height", func(t *testing.T) {
+		// when:
+		root, err := repo.MerkleRootAtHeight(merklerootstest.LastMockedMerkleRoot().BlockHeight)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrMerkleRootNotFound)
+		require.Empty(t, root)
+	})
+}
+
+func TestFileRepository_SyncMerkleRoots(t *testing.T) {
+	// given:
+	server := merklerootstest.MockMerkleRootsAPIResponseNormal()
+	defer server.Close()
+	apiURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "merkleroots.jsonl")
+	repo := givenFileRepository(t, path)
+	require.NoError(t, repo.SaveMerkleRoots(merklerootstest.MockedSPVWalletData[:4]))
+	require.NoError(t, repo.Close())
+	client := apimerkleroots.NewAPI(apiURL, resty.New())
+
+	// when:
+	repo = givenFileRepository(t, path)
+	err = client.SyncMerkleRoots(context.Background(), repo)
+
+	// then:
+	require.NoError(t, err)
+	require.Equal(t, merklerootstest.LastMockedMerkleRoot().MerkleRoot, repo.GetLastMerkleRoot())
+	for _, expected := range merklerootstest.MockedSPVWalletData {
+		root, err := repo.MerkleRootAtHeight(expected.BlockHeight)
+		require.NoError(t, err)
+		require.Equal(t, expected.MerkleRoot, root)
+	}
+}
+
+func givenFileRepository(t *testing.T, path string) *merkleroots.FileRepository {
+	t.Helper()
+
+	repo, err := merkleroots.NewFileRepository(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+
+	return repo
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	return info.Size()
+}
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+}
@@ -0,0 +1,67 @@
+// Package merkleroots provides ready to use storages for Merkle roots synchronized with
+// UserAPI.SyncMerkleRoots, together with lookups needed to verify Merkle proofs locally.
+package merkleroots
+
+import (
+	"fmt"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet/models"
+)
+
+// Lookup looks up synchronized Merkle roots.
+type Lookup interface {
+	// HasMerkleRoot reports whether the Merkle root is stored at any block height.
+	HasMerkleRoot(root string) (bool, error)
+	// MerkleRootAtHeight returns the Merkle root stored for the block height
+	// or errors.ErrMerkleRootNotFound if there is none.
+	MerkleRootAtHeight(height int) (string, error)
+}
+
+// Repository stores synchronized Merkle roots and allows looking them up.
+// It satisfies the repository expected by UserAPI.SyncMerkleRoots.
+type Repository interface {
+	Lookup
+	// GetLastMerkleRoot returns the Merkle root with the highest block height, or an empty string if none is stored.
+	GetLastMerkleRoot() string
+	// SaveMerkleRoots atomically stores a batch of Merkle roots sorted in ascending order by block height.
+	SaveMerkleRoots(syncedMerkleRoots []models.MerkleRoot) error
+}
+
+// ReorgRepository is a Repository able to drop the Merkle roots orphaned by a chain reorganization.
+// It is required by the Syncer to keep the stored roots in line with the chain known to the SPV Wallet.
+type ReorgRepository interface {
+	Repository
+	// LastMerkleRootHeight returns the highest stored block height, or -1 if no Merkle root is stored.
+	LastMerkleRootHeight() (int, error)
+	// DeleteMerkleRootsFrom removes the Merkle roots stored for the block height and all higher ones.
+	DeleteMerkleRootsFrom(height int) error
+}
+
+// storedRootFunc returns the Merkle root stored for the block height and whether it exists.
+type storedRootFunc func(height int) (string, bool, error)
+
+// unsavedRoots validates the batch against the stored roots and returns the roots that still need to be saved.
+// Roots already stored with the same value are skipped, so a batch can be safely saved again after a crash.
+func unsavedRoots(batch []models.MerkleRoot, stored storedRootFunc) ([]models.MerkleRoot, error) {
+	unsaved := make([]models.MerkleRoot, 0, len(batch))
+	for i, root := range batch {
+		if i > 0 && root.BlockHeight <= batch[i-1].BlockHeight {
+			return nil, fmt.Errorf("%w: height %d follows height %d", goclienterr.ErrMerkleRootsOutOfOrder, root.BlockHeight, batch[i-1].BlockHeight)
+		}
+
+		current, ok, err := stored(root.BlockHeight)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			unsaved = append(unsaved, root)
+			continue
+		}
+		if current != root.MerkleRoot {
+			return nil, fmt.Errorf("%w: height %d has %s, got %s", goclienterr.ErrMerkleRootConflict, root.BlockHeight, current, root.MerkleRoot)
+		}
+	}
+
+	return unsaved, nil
+}
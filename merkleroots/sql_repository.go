@@ -0,0 +1,212 @@
+package merkleroots
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet/models"
+)
+
+// DefaultTableName is the name of the table used by SQLRepository unless WithTableName is given.
+const DefaultTableName = "merkle_roots"
+
+// Placeholder returns the bind parameter placeholder for the n-th (1-based) query argument.
+type Placeholder func(n int) string
+
+// QuestionPlaceholder produces "?" placeholders used by MySQL and SQLite drivers.
+func QuestionPlaceholder(int) string { return "?" }
+
+// DollarPlaceholder produces "$n" placeholders used by PostgreSQL drivers.
+func DollarPlaceholder(n int) string { return "$" + strconv.Itoa(n) }
+
+// SQLOption configures an SQLRepository.
+type SQLOption func(*SQLRepository)
+
+// WithTableName sets the name of the table storing the Merkle roots.
+func WithTableName(name string) SQLOption {
+	return func(r *SQLRepository) {
+		r.table = name
+	}
+}
+
+// WithPlaceholder sets the bind parameter style of the database driver.
+func WithPlaceholder(p Placeholder) SQLOption {
+	return func(r *SQLRepository) {
+		r.placeholder = p
+	}
+}
+
+// SQLRepository is a ReorgRepository backed by a database/sql database.
+// Each SaveMerkleRoots call is executed in a single transaction, so a batch is either stored entirely or not at all.
+// The table can be created with CreateTable; its schema is:
+//
+//	block_height BIGINT PRIMARY KEY, merkle_root VARCHAR(64) NOT NULL
+type SQLRepository struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+}
+
+// NewSQLRepository returns an SQLRepository using the given database handle.
+// By default the "merkle_roots" table and "?" placeholders are used.
+func NewSQLRepository(db *sql.DB, opts ...SQLOption) *SQLRepository {
+	repo := &SQLRepository{
+		db:          db,
+		table:       DefaultTableName,
+		placeholder: QuestionPlaceholder,
+	}
+	for _, o := range opts {
+		o(repo)
+	}
+
+	return repo
+}
+
+// CreateTable creates the Merkle roots table and its merkle_root index if they don't exist.
+func (r *SQLRepository) CreateTable(ctx context.Context) error {
+	stmts := []string{
+		"CREATE TABLE IF NOT EXISTS " + r.table + " (block_height BIGINT PRIMARY KEY, merkle_root VARCHAR(64) NOT NULL)",
+		"CREATE INDEX IF NOT EXISTS " + r.table + "_merkle_root_idx ON " + r.table + " (merkle_root)",
+	}
+	for _, stmt := range stmts {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create merkle roots table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetLastMerkleRoot returns the Merkle root with the highest block height, or an empty string
+// if none is stored or the query fails. In the latter case the next sync starts from the beginning,
+// which is safe as already stored roots are skipped by SaveMerkleRoots.
+func (r *SQLRepository) GetLastMerkleRoot() string {
+	var root string
+	query := "SELECT merkle_root FROM " + r.table + " ORDER BY block_height DESC LIMIT 1"
+	if err := r.db.QueryRowContext(context.Background(), query).Scan(&root); err != nil {
+		return ""
+	}
+
+	return root
+}
+
+// SaveMerkleRoots stores the batch in a single transaction.
+// Roots already stored with the same value are skipped. Returns errors.ErrMerkleRootsOutOfOrder
+// for an unsorted batch and errors.ErrMerkleRootConflict if a different root is stored for one of the heights.
+func (r *SQLRepository) SaveMerkleRoots(syncedMerkleRoots []models.MerkleRoot) (err error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin merkle roots transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	batch, err := unsavedRoots(syncedMerkleRoots, func(height int) (string, bool, error) {
+		return r.rootAtHeight(ctx, tx, height)
+	})
+	if err != nil {
+		return err
+	}
+
+	insert := r.query("INSERT INTO %s (block_height, merkle_root) VALUES (%s, %s)", 2)
+	for _, root := range batch {
+		if _, err = tx.ExecContext(ctx, insert, root.BlockHeight, root.MerkleRoot); err != nil {
+			return fmt.Errorf("failed to insert merkle root at height %d: %w", root.BlockHeight, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit merkle roots transaction: %w", err)
+	}
+
+	return nil
+}
+
+// LastMerkleRootHeight returns the highest stored block height, or -1 if no Merkle root is stored.
+func (r *SQLRepository) LastMerkleRootHeight() (int, error) {
+	var height sql.NullInt64
+	query := "SELECT MAX(block_height) FROM " + r.table
+	if err := r.db.QueryRowContext(context.Background(), query).Scan(&height); err != nil {
+		return 0, fmt.Errorf("failed to query last merkle root height: %w", err)
+	}
+	if !height.Valid {
+		return -1, nil
+	}
+
+	return int(height.Int64), nil
+}
+
+// DeleteMerkleRootsFrom removes the Merkle roots stored for the block height and all higher ones.
+func (r *SQLRepository) DeleteMerkleRootsFrom(height int) error {
+	query := r.query("DELETE FROM %s WHERE block_height >= %s", 1)
+	if _, err := r.db.ExecContext(context.Background(), query, height); err != nil {
+		return fmt.Errorf("failed to delete merkle roots from height %d: %w", height, err)
+	}
+
+	return nil
+}
+
+// HasMerkleRoot reports whether the Merkle root is stored at any block height.
+func (r *SQLRepository) HasMerkleRoot(root string) (bool, error) {
+	var height int
+	query := r.query("SELECT block_height FROM %s WHERE merkle_root = %s LIMIT 1", 1)
+	err := r.db.QueryRowContext(context.Background(), query, root).Scan(&height)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query merkle root: %w", err)
+	}
+
+	return true, nil
+}
+
+// MerkleRootAtHeight returns the Merkle root stored for the block height
+// or errors.ErrMerkleRootNotFound if there is none.
+func (r *SQLRepository) MerkleRootAtHeight(height int) (string, error) {
+	root, ok, err := r.rootAtHeight(context.Background(), r.db, height)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: height %d", goclienterr.ErrMerkleRootNotFound, height)
+	}
+
+	return root, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *SQLRepository) rootAtHeight(ctx context.Context, q queryRower, height int) (string, bool, error) {
+	var root string
+	query := r.query("SELECT merkle_root FROM %s WHERE block_height = %s", 1)
+	err := q.QueryRowContext(ctx, query, height).Scan(&root)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query merkle root at height %d: %w", height, err)
+	}
+
+	return root, true, nil
+}
+
+// query formats the statement with the table name followed by args placeholders.
+func (r *SQLRepository) query(format string, args int) string {
+	values := []any{r.table}
+	for i := 1; i <= args; i++ {
+		values = append(values, r.placeholder(i))
+	}
+
+	return fmt.Sprintf(format, values...)
+}
@@ -0,0 +1,178 @@
+package merkleroots_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/merkleroots/merklerootstest"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/stretchr/testify/require"
+)
+
+var _ merkleroots.ReorgRepository = (*merkleroots.SQLRepository)(nil)
+
+const (
+	selectRootAtHeight = "SELECT merkle_root FROM merkle_roots WHERE block_height = ?"
+	insertRoot         = "INSERT INTO merkle_roots (block_height, merkle_root) VALUES (?, ?)"
+)
+
+func TestSQLRepository_SaveMerkleRoots(t *testing.T) {
+	batch := merklerootstest.MockedSPVWalletData[:2]
+
+	t.Run("Save batch in a single transaction", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(batch[0].BlockHeight).WillReturnRows(sqlmock.NewRows([]string{"merkle_root"}).AddRow(batch[0].MerkleRoot))
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(batch[1].BlockHeight).WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(insertRoot).WithArgs(batch[1].BlockHeight, batch[1].MerkleRoot).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		// when:
+		err := repo.SaveMerkleRoots(batch)
+
+		// then:
+		require.NoError(t, err)
+	})
+
+	t.Run("Failed insert rolls back the batch", func(t *testing.T) {
+		// given:
+		insertErr := errors.New("disk full")
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(batch[0].BlockHeight).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(batch[1].BlockHeight).WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(insertRoot).WithArgs(batch[0].BlockHeight, batch[0].MerkleRoot).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertRoot).WithArgs(batch[1].BlockHeight, batch[1].MerkleRoot).WillReturnError(insertErr)
+		mock.ExpectRollback()
+
+		// when:
+		err := repo.SaveMerkleRoots(batch)
+
+		// then:
+		require.ErrorIs(t, err, insertErr)
+	})
+
+	t.Run("Conflicting root rolls back the batch", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(batch[0].BlockHeight).WillReturnRows(sqlmock.NewRows([]string{"merkle_root"}).AddRow(batch[1].MerkleRoot))
+		mock.ExpectRollback()
+
+		// when:
+		err := repo.SaveMerkleRoots(batch)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrMerkleRootConflict)
+	})
+}
+
+func TestSQLRepository_Lookups(t *testing.T) {
+	stored := merklerootstest.LastMockedMerkleRoot()
+
+	t.Run("GetLastMerkleRoot", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectQuery("SELECT merkle_root FROM merkle_roots ORDER BY block_height DESC LIMIT 1").
+			WillReturnRows(sqlmock.NewRows([]string{"merkle_root"}).AddRow(stored.MerkleRoot))
+
+		// when:
+		root := repo.GetLastMerkleRoot()
+
+		// then:
+		require.Equal(t, stored.MerkleRoot, root)
+	})
+
+	t.Run("HasMerkleRoot for unknown root", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectQuery("SELECT block_height FROM merkle_roots WHERE merkle_root = ? LIMIT 1").
+			WithArgs(stored.MerkleRoot).
+			WillReturnError(sql.ErrNoRows)
+
+		// when:
+		ok, err := repo.HasMerkleRoot(stored.MerkleRoot)
+
+		// then:
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("MerkleRootAtHeight with custom table and placeholders", func(t *testing.T) {
+		// given:
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		repo := merkleroots.NewSQLRepository(db, merkleroots.WithTableName("roots"), merkleroots.WithPlaceholder(merkleroots.DollarPlaceholder))
+		mock.ExpectQuery("SELECT merkle_root FROM roots WHERE block_height = $1").
+			WithArgs(stored.BlockHeight).
+			WillReturnRows(sqlmock.NewRows([]string{"merkle_root"}).AddRow(stored.MerkleRoot))
+
+		// when:
+		root, err := repo.MerkleRootAtHeight(stored.BlockHeight)
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, stored.MerkleRoot, root)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("MerkleRootAtHeight for unknown height", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectQuery(selectRootAtHeight).WithArgs(stored.BlockHeight).WillReturnError(sql.ErrNoRows)
+
+		// when:
+		root, err := repo.MerkleRootAtHeight(stored.BlockHeight)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrMerkleRootNotFound)
+		require.Empty(t, root)
+	})
+}
+
+func TestSQLRepository_Reorg(t *testing.T) {
+	t.Run("LastMerkleRootHeight of empty table", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectQuery("SELECT MAX(block_height) FROM merkle_roots").
+			WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+		// when:
+		height, err := repo.LastMerkleRootHeight()
+
+		// then:
+		require.NoError(t, err)
+		require.Equal(t, -1, height)
+	})
+
+	t.Run("DeleteMerkleRootsFrom", func(t *testing.T) {
+		// given:
+		repo, mock := givenSQLRepository(t)
+		mock.ExpectExec("DELETE FROM merkle_roots WHERE block_height >= ?").
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		// when:
+		err := repo.DeleteMerkleRootsFrom(4)
+
+		// then:
+		require.NoError(t, err)
+	})
+}
+
+func givenSQLRepository(t *testing.T) (*merkleroots.SQLRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+
+	return merkleroots.NewSQLRepository(db), mock
+}
@@ -0,0 +1,352 @@
+package merkleroots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet/models"
+)
+
+const (
+	// DefaultReorgDepth is the number of most recent stored Merkle roots re-fetched
+	// and compared with the SPV Wallet on every sync to detect chain reorganizations.
+	DefaultReorgDepth = 6
+
+	// DefaultSyncInterval is the interval between syncs used when StartSyncer is given a non-positive interval.
+	DefaultSyncInterval = 10 * time.Minute
+
+	defaultBaseBackoff = time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+)
+
+// Fetcher fetches pages of Merkle roots from the SPV Wallet. It is satisfied by *spvwallet.UserAPI.
+type Fetcher interface {
+	MerkleRoots(ctx context.Context, opts ...queries.MerkleRootsQueryOption) (*queries.MerkleRootPage, error)
+}
+
+// Reorg describes a chain reorganization detected by the Syncer.
+type Reorg struct {
+	// Height is the first block height whose Merkle root changed.
+	Height int
+	// Orphaned holds the Merkle roots removed from the repository, sorted in ascending order by block height.
+	Orphaned []models.MerkleRoot
+}
+
+// SyncMetrics is a snapshot of the Syncer activity.
+type SyncMetrics struct {
+	// Syncs is the number of completed sync passes, successful or not.
+	Syncs uint64
+	// Failures is the number of failed sync passes.
+	Failures uint64
+	// ConsecutiveFailures is the number of failed sync passes since the last successful one.
+	ConsecutiveFailures int
+	// SyncedRoots is the number of Merkle roots saved by the Syncer.
+	SyncedRoots uint64
+	// Reorgs is the number of detected chain reorganizations.
+	Reorgs uint64
+	// LastHeight is the highest stored block height after the last sync pass, -1 if none is stored.
+	LastHeight int
+	// LastSyncAt is the time the last sync pass finished.
+	LastSyncAt time.Time
+	// LastSuccessAt is the time the last successful sync pass finished.
+	LastSuccessAt time.Time
+	// LastError is the error of the last sync pass, nil if it succeeded.
+	LastError error
+}
+
+// SyncerOption configures a Syncer.
+type SyncerOption func(*Syncer)
+
+// WithReorgDepth sets how many of the most recent stored Merkle roots are compared with the SPV Wallet
+// on every sync. Reorganizations deeper than that are not detected.
+func WithReorgDepth(depth int) SyncerOption {
+	return func(s *Syncer) {
+		s.reorgDepth = depth
+	}
+}
+
+// WithSyncBackoff sets the delay before retrying a failed sync. The delay doubles with every
+// consecutive failure, starting at base and capped at max. A non-positive base is replaced with
+// the default of one second, and a max lower than base with base.
+func WithSyncBackoff(base, max time.Duration) SyncerOption {
+	return func(s *Syncer) {
+		if base <= 0 {
+			base = defaultBaseBackoff
+		}
+		if max < base {
+			max = base
+		}
+		s.baseBackoff = base
+		s.maxBackoff = max
+	}
+}
+
+// WithOnNewRoots registers a callback invoked with every batch of newly saved Merkle roots.
+func WithOnNewRoots(fn func(roots []models.MerkleRoot)) SyncerOption {
+	return func(s *Syncer) {
+		s.onNewRoots = fn
+	}
+}
+
+// WithOnReorg registers a callback invoked when a chain reorganization is detected,
+// after the orphaned Merkle roots have been removed from the repository.
+func WithOnReorg(fn func(reorg Reorg)) SyncerOption {
+	return func(s *Syncer) {
+		s.onReorg = fn
+	}
+}
+
+// WithOnSyncError registers a callback invoked when a sync pass fails.
+func WithOnSyncError(fn func(err error)) SyncerOption {
+	return func(s *Syncer) {
+		s.onError = fn
+	}
+}
+
+// Syncer keeps a ReorgRepository in sync with the Merkle roots known to the SPV Wallet in the background.
+// Callbacks are invoked from the Syncer goroutine and delay the next sync until they return.
+type Syncer struct {
+	fetcher  Fetcher
+	repo     ReorgRepository
+	interval time.Duration
+
+	reorgDepth  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	onNewRoots  func(roots []models.MerkleRoot)
+	onReorg     func(reorg Reorg)
+	onError     func(err error)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.RWMutex
+	metrics SyncMetrics
+}
+
+// StartSyncer starts syncing the repository every interval until the context is canceled or Stop is called.
+// The first sync starts immediately. A failed sync is retried with an exponential backoff instead of waiting for the interval.
+// A non-positive interval is replaced with DefaultSyncInterval.
+func StartSyncer(ctx context.Context, fetcher Fetcher, repo ReorgRepository, interval time.Duration, opts ...SyncerOption) *Syncer {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+
+	s := &Syncer{
+		fetcher:     fetcher,
+		repo:        repo,
+		interval:    interval,
+		reorgDepth:  DefaultReorgDepth,
+		baseBackoff: defaultBaseBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		done:        make(chan struct{}),
+		metrics:     SyncMetrics{LastHeight: -1},
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+
+	return s
+}
+
+// Stop stops the Syncer and waits for the sync in progress to finish.
+func (s *Syncer) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+// Done returns a channel closed when the Syncer stops.
+func (s *Syncer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Metrics returns a snapshot of the Syncer activity.
+func (s *Syncer) Metrics() SyncMetrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.metrics
+}
+
+func (s *Syncer) run(ctx context.Context) {
+	defer close(s.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		err := s.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		timer.Reset(s.record(err))
+	}
+}
+
+// record updates the metrics with the sync outcome, invokes the error callback after releasing the lock,
+// so that the callback may read the metrics, and returns the delay before the next sync.
+func (s *Syncer) record(err error) time.Duration {
+	delay := s.recordMetrics(err)
+	if err != nil && s.onError != nil {
+		s.onError(err)
+	}
+
+	return delay
+}
+
+func (s *Syncer) recordMetrics(err error) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.metrics.Syncs++
+	s.metrics.LastSyncAt = now
+	s.metrics.LastError = err
+	if height, heightErr := s.repo.LastMerkleRootHeight(); heightErr == nil {
+		s.metrics.LastHeight = height
+	}
+
+	if err == nil {
+		s.metrics.ConsecutiveFailures = 0
+		s.metrics.LastSuccessAt = now
+		return s.interval
+	}
+
+	s.metrics.Failures++
+	s.metrics.ConsecutiveFailures++
+
+	backoff := s.baseBackoff
+	for i := 1; i < s.metrics.ConsecutiveFailures && backoff < s.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, s.maxBackoff)
+}
+
+// sync fetches the Merkle roots following the stored ones, starting reorgDepth roots back
+// to compare the recent stored roots with the ones known to the SPV Wallet.
+func (s *Syncer) sync(ctx context.Context) error {
+	lastHeight, err := s.repo.LastMerkleRootHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get last merkle root height: %w", err)
+	}
+
+	key := ""
+	if lastHeight >= 0 {
+		if key, err = s.repo.MerkleRootAtHeight(max(lastHeight-s.reorgDepth, 0)); err != nil {
+			return fmt.Errorf("failed to get merkle root to resume sync from: %w", err)
+		}
+	}
+
+	previousKey := ""
+	for {
+		page, err := s.fetcher.MerkleRoots(ctx, queries.MerkleRootsQueryWithLastEvaluatedKey(key))
+		if err != nil {
+			return fmt.Errorf("failed to fetch merkle roots: %w", err)
+		}
+		if len(page.Content) == 0 {
+			return nil
+		}
+
+		if lastHeight, err = s.save(page.Content, lastHeight); err != nil {
+			return err
+		}
+
+		key = page.Page.LastEvaluatedKey
+		if key == "" {
+			return nil
+		}
+		if key == previousKey {
+			return goclienterr.ErrStaleLastEvaluatedKey
+		}
+		previousKey = key
+	}
+}
+
+// save compares the fetched roots with the stored ones, handles a reorganization
+// and saves the new roots. Returns the highest stored block height.
+func (s *Syncer) save(fetched []models.MerkleRoot, lastHeight int) (int, error) {
+	fresh := make([]models.MerkleRoot, 0, len(fetched))
+	for _, root := range fetched {
+		if root.BlockHeight > lastHeight {
+			fresh = append(fresh, root)
+			continue
+		}
+
+		stored, err := s.repo.MerkleRootAtHeight(root.BlockHeight)
+		if errors.Is(err, goclienterr.ErrMerkleRootNotFound) {
+			fresh = append(fresh, root)
+			continue
+		}
+		if err != nil {
+			return lastHeight, fmt.Errorf("failed to get stored merkle root at height %d: %w", root.BlockHeight, err)
+		}
+		if stored == root.MerkleRoot {
+			continue
+		}
+
+		if err := s.rollback(root.BlockHeight, lastHeight); err != nil {
+			return lastHeight, err
+		}
+		lastHeight = root.BlockHeight - 1
+		fresh = append(fresh, root)
+	}
+
+	if len(fresh) == 0 {
+		return lastHeight, nil
+	}
+	if err := s.repo.SaveMerkleRoots(fresh); err != nil {
+		return lastHeight, fmt.Errorf("failed to save merkle roots: %w", err)
+	}
+
+	s.mu.Lock()
+	s.metrics.SyncedRoots += uint64(len(fresh))
+	s.mu.Unlock()
+	if s.onNewRoots != nil {
+		s.onNewRoots(fresh)
+	}
+
+	return max(lastHeight, fresh[len(fresh)-1].BlockHeight), nil
+}
+
+// rollback removes the Merkle roots orphaned by a reorganization starting at the given height.
+func (s *Syncer) rollback(height, lastHeight int) error {
+	orphaned := make([]models.MerkleRoot, 0, lastHeight-height+1)
+	for h := height; h <= lastHeight; h++ {
+		root, err := s.repo.MerkleRootAtHeight(h)
+		if errors.Is(err, goclienterr.ErrMerkleRootNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get orphaned merkle root at height %d: %w", h, err)
+		}
+		orphaned = append(orphaned, models.MerkleRoot{BlockHeight: h, MerkleRoot: root})
+	}
+
+	if err := s.repo.DeleteMerkleRootsFrom(height); err != nil {
+		return fmt.Errorf("failed to delete orphaned merkle roots: %w", err)
+	}
+
+	s.mu.Lock()
+	s.metrics.Reorgs++
+	s.mu.Unlock()
+	if s.onReorg != nil {
+		s.onReorg(Reorg{Height: height, Orphaned: orphaned})
+	}
+
+	return nil
+}
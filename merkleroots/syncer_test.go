@@ -0,0 +1,311 @@
+package merkleroots_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/merkleroots/merklerootstest"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	syncInterval = 10 * time.Millisecond
+	waitTimeout  = 2 * time.Second
+)
+
+func TestSyncer_NewRoots(t *testing.T) {
+	// given:
+	chain := newFakeChain(merklerootstest.MockedSPVWalletData[:5])
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	var (
+		mu      sync.Mutex
+		emitted []models.MerkleRoot
+	)
+	onNewRoots := func(roots []models.MerkleRoot) {
+		mu.Lock()
+		defer mu.Unlock()
+		emitted = append(emitted, roots...)
+	}
+
+	// when:
+	syncer := merkleroots.StartSyncer(context.Background(), chain, repo, syncInterval, merkleroots.WithOnNewRoots(onNewRoots))
+	defer syncer.Stop()
+	waitForHeight(t, syncer, 4)
+	chain.set(merklerootstest.MockedSPVWalletData)
+
+	// then:
+	last := merklerootstest.LastMockedMerkleRoot()
+	waitForHeight(t, syncer, last.BlockHeight)
+	require.Equal(t, last.MerkleRoot, repo.GetLastMerkleRoot())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, merklerootstest.MockedSPVWalletData, emitted)
+
+	metrics := syncer.Metrics()
+	require.Equal(t, uint64(len(merklerootstest.MockedSPVWalletData)), metrics.SyncedRoots)
+	require.Zero(t, metrics.Reorgs)
+	require.Zero(t, metrics.Failures)
+	require.NoError(t, metrics.LastError)
+	require.False(t, metrics.LastSuccessAt.IsZero())
+}
+
+func TestSyncer_Reorg(t *testing.T) {
+	// given:
+	stored := merklerootstest.MockedSPVWalletData[:6]
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	require.NoError(t, repo.SaveMerkleRoots(stored))
+
+	fork := append([]models.MerkleRoot{}, stored[:4]...)
+	fork = append(fork,
+		models.MerkleRoot{BlockHeight: 4, MerkleRoot: merklerootstest.MockedSPVWalletData[10].MerkleRoot},
+		models.MerkleRoot{BlockHeight: 5, MerkleRoot: merklerootstest.MockedSPVWalletData[11].MerkleRoot},
+		models.MerkleRoot{BlockHeight: 6, MerkleRoot: merklerootstest.MockedSPVWalletData[12].MerkleRoot},
+	)
+	chain := newFakeChain(fork)
+	reorgs := make(chan merkleroots.Reorg, 1)
+
+	// when:
+	syncer := merkleroots.StartSyncer(context.Background(), chain, repo, syncInterval,
+		merkleroots.WithOnReorg(func(r merkleroots.Reorg) { reorgs <- r }))
+	defer syncer.Stop()
+
+	// then:
+	select {
+	case reorg := <-reorgs:
+		require.Equal(t, 4, reorg.Height)
+		require.Equal(t, stored[4:], reorg.Orphaned)
+	case <-time.After(waitTimeout):
+		t.Fatal("reorg not detected")
+	}
+	waitForHeight(t, syncer, 6)
+	for _, expected := range fork {
+		root, err := repo.MerkleRootAtHeight(expected.BlockHeight)
+		require.NoError(t, err)
+		require.Equal(t, expected.MerkleRoot, root)
+	}
+	require.Equal(t, uint64(1), syncer.Metrics().Reorgs)
+}
+
+func TestSyncer_BackoffOnError(t *testing.T) {
+	// given:
+	fetchErr := errors.New("connection refused")
+	chain := newFakeChain(merklerootstest.MockedSPVWalletData[:3])
+	chain.fail(fetchErr)
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	errs := make(chan error, 10)
+
+	// when:
+	syncer := merkleroots.StartSyncer(context.Background(), chain, repo, time.Hour,
+		merkleroots.WithSyncBackoff(time.Millisecond, 5*time.Millisecond),
+		merkleroots.WithOnSyncError(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}))
+	defer syncer.Stop()
+
+	// then:
+	for range 2 {
+		select {
+		case err := <-errs:
+			require.ErrorIs(t, err, fetchErr)
+		case <-time.After(waitTimeout):
+			t.Fatal("failed sync not retried")
+		}
+	}
+	metrics := syncer.Metrics()
+	require.ErrorIs(t, metrics.LastError, fetchErr)
+	require.GreaterOrEqual(t, metrics.ConsecutiveFailures, 2)
+	require.True(t, metrics.LastSuccessAt.IsZero())
+
+	// when:
+	chain.fail(nil)
+
+	// then:
+	waitForHeight(t, syncer, 2)
+	metrics = syncer.Metrics()
+	require.Zero(t, metrics.ConsecutiveFailures)
+	require.NoError(t, metrics.LastError)
+}
+
+func TestSyncer_InvalidBackoff(t *testing.T) {
+	tests := map[string]struct {
+		base        time.Duration
+		max         time.Duration
+		maxFailures uint64
+	}{
+		"Non-positive base is replaced with the default": {
+			base:        0,
+			max:         time.Hour,
+			maxFailures: 1,
+		},
+		"Max below base is raised to base": {
+			base:        20 * time.Millisecond,
+			max:         0,
+			maxFailures: 10,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			chain := newFakeChain(merklerootstest.MockedSPVWalletData[:3])
+			chain.fail(errors.New("connection refused"))
+			repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+
+			// when:
+			syncer := merkleroots.StartSyncer(context.Background(), chain, repo, time.Hour,
+				merkleroots.WithSyncBackoff(tc.base, tc.max))
+			defer syncer.Stop()
+			time.Sleep(100 * time.Millisecond)
+
+			// then:
+			failures := syncer.Metrics().Failures
+			require.NotZero(t, failures)
+			require.LessOrEqual(t, failures, tc.maxFailures)
+		})
+	}
+}
+
+func TestSyncer_OnSyncErrorReadsMetrics(t *testing.T) {
+	// given:
+	chain := newFakeChain(merklerootstest.MockedSPVWalletData[:3])
+	chain.fail(errors.New("connection refused"))
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	failures := make(chan uint64, 10)
+
+	// when:
+	var syncer *merkleroots.Syncer
+	ready := make(chan struct{})
+	syncer = merkleroots.StartSyncer(context.Background(), chain, repo, time.Hour,
+		merkleroots.WithSyncBackoff(time.Millisecond, 5*time.Millisecond),
+		merkleroots.WithOnSyncError(func(error) {
+			<-ready
+			select {
+			case failures <- syncer.Metrics().Failures:
+			default:
+			}
+		}))
+	close(ready)
+	defer syncer.Stop()
+
+	// then:
+	for expected := uint64(1); expected <= 2; expected++ {
+		select {
+		case got := <-failures:
+			require.Equal(t, expected, got)
+		case <-time.After(waitTimeout):
+			t.Fatal("syncer deadlocked in the error callback")
+		}
+	}
+}
+
+func TestSyncer_NonPositiveInterval(t *testing.T) {
+	// given:
+	chain := newFakeChain(merklerootstest.MockedSPVWalletData[:3])
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+
+	// when:
+	syncer := merkleroots.StartSyncer(context.Background(), chain, repo, 0)
+	defer syncer.Stop()
+	waitForHeight(t, syncer, 2)
+	time.Sleep(50 * time.Millisecond)
+
+	// then:
+	require.Equal(t, uint64(1), syncer.Metrics().Syncs)
+}
+
+func TestSyncer_Stop(t *testing.T) {
+	// given:
+	ctx, cancel := context.WithCancel(context.Background())
+	chain := newFakeChain(merklerootstest.MockedSPVWalletData)
+	repo := givenFileRepository(t, filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	syncer := merkleroots.StartSyncer(ctx, chain, repo, time.Hour)
+	waitForHeight(t, syncer, merklerootstest.LastMockedMerkleRoot().BlockHeight)
+
+	// when:
+	cancel()
+
+	// then:
+	select {
+	case <-syncer.Done():
+	case <-time.After(waitTimeout):
+		t.Fatal("syncer not stopped")
+	}
+	syncer.Stop()
+	require.Equal(t, uint64(1), syncer.Metrics().Syncs)
+}
+
+// fakeChain serves Merkle roots the way the SPV Wallet does, one root per page
+// to exercise the pagination. The chain can be replaced to simulate a reorganization.
+type fakeChain struct {
+	mu    sync.Mutex
+	roots []models.MerkleRoot
+	err   error
+}
+
+func newFakeChain(roots []models.MerkleRoot) *fakeChain {
+	return &fakeChain{roots: roots}
+}
+
+func (c *fakeChain) set(roots []models.MerkleRoot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.roots = roots
+}
+
+func (c *fakeChain) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func (c *fakeChain) MerkleRoots(_ context.Context, opts ...queries.MerkleRootsQueryOption) (*queries.MerkleRootPage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var query queries.MerkleRootsQuery
+	for _, o := range opts {
+		o(&query)
+	}
+
+	next := 0
+	if query.LastEvaluatedKey != "" {
+		next = len(c.roots)
+		for i, root := range c.roots {
+			if root.MerkleRoot == query.LastEvaluatedKey {
+				next = i + 1
+				break
+			}
+		}
+	}
+
+	page := &queries.MerkleRootPage{Content: []models.MerkleRoot{}}
+	if next < len(c.roots) {
+		page.Content = c.roots[next : next+1]
+		page.Page.LastEvaluatedKey = c.roots[next].MerkleRoot
+	}
+
+	return page, nil
+}
+
+func waitForHeight(t *testing.T, syncer *merkleroots.Syncer, height int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return syncer.Metrics().LastHeight == height
+	}, waitTimeout, time.Millisecond)
+}
@@ -0,0 +1,163 @@
+// Package metrics provides a Prometheus implementation of config.Metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultNamespace is the namespace of the metric names, e.g. "spvwallet_client_requests_total".
+const DefaultNamespace = "spvwallet_client"
+
+// Metric label names.
+const (
+	EndpointLabel    = "endpoint"
+	MethodLabel      = "method"
+	StatusClassLabel = "status_class"
+	ResultLabel      = "result"
+)
+
+// PrometheusOption configures a Prometheus collector.
+type PrometheusOption func(*prometheusOptions)
+
+type prometheusOptions struct {
+	namespace      string
+	latencyBuckets []float64
+	signingBuckets []float64
+	constLabels    prometheus.Labels
+}
+
+// WithNamespace sets the namespace of the metric names.
+func WithNamespace(namespace string) PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.namespace = namespace
+	}
+}
+
+// WithLatencyBuckets sets the buckets, in seconds, of the request duration histogram.
+func WithLatencyBuckets(buckets ...float64) PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.latencyBuckets = buckets
+	}
+}
+
+// WithSigningBuckets sets the buckets, in seconds, of the request signing duration histogram.
+func WithSigningBuckets(buckets ...float64) PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.signingBuckets = buckets
+	}
+}
+
+// WithConstLabels adds labels with fixed values to every metric, e.g. to tell several clients apart.
+func WithConstLabels(labels map[string]string) PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.constLabels = labels
+	}
+}
+
+// Prometheus records the client metrics as Prometheus metrics:
+//   - <namespace>_requests_total counter of the request attempts by endpoint, method and status class,
+//   - <namespace>_request_duration_seconds histogram of the request attempts by endpoint, method and status class,
+//   - <namespace>_retries_total counter of the retried request attempts by endpoint and method,
+//   - <namespace>_signing_duration_seconds histogram of the request signing by result, "success" or "error".
+//
+// The status class is "2xx" to "5xx", or "error" when no response was received. Set it on the client configuration
+// with config.WithMetrics and expose it with Handler, or register it in an existing prometheus.Registerer.
+type Prometheus struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+	signing  *prometheus.HistogramVec
+}
+
+// NewPrometheus creates a Prometheus collector, by default with the DefaultNamespace,
+// the prometheus.DefBuckets request duration buckets and sub-millisecond signing duration buckets.
+func NewPrometheus(opts ...PrometheusOption) *Prometheus {
+	o := prometheusOptions{
+		namespace:      DefaultNamespace,
+		latencyBuckets: prometheus.DefBuckets,
+		signingBuckets: prometheus.ExponentialBuckets(0.0001, 2, 10),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Prometheus{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Name:        "requests_total",
+			Help:        "Number of HTTP request attempts sent to the SPV Wallet API.",
+			ConstLabels: o.constLabels,
+		}, []string{EndpointLabel, MethodLabel, StatusClassLabel}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of the HTTP request attempts sent to the SPV Wallet API.",
+			Buckets:     o.latencyBuckets,
+			ConstLabels: o.constLabels,
+		}, []string{EndpointLabel, MethodLabel, StatusClassLabel}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Name:        "retries_total",
+			Help:        "Number of HTTP request attempts retried by the retry policy.",
+			ConstLabels: o.constLabels,
+		}, []string{EndpointLabel, MethodLabel}),
+		signing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.namespace,
+			Name:        "signing_duration_seconds",
+			Help:        "Duration of the HTTP request signing.",
+			Buckets:     o.signingBuckets,
+			ConstLabels: o.constLabels,
+		}, []string{ResultLabel}),
+	}
+}
+
+// ObserveRequest implements config.Metrics.
+func (p *Prometheus) ObserveRequest(endpoint, method string, statusCode int, duration time.Duration) {
+	class := config.StatusClass(statusCode)
+	p.requests.WithLabelValues(endpoint, method, class).Inc()
+	p.latency.WithLabelValues(endpoint, method, class).Observe(duration.Seconds())
+}
+
+// ObserveRetry implements config.Metrics.
+func (p *Prometheus) ObserveRetry(endpoint, method string) {
+	p.retries.WithLabelValues(endpoint, method).Inc()
+}
+
+// ObserveSigning implements config.Metrics.
+func (p *Prometheus) ObserveSigning(duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	p.signing.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (p *Prometheus) Describe(ch chan<- *prometheus.Desc) {
+	p.requests.Describe(ch)
+	p.latency.Describe(ch)
+	p.retries.Describe(ch)
+	p.signing.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (p *Prometheus) Collect(ch chan<- prometheus.Metric) {
+	p.requests.Collect(ch)
+	p.latency.Collect(ch)
+	p.retries.Collect(ch)
+	p.signing.Collect(ch)
+}
+
+// Handler returns an http.Handler exposing the client metrics in the Prometheus exposition format.
+// The metrics are served from a dedicated registry; to expose them together with other metrics,
+// register the collector in the application registry instead.
+func (p *Prometheus) Handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(p)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,88 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/metrics"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+var _ config.Metrics = (*metrics.Prometheus)(nil)
+
+const xpubsURL = "/api/v1/users/current"
+
+func TestPrometheus_Handler(t *testing.T) {
+	tests := map[string]struct {
+		responder       httpmock.Responder
+		expectedMetrics []string
+	}{
+		"HTTP GET /api/v1/users/current response: 200": {
+			responder: httpmock.NewStringResponder(http.StatusOK, "{}"),
+			expectedMetrics: []string{
+				`spvwallet_client_requests_total{endpoint="user/xpubs",method="GET",status_class="2xx"} 1`,
+				`spvwallet_client_request_duration_seconds_count{endpoint="user/xpubs",method="GET",status_class="2xx"} 1`,
+				`spvwallet_client_signing_duration_seconds_count{result="success"} 1`,
+			},
+		},
+		"HTTP GET /api/v1/users/current response: 500": {
+			responder: testutils.NewInternalServerSPVErrorResponder(),
+			expectedMetrics: []string{
+				`spvwallet_client_requests_total{endpoint="user/xpubs",method="GET",status_class="5xx"} 1`,
+				`spvwallet_client_request_duration_seconds_count{endpoint="user/xpubs",method="GET",status_class="5xx"} 1`,
+			},
+		},
+		"HTTP GET /api/v1/users/current no response": {
+			responder: httpmock.NewErrorResponder(io.ErrUnexpectedEOF),
+			expectedMetrics: []string{
+				`spvwallet_client_requests_total{endpoint="user/xpubs",method="GET",status_class="error"} 1`,
+			},
+		},
+	}
+
+	url := testutils.FullAPIURL(t, xpubsURL)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			collector := metrics.NewPrometheus()
+			wallet, transport := testutils.GivenSPVUserAPIWithMetrics(t, collector)
+			transport.RegisterResponder(http.MethodGet, url, tc.responder)
+
+			// when:
+			_, _ = wallet.XPub(context.Background())
+
+			// then:
+			exposed := scrape(t, collector.Handler())
+			for _, metric := range tc.expectedMetrics {
+				require.Contains(t, exposed, metric)
+			}
+		})
+	}
+}
+
+func TestPrometheus_ObserveRetry(t *testing.T) {
+	// given:
+	collector := metrics.NewPrometheus(metrics.WithNamespace("wallet"), metrics.WithConstLabels(map[string]string{"client": "payments"}))
+
+	// when:
+	collector.ObserveRetry("user/transactions", http.MethodGet)
+	collector.ObserveRetry("user/transactions", http.MethodGet)
+
+	// then:
+	require.Contains(t, scrape(t, collector.Handler()), `wallet_retries_total{client="payments",endpoint="user/transactions",method="GET"} 2`)
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
@@ -3,32 +3,38 @@ package notifications
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"runtime"
 	"time"
 
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet/models"
 )
 
 // WebhookOptions - options for the webhook
 type WebhookOptions struct {
-	TokenHeader string
-	TokenValue  string
-	BufferSize  int
-	RootContext context.Context
-	Processors  int
+	TokenHeader    string
+	TokenValue     string
+	BufferSize     int
+	RootContext    context.Context
+	Processors     int
+	EnqueueTimeout time.Duration
+	OnError        func(event *models.RawEvent, err error)
+	DeadLetter     func(event *models.RawEvent)
 }
 
 // NewWebhookOptions - creates a new webhook options
 func NewWebhookOptions() *WebhookOptions {
 	return &WebhookOptions{
-		TokenHeader: "",
-		TokenValue:  "",
-		BufferSize:  100,
-		Processors:  runtime.NumCPU(),
-		RootContext: context.Background(),
+		TokenHeader:    "",
+		TokenValue:     "",
+		BufferSize:     100,
+		Processors:     runtime.NumCPU(),
+		RootContext:    context.Background(),
+		EnqueueTimeout: 1 * time.Second,
 	}
 }
 
@@ -43,7 +49,8 @@ func WithToken(tokenHeader, tokenValue string) WebhookOpts {
 	}
 }
 
-// WithBufferSize - sets the buffer size
+// WithBufferSize - sets the buffer size; it must be at least the number of events the SPV Wallet sends
+// in one request (up to 100), since larger batches are rejected with 413 Request Entity Too Large
 func WithBufferSize(size int) WebhookOpts {
 	return func(w *WebhookOptions) {
 		w.BufferSize = size
@@ -64,7 +71,32 @@ func WithProcessors(count int) WebhookOpts {
 	}
 }
 
-// WebhookSubscriber - interface for subscribing and unsubscribing to webhooks
+// WithEnqueueTimeout - sets how long the HTTP handler waits for space in the buffer for the whole batch
+// before rejecting the request with 503 Service Unavailable; with 0 the request is rejected only
+// when the buffer is full
+func WithEnqueueTimeout(timeout time.Duration) WebhookOpts {
+	return func(w *WebhookOptions) {
+		w.EnqueueTimeout = timeout
+	}
+}
+
+// WithOnError - sets the hook called when an event cannot be decoded (errors.ErrWebhookEventContent)
+// or its handler or the dead-letter sink panics (errors.ErrWebhookHandlerPanic); it is called concurrently by the processors
+func WithOnError(onError func(event *models.RawEvent, err error)) WebhookOpts {
+	return func(w *WebhookOptions) {
+		w.OnError = onError
+	}
+}
+
+// WithDeadLetter - sets the sink receiving events of types without a registered handler;
+// without it such events are discarded; it is called concurrently by the processors
+func WithDeadLetter(sink func(event *models.RawEvent)) WebhookOpts {
+	return func(w *WebhookOptions) {
+		w.DeadLetter = sink
+	}
+}
+
+// WebhookSubscriber - interface for subscribing and unsubscribing to webhooks; it is implemented by spvwallet.AdminAPI
 type WebhookSubscriber interface {
 	AdminSubscribeWebhook(ctx context.Context, webhookURL, tokenHeader, tokenValue string) error
 	AdminUnsubscribeWebhook(ctx context.Context, webhookURL string) error
@@ -75,6 +107,8 @@ type Webhook struct {
 	URL        string
 	options    *WebhookOptions
 	buffer     chan *models.RawEvent
+	slots      chan struct{} // buffer slots reserved by the HTTP handlers, released by the processors
+	reserving  chan struct{} // held by the HTTP handler reserving slots, so that batches don't hold a part of them each
 	subscriber WebhookSubscriber
 	handlers   *eventsMap
 }
@@ -90,6 +124,8 @@ func NewWebhook(subscriber WebhookSubscriber, url string, opts ...WebhookOpts) *
 		URL:        url,
 		options:    options,
 		buffer:     make(chan *models.RawEvent, options.BufferSize),
+		slots:      make(chan struct{}, options.BufferSize),
+		reserving:  make(chan struct{}, 1),
 		subscriber: subscriber,
 		handlers:   newEventsMap(),
 	}
@@ -117,7 +153,10 @@ func (w *Webhook) Unsubscribe(ctx context.Context) error {
 	return nil
 }
 
-// HTTPHandler - returns an http handler for the webhook; it should be registered with the http server
+// HTTPHandler - returns an http handler for the webhook; it should be registered with the http server.
+// The events of a request are enqueued only once the buffer has space for all of them. When it doesn't
+// within the enqueue timeout, the request is rejected with 503 Service Unavailable without enqueuing
+// any event, so that the SPV Wallet retries the whole batch later.
 func (w *Webhook) HTTPHandler() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		if w.options.TokenHeader != "" && r.Header.Get(w.options.TokenHeader) != w.options.TokenValue {
@@ -130,39 +169,136 @@ func (w *Webhook) HTTPHandler() http.Handler {
 			return
 		}
 
+		if len(events) > cap(w.buffer) {
+			http.Error(rw, "Event batch larger than the buffer", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		switch err := w.reserve(r.Context(), len(events)); {
+		case errors.Is(err, errWebhookStopped):
+			// root context canceled - the whole event processing has been stopped
+			http.Error(rw, "Webhook stopped", http.StatusServiceUnavailable)
+			return
+		case errors.Is(err, errEventBufferFull):
+			// the buffer is full - let the SPV Wallet retry later
+			http.Error(rw, "Event buffer full", http.StatusServiceUnavailable)
+			return
+		case err != nil:
+			// request context canceled
+			return
+		}
 		for _, event := range events {
-			select {
-			case w.buffer <- event:
-				// event sent
-			case <-r.Context().Done():
-				// request context canceled
-				return
-			case <-w.options.RootContext.Done():
-				// root context canceled - the whole event processing has been stopped
-				return
-			case <-time.After(1 * time.Second):
-				// timeout, most probably the channel is full
-			}
+			w.buffer <- event // doesn't block, the slot is reserved
 		}
 		rw.WriteHeader(http.StatusOK)
 	})
 }
 
+var (
+	errWebhookStopped  = errors.New("webhook stopped")
+	errEventBufferFull = errors.New("event buffer full")
+)
+
+// reserve reserves n slots of the buffer, waiting for them up to the enqueue timeout,
+// which starts once the buffer is full. On failure, none of the slots stays reserved.
+func (w *Webhook) reserve(ctx context.Context, n int) error {
+	if w.options.RootContext.Err() != nil {
+		return errWebhookStopped
+	}
+
+	deadline := &enqueueDeadline{timeout: w.options.EnqueueTimeout}
+	defer deadline.stop()
+
+	if err := w.acquire(ctx, w.reserving, deadline); err != nil {
+		return err
+	}
+	defer func() { <-w.reserving }()
+
+	for i := 0; i < n; i++ {
+		if err := w.acquire(ctx, w.slots, deadline); err != nil {
+			for ; i > 0; i-- {
+				<-w.slots
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// acquire takes a token of the semaphore, failing only when it's not available without waiting
+// and doesn't become available before the deadline.
+func (w *Webhook) acquire(ctx context.Context, semaphore chan<- struct{}, deadline *enqueueDeadline) error {
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	default:
+	}
+
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.options.RootContext.Done():
+		return errWebhookStopped
+	case <-deadline.C():
+		return errEventBufferFull
+	}
+}
+
+// enqueueDeadline is the enqueue timeout of a request, started when the request has to wait for the first time.
+type enqueueDeadline struct {
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func (d *enqueueDeadline) C() <-chan time.Time {
+	if d.timer == nil {
+		d.timer = time.NewTimer(d.timeout)
+	}
+	return d.timer.C
+}
+
+func (d *enqueueDeadline) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
 func (w *Webhook) process() {
 	for {
 		select {
 		case event := <-w.buffer:
-			handler, ok := w.handlers.load(event.Type)
-			if !ok {
-				continue
-			}
-			model := reflect.New(handler.ModelType).Interface()
-			if err := json.Unmarshal(event.Content, model); err != nil {
-				continue
+			<-w.slots
+			if err := w.handle(event); err != nil && w.options.OnError != nil {
+				w.options.OnError(event, err)
 			}
-			handler.Caller.Call([]reflect.Value{reflect.ValueOf(model)})
 		case <-w.options.RootContext.Done():
 			return
 		}
 	}
 }
+
+func (w *Webhook) handle(event *models.RawEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s: %v", goclienterr.ErrWebhookHandlerPanic, event.Type, r)
+		}
+	}()
+
+	handler, ok := w.handlers.load(event.Type)
+	if !ok {
+		if w.options.DeadLetter != nil {
+			w.options.DeadLetter(event)
+		}
+		return nil
+	}
+
+	model := reflect.New(handler.ModelType).Interface()
+	if err := json.Unmarshal(event.Content, model); err != nil {
+		return errors.Join(goclienterr.ErrWebhookEventContent, err)
+	}
+	handler.Caller.Call([]reflect.Value{reflect.ValueOf(model)})
+
+	return nil
+}
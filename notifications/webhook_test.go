@@ -0,0 +1,233 @@
+package notifications_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/notifications"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	webhookURL    = "http://client.example.com/notifications"
+	helloEvent    = `{"type":"StringEvent","content":{"value":"hello"}}`
+	deliveryLimit = time.Second
+)
+
+func TestWebhook_HTTPHandler(t *testing.T) {
+	tests := map[string]struct {
+		queued       string
+		body         string
+		bufferSize   int
+		timeout      time.Duration
+		stopped      bool
+		expectedCode int
+	}{
+		"Events accepted": {
+			body:         "[" + helloEvent + "]",
+			bufferSize:   1,
+			expectedCode: http.StatusOK,
+		},
+		"Batch filling the buffer": {
+			body:         "[" + helloEvent + "," + helloEvent + "]",
+			bufferSize:   2,
+			expectedCode: http.StatusOK,
+		},
+		"Malformed body": {
+			body:         "{",
+			bufferSize:   1,
+			expectedCode: http.StatusBadRequest,
+		},
+		"Buffer full": {
+			queued:       "[" + helloEvent + "]",
+			body:         "[" + helloEvent + "]",
+			bufferSize:   1,
+			timeout:      10 * time.Millisecond,
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		"Buffer full without enqueue timeout": {
+			queued:       "[" + helloEvent + "]",
+			body:         "[" + helloEvent + "]",
+			bufferSize:   1,
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		"Batch larger than the buffer": {
+			body:         "[" + helloEvent + "," + helloEvent + "]",
+			bufferSize:   1,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		"Webhook stopped": {
+			body:         "[" + helloEvent + "]",
+			bufferSize:   1,
+			stopped:      true,
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			webhook := notifications.NewWebhook(nil, webhookURL,
+				notifications.WithRootContext(ctx),
+				notifications.WithProcessors(0),
+				notifications.WithBufferSize(tc.bufferSize),
+				notifications.WithEnqueueTimeout(tc.timeout))
+			if tc.queued != "" {
+				require.Equal(t, http.StatusOK, serve(webhook, tc.queued).Code)
+			}
+			if tc.stopped {
+				cancel()
+			}
+
+			// when:
+			rec := serve(webhook, tc.body)
+
+			// then:
+			require.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	t.Run("Rejected batch enqueues no events", func(t *testing.T) {
+		// given:
+		webhook := notifications.NewWebhook(nil, webhookURL,
+			notifications.WithProcessors(0),
+			notifications.WithBufferSize(2),
+			notifications.WithEnqueueTimeout(10*time.Millisecond))
+		require.Equal(t, http.StatusOK, serve(webhook, "["+helloEvent+"]").Code)
+
+		// when:
+		rec := serve(webhook, "["+helloEvent+","+helloEvent+"]")
+
+		// then:
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		require.Equal(t, http.StatusOK, serve(webhook, "["+helloEvent+"]").Code)
+	})
+
+	t.Run("Reserved slots are released by the processors", func(t *testing.T) {
+		// given:
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		values := make(chan string, 4)
+		webhook := notifications.NewWebhook(nil, webhookURL,
+			notifications.WithRootContext(ctx),
+			notifications.WithProcessors(1),
+			notifications.WithBufferSize(2),
+			notifications.WithEnqueueTimeout(deliveryLimit))
+		require.NoError(t, notifications.RegisterHandler(webhook, func(event *models.StringEvent) {
+			values <- event.Value
+		}))
+
+		// when:
+		for range 2 {
+			require.Equal(t, http.StatusOK, serve(webhook, "["+helloEvent+","+helloEvent+"]").Code)
+		}
+
+		// then:
+		for range 4 {
+			select {
+			case value := <-values:
+				require.Equal(t, "hello", value)
+			case <-time.After(deliveryLimit):
+				t.Fatal("event not delivered")
+			}
+		}
+	})
+}
+
+func TestWebhook_EventProcessing(t *testing.T) {
+	tests := map[string]struct {
+		event          string
+		handler        func(event *models.StringEvent)
+		expectedErr    error
+		expectedLetter string
+	}{
+		"Event with malformed content": {
+			event:       `{"type":"StringEvent","content":{"value":1}}`,
+			handler:     func(*models.StringEvent) {},
+			expectedErr: goclienterr.ErrWebhookEventContent,
+		},
+		"Panicking handler": {
+			event:       helloEvent,
+			handler:     func(*models.StringEvent) { panic("boom") },
+			expectedErr: goclienterr.ErrWebhookHandlerPanic,
+		},
+		"Event without handler": {
+			event:          `{"type":"TransactionEvent","content":{"transactionId":"tx"}}`,
+			handler:        func(*models.StringEvent) {},
+			expectedLetter: "TransactionEvent",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errs := make(chan error, 1)
+			letters := make(chan *models.RawEvent, 1)
+			webhook := notifications.NewWebhook(nil, webhookURL,
+				notifications.WithRootContext(ctx),
+				notifications.WithProcessors(1),
+				notifications.WithOnError(func(_ *models.RawEvent, err error) { errs <- err }),
+				notifications.WithDeadLetter(func(event *models.RawEvent) { letters <- event }))
+			require.NoError(t, notifications.RegisterHandler(webhook, tc.handler))
+
+			// when:
+			rec := serve(webhook, "["+tc.event+"]")
+
+			// then:
+			require.Equal(t, http.StatusOK, rec.Code)
+			select {
+			case err := <-errs:
+				require.ErrorIs(t, err, tc.expectedErr)
+			case letter := <-letters:
+				require.Nil(t, tc.expectedErr)
+				require.Equal(t, tc.expectedLetter, letter.Type)
+			case <-time.After(deliveryLimit):
+				t.Fatal("event silently dropped")
+			}
+		})
+	}
+
+	t.Run("Processor keeps running after handler panic", func(t *testing.T) {
+		// given:
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		values := make(chan string, 1)
+		webhook := notifications.NewWebhook(nil, webhookURL, notifications.WithRootContext(ctx), notifications.WithProcessors(1))
+		require.NoError(t, notifications.RegisterHandler(webhook, func(event *models.StringEvent) {
+			if event.Value == "panic" {
+				panic("boom")
+			}
+			values <- event.Value
+		}))
+
+		// when:
+		rec := serve(webhook, `[{"type":"StringEvent","content":{"value":"panic"}},`+helloEvent+`]`)
+
+		// then:
+		require.Equal(t, http.StatusOK, rec.Code)
+		select {
+		case value := <-values:
+			require.Equal(t, "hello", value)
+		case <-time.After(deliveryLimit):
+			t.Fatal("event not delivered after handler panic")
+		}
+	})
+}
+
+func serve(webhook *notifications.Webhook, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, webhookURL, strings.NewReader(body))
+	webhook.HTTPHandler().ServeHTTP(rec, req)
+
+	return rec
+}
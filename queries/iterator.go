@@ -0,0 +1,32 @@
+package queries
+
+// Iterator is a push-style sequence of paginated API resources, shaped like iter.Seq2[*T, error].
+// It calls yield for every element, page after page, until all pages are exhausted
+// or yield returns false. A non-nil error is yielded at most once, with a nil element,
+// after which iteration stops.
+//
+// With Go 1.23 or later the iterator can be used directly in a range-over-func loop:
+//
+//	for tx, err := range userAPI.AllTransactions(ctx) { ... }
+//
+// With older Go releases it can be called with a callback function:
+//
+//	userAPI.AllTransactions(ctx)(func(tx *response.Transaction, err error) bool { ... })
+type Iterator[T any] func(yield func(*T, error) bool)
+
+// Collect drains the iterator and returns all elements in order.
+// It returns the elements gathered so far together with the first error encountered.
+func (it Iterator[T]) Collect() ([]*T, error) {
+	var items []*T
+	var iterErr error
+	it(func(item *T, err error) bool {
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		items = append(items, item)
+		return true
+	})
+
+	return items, iterErr
+}
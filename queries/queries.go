@@ -52,6 +52,15 @@ func QueryWithPageFilter[F QueryFilters](f filter.Page) QueryOption[F] {
 	}
 }
 
+// QueryWithPrefetch enables fetching the next page concurrently while the elements
+// of the current page are being consumed. It only affects page iterators, such as
+// UserAPI.AllTransactions, and is never sent to the SPV Wallet API.
+func QueryWithPrefetch[F QueryFilters]() QueryOption[F] {
+	return func(q *Query[F]) {
+		q.Prefetch = true
+	}
+}
+
 // QueryWithFilter adds search parameters to the search URL corresponding to the specified filter type.
 func QueryWithFilter[F QueryFilters](f F) QueryOption[F] {
 	return func(q *Query[F]) {
@@ -81,6 +90,7 @@ type Query[F QueryFilters] struct {
 	Metadata   map[string]any // Metadata filters for refining the search.
 	PageFilter filter.Page    // Pagination details, including page number, size, and sorting.
 	Filter     F              // Specific filter for refining the query.
+	Prefetch   bool           // Prefetch the next page while iterating over the current one.
 }
 
 // NewQuery creates a new Query instance, applying the provided functional options.
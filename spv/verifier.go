@@ -0,0 +1,248 @@
+// Package spv verifies Merkle proofs of transactions against Merkle roots synchronized
+// with UserAPI.SyncMerkleRoots, without trusting the transaction status reported by the SPV Wallet.
+package spv
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	trx "github.com/bitcoin-sv/go-sdk/transaction"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+)
+
+// beefHexPrefix is the hex encoded BEEF version marker (0x0100BEEF).
+const beefHexPrefix = "0100beef"
+
+// Status is the outcome of a Merkle proof verification.
+type Status string
+
+const (
+	// StatusValid means the Merkle proof leads to the Merkle root stored for its block height.
+	StatusValid Status = "valid"
+	// StatusUnknownRoot means no Merkle root is stored for the block height of the proof,
+	// usually because the Merkle roots are not synchronized up to that block yet.
+	StatusUnknownRoot Status = "unknown_root"
+	// StatusInvalidPath means the Merkle path doesn't contain the transaction
+	// or leads to a different Merkle root than the one stored for its block height.
+	StatusInvalidPath Status = "invalid_path"
+	// StatusMissingProof means neither the transaction nor its ancestors come with a Merkle proof.
+	StatusMissingProof Status = "missing_proof"
+	// StatusHeightMismatch means the proof is valid but for a different block height than reported by the SPV Wallet.
+	StatusHeightMismatch Status = "height_mismatch"
+)
+
+// severity orders statuses so that the worst one is reported for a transaction with many proofs.
+var severity = map[Status]int{
+	StatusValid:          0,
+	StatusUnknownRoot:    1,
+	StatusMissingProof:   2,
+	StatusHeightMismatch: 3,
+	StatusInvalidPath:    4,
+}
+
+// Proof is the verification result of a single Merkle proof.
+type Proof struct {
+	TxID        string
+	Status      Status
+	BlockHeight uint32
+	// MerkleRoot is the root computed from the Merkle path, empty if it couldn't be computed.
+	MerkleRoot string
+}
+
+// Result is the verification result of a transaction.
+type Result struct {
+	TxID string
+	// Status is the worst status among Proofs.
+	Status Status
+	// BlockHeight is the height of the block the transaction is mined in, 0 if it has no Merkle proof itself.
+	BlockHeight uint32
+	// Proofs holds the verified Merkle proofs: the transaction's own one or, for an unmined
+	// transaction in BEEF format, the ones of its mined ancestors.
+	Proofs []Proof
+}
+
+// Valid reports whether all Merkle proofs of the transaction are valid.
+func (r *Result) Valid() bool {
+	return r.Status == StatusValid
+}
+
+// Verifier verifies Merkle proofs of transactions against the synchronized Merkle roots.
+type Verifier struct {
+	roots merkleroots.Lookup
+}
+
+// NewVerifier returns a Verifier looking up Merkle roots in the given repository,
+// e.g. merkleroots.FileRepository or merkleroots.SQLRepository kept in sync with UserAPI.SyncMerkleRoots.
+func NewVerifier(roots merkleroots.Lookup) *Verifier {
+	return &Verifier{roots: roots}
+}
+
+// VerifyBEEF verifies a transaction in BEEF format. A mined transaction is verified with its own
+// Merkle proof, an unmined one with the Merkle proofs of all its ancestors.
+// Returns errors.ErrInvalidBEEF if the BEEF cannot be parsed.
+func (v *Verifier) VerifyBEEF(beefHex string) (*Result, error) {
+	tx, err := parseBEEF(beefHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.verify(tx)
+}
+
+// VerifyRawTransaction verifies a raw transaction against its Merkle path in BUMP format (BRC-74).
+// Returns errors.ErrFailedToParseHex or errors.ErrInvalidMerklePath if the input cannot be parsed.
+func (v *Verifier) VerifyRawTransaction(txHex, merklePathHex string) (*Result, error) {
+	tx, err := trx.NewTransactionFromHex(txHex)
+	if err != nil {
+		return nil, errors.Join(goclienterr.ErrFailedToParseHex, err)
+	}
+	if merklePathHex != "" {
+		if tx.MerklePath, err = trx.NewMerklePathFromHex(merklePathHex); err != nil {
+			return nil, errors.Join(goclienterr.ErrInvalidMerklePath, err)
+		}
+	}
+
+	return v.verify(tx)
+}
+
+// VerifyTransaction independently confirms a transaction record returned by the SPV Wallet.
+// The record's hex is verified as BEEF if it is in that format, otherwise as a raw transaction
+// with the given Merkle path. A valid proof for a block height other than the one of the record
+// is reported as StatusHeightMismatch.
+// Returns errors.ErrTransactionMissing if the record is nil, and errors.ErrTransactionIDMismatch
+// if the hex doesn't hash to the record's ID.
+func (v *Verifier) VerifyTransaction(tx *response.Transaction, merklePathHex string) (*Result, error) {
+	if tx == nil {
+		return nil, goclienterr.ErrTransactionMissing
+	}
+
+	var (
+		res *Result
+		err error
+	)
+	if strings.HasPrefix(strings.ToLower(tx.Hex), beefHexPrefix) {
+		res, err = v.VerifyBEEF(tx.Hex)
+	} else {
+		res, err = v.VerifyRawTransaction(tx.Hex, merklePathHex)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.ID != "" && tx.ID != res.TxID {
+		return nil, fmt.Errorf("%w: record %s, hex %s", goclienterr.ErrTransactionIDMismatch, tx.ID, res.TxID)
+	}
+	if res.Valid() && tx.BlockHeight != 0 && tx.BlockHeight != uint64(res.BlockHeight) {
+		res.Status = StatusHeightMismatch
+	}
+
+	return res, nil
+}
+
+func (v *Verifier) verify(tx *trx.Transaction) (*Result, error) {
+	res := &Result{TxID: tx.TxID().String()}
+	if tx.MerklePath != nil {
+		res.BlockHeight = tx.MerklePath.BlockHeight
+	}
+
+	proofs, err := v.collectProofs(tx, make(map[string]struct{}))
+	if err != nil {
+		return nil, err
+	}
+
+	res.Proofs = proofs
+	res.Status = StatusValid
+	for _, p := range proofs {
+		if severity[p.Status] > severity[res.Status] {
+			res.Status = p.Status
+		}
+	}
+
+	return res, nil
+}
+
+// collectProofs verifies the Merkle proof of the transaction or, if it has none, of its ancestors.
+func (v *Verifier) collectProofs(tx *trx.Transaction, visited map[string]struct{}) ([]Proof, error) {
+	txID := tx.TxID().String()
+	if _, ok := visited[txID]; ok {
+		return nil, nil
+	}
+	visited[txID] = struct{}{}
+
+	if tx.MerklePath != nil {
+		proof, err := v.verifyMerklePath(txID, tx.MerklePath)
+		if err != nil {
+			return nil, err
+		}
+		return []Proof{proof}, nil
+	}
+
+	proofs := make([]Proof, 0, len(tx.Inputs))
+	for _, input := range tx.Inputs {
+		if input.SourceTransaction == nil {
+			proofs = append(proofs, Proof{TxID: input.SourceTXID.String(), Status: StatusMissingProof})
+			continue
+		}
+		ancestors, err := v.collectProofs(input.SourceTransaction, visited)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, ancestors...)
+	}
+	if len(proofs) == 0 {
+		proofs = append(proofs, Proof{TxID: txID, Status: StatusMissingProof})
+	}
+
+	return proofs, nil
+}
+
+func (v *Verifier) verifyMerklePath(txID string, path *trx.MerklePath) (Proof, error) {
+	proof := Proof{TxID: txID, BlockHeight: path.BlockHeight}
+
+	root, err := path.ComputeRootHex(&txID)
+	if err != nil {
+		proof.Status = StatusInvalidPath
+		return proof, nil
+	}
+	proof.MerkleRoot = root
+
+	stored, err := v.roots.MerkleRootAtHeight(int(path.BlockHeight))
+	switch {
+	case errors.Is(err, goclienterr.ErrMerkleRootNotFound):
+		proof.Status = StatusUnknownRoot
+	case err != nil:
+		return Proof{}, fmt.Errorf("failed to look up merkle root at height %d: %w", path.BlockHeight, err)
+	case stored == root:
+		proof.Status = StatusValid
+	default:
+		proof.Status = StatusInvalidPath
+	}
+
+	return proof, nil
+}
+
+// parseBEEF parses the BEEF, recovering from the panic raised by the SDK for a BEEF
+// referencing a transaction it doesn't contain.
+func parseBEEF(beefHex string) (tx *trx.Transaction, err error) {
+	beef, err := hex.DecodeString(beefHex)
+	if err != nil {
+		return nil, errors.Join(goclienterr.ErrInvalidBEEF, err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx, err = nil, fmt.Errorf("%w: %v", goclienterr.ErrInvalidBEEF, r)
+		}
+	}()
+
+	tx, err = trx.NewTransactionFromBEEF(beef)
+	if err != nil {
+		return nil, errors.Join(goclienterr.ErrInvalidBEEF, err)
+	}
+
+	return tx, nil
+}
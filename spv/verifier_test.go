@@ -0,0 +1,241 @@
+package spv_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitcoin-sv/go-sdk/chainhash"
+	"github.com/bitcoin-sv/go-sdk/script"
+	trx "github.com/bitcoin-sv/go-sdk/transaction"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
+	"github.com/bitcoin-sv/spv-wallet-go-client/spv"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/bitcoin-sv/spv-wallet/models/response"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	blockHeight = 850000
+	// otherRoot is a Merkle root of a different block.
+	otherRoot = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+)
+
+func TestVerifier_VerifyBEEF(t *testing.T) {
+	mined, unmined := givenTransactions(t)
+	minedRoot := merkleRoot(t, mined)
+
+	tests := map[string]struct {
+		tx             *trx.Transaction
+		storedRoots    []models.MerkleRoot
+		expectedStatus spv.Status
+		expectedHeight uint32
+	}{
+		"Mined transaction with synced root": {
+			tx:             mined,
+			storedRoots:    []models.MerkleRoot{{BlockHeight: blockHeight, MerkleRoot: minedRoot}},
+			expectedStatus: spv.StatusValid,
+			expectedHeight: blockHeight,
+		},
+		"Unmined transaction with proven ancestor": {
+			tx:             unmined,
+			storedRoots:    []models.MerkleRoot{{BlockHeight: blockHeight, MerkleRoot: minedRoot}},
+			expectedStatus: spv.StatusValid,
+		},
+		"Mined transaction with root not synced yet": {
+			tx:             mined,
+			storedRoots:    []models.MerkleRoot{{BlockHeight: blockHeight - 1, MerkleRoot: minedRoot}},
+			expectedStatus: spv.StatusUnknownRoot,
+			expectedHeight: blockHeight,
+		},
+		"Unmined transaction with ancestor proven by different root": {
+			tx:             unmined,
+			storedRoots:    []models.MerkleRoot{{BlockHeight: blockHeight, MerkleRoot: otherRoot}},
+			expectedStatus: spv.StatusInvalidPath,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			verifier := spv.NewVerifier(givenRepository(t, tc.storedRoots))
+			beef, err := tc.tx.BEEFHex()
+			require.NoError(t, err)
+
+			// when:
+			res, err := verifier.VerifyBEEF(beef)
+
+			// then:
+			require.NoError(t, err)
+			require.Equal(t, tc.tx.TxID().String(), res.TxID)
+			require.Equal(t, tc.expectedStatus, res.Status)
+			require.Equal(t, tc.expectedHeight, res.BlockHeight)
+			require.Len(t, res.Proofs, 1)
+			require.Equal(t, mined.TxID().String(), res.Proofs[0].TxID)
+			require.Equal(t, minedRoot, res.Proofs[0].MerkleRoot)
+		})
+	}
+
+	t.Run("Malformed BEEF", func(t *testing.T) {
+		// given:
+		verifier := spv.NewVerifier(givenRepository(t, nil))
+
+		// when:
+		res, err := verifier.VerifyBEEF(unmined.String())
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrInvalidBEEF)
+		require.Nil(t, res)
+	})
+}
+
+func TestVerifier_VerifyRawTransaction(t *testing.T) {
+	mined, unmined := givenTransactions(t)
+	repo := givenRepository(t, []models.MerkleRoot{{BlockHeight: blockHeight, MerkleRoot: merkleRoot(t, mined)}})
+
+	tests := map[string]struct {
+		txHex          string
+		merklePathHex  string
+		expectedStatus spv.Status
+		expectedErr    error
+	}{
+		"Raw transaction with Merkle path": {
+			txHex:          mined.String(),
+			merklePathHex:  mined.MerklePath.Hex(),
+			expectedStatus: spv.StatusValid,
+		},
+		"Raw transaction with Merkle path of another transaction": {
+			txHex:          unmined.String(),
+			merklePathHex:  mined.MerklePath.Hex(),
+			expectedStatus: spv.StatusInvalidPath,
+		},
+		"Raw transaction without Merkle path": {
+			txHex:          mined.String(),
+			expectedStatus: spv.StatusMissingProof,
+		},
+		"Malformed Merkle path": {
+			txHex:         mined.String(),
+			merklePathHex: "fe",
+			expectedErr:   goclienterr.ErrInvalidMerklePath,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			verifier := spv.NewVerifier(repo)
+
+			// when:
+			res, err := verifier.VerifyRawTransaction(tc.txHex, tc.merklePathHex)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.Nil(t, res)
+				return
+			}
+			require.Equal(t, tc.expectedStatus, res.Status)
+		})
+	}
+}
+
+func TestVerifier_VerifyTransaction(t *testing.T) {
+	mined, _ := givenTransactions(t)
+	beef, err := mined.BEEFHex()
+	require.NoError(t, err)
+	repo := givenRepository(t, []models.MerkleRoot{{BlockHeight: blockHeight, MerkleRoot: merkleRoot(t, mined)}})
+
+	tests := map[string]struct {
+		tx             *response.Transaction
+		merklePathHex  string
+		expectedStatus spv.Status
+		expectedErr    error
+	}{
+		"Record in BEEF format": {
+			tx:             &response.Transaction{ID: mined.TxID().String(), Hex: beef, BlockHeight: blockHeight},
+			expectedStatus: spv.StatusValid,
+		},
+		"Record with raw hex and Merkle path": {
+			tx:             &response.Transaction{ID: mined.TxID().String(), Hex: mined.String(), BlockHeight: blockHeight},
+			merklePathHex:  mined.MerklePath.Hex(),
+			expectedStatus: spv.StatusValid,
+		},
+		"Record reporting different block height": {
+			tx:             &response.Transaction{ID: mined.TxID().String(), Hex: beef, BlockHeight: blockHeight + 1},
+			expectedStatus: spv.StatusHeightMismatch,
+		},
+		"Record with hex of another transaction": {
+			tx:          &response.Transaction{ID: otherRoot, Hex: beef},
+			expectedErr: goclienterr.ErrTransactionIDMismatch,
+		},
+		"Nil record": {
+			expectedErr: goclienterr.ErrTransactionMissing,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			verifier := spv.NewVerifier(repo)
+
+			// when:
+			res, err := verifier.VerifyTransaction(tc.tx, tc.merklePathHex)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.Nil(t, res)
+				return
+			}
+			require.Equal(t, tc.expectedStatus, res.Status)
+		})
+	}
+}
+
+// givenTransactions returns a transaction mined at blockHeight in a block of two transactions
+// and an unmined transaction spending it.
+func givenTransactions(t *testing.T) (mined, unmined *trx.Transaction) {
+	t.Helper()
+
+	lockingScript, err := script.NewFromHex("76a9141d3a445763e1095ad6989fafbdf3ecad92b3a3fa88ac")
+	require.NoError(t, err)
+	fundingTxID, err := chainhash.NewHashFromHex(otherRoot)
+	require.NoError(t, err)
+
+	mined = trx.NewTransaction()
+	mined.AddInput(&trx.TransactionInput{SourceTXID: fundingTxID, UnlockingScript: &script.Script{}, SequenceNumber: trx.DefaultSequenceNumber})
+	mined.AddOutput(&trx.TransactionOutput{Satoshis: 10, LockingScript: lockingScript})
+
+	isTxID := true
+	sibling := chainhash.DoubleHashH([]byte("sibling transaction"))
+	mined.MerklePath = trx.NewMerklePath(blockHeight, [][]*trx.PathElement{{
+		{Offset: 0, Hash: mined.TxID(), Txid: &isTxID},
+		{Offset: 1, Hash: &sibling},
+	}})
+
+	unmined = trx.NewTransaction()
+	unmined.AddInput(&trx.TransactionInput{SourceTXID: mined.TxID(), SourceTransaction: mined, UnlockingScript: &script.Script{}, SequenceNumber: trx.DefaultSequenceNumber})
+	unmined.AddOutput(&trx.TransactionOutput{Satoshis: 9, LockingScript: lockingScript})
+
+	return mined, unmined
+}
+
+func merkleRoot(t *testing.T, tx *trx.Transaction) string {
+	t.Helper()
+
+	root, err := tx.MerklePath.ComputeRoot(tx.TxID())
+	require.NoError(t, err)
+
+	return root.String()
+}
+
+func givenRepository(t *testing.T, roots []models.MerkleRoot) merkleroots.Repository {
+	t.Helper()
+
+	repo, err := merkleroots.NewFileRepository(filepath.Join(t.TempDir(), "merkleroots.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	require.NoError(t, repo.SaveMerkleRoots(roots))
+
+	return repo
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/bitcoin-sv/spv-wallet-go-client/commands"
 	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/configs"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/errutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/pagination"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/accesskeys"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/contacts"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/api/v1/user/invitations"
@@ -22,7 +25,10 @@ import (
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/auth"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/constants"
 	"github.com/bitcoin-sv/spv-wallet-go-client/internal/restyutil"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/tracing"
+	localroots "github.com/bitcoin-sv/spv-wallet-go-client/merkleroots"
 	"github.com/bitcoin-sv/spv-wallet-go-client/queries"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
 	"github.com/bitcoin-sv/spv-wallet/models"
 	"github.com/bitcoin-sv/spv-wallet/models/filter"
 	"github.com/bitcoin-sv/spv-wallet/models/response"
@@ -39,6 +45,9 @@ import (
 //
 // UserAPI methods may return wrapped errors, including models.SPVError or
 // ErrUnrecognizedAPIResponse, depending on the behavior of the SPV Wallet API.
+// Errors caused by a non-2xx response wrap *errors.APIError, carrying the status code,
+// server error code and raw body; classify them with errors.IsNotFound, errors.IsUnauthorized,
+// errors.IsInsufficientFunds and errors.IsRetryable.
 type UserAPI struct {
 	xpubAPI         *xpubs.API
 	accessKeyAPI    *accesskeys.API
@@ -49,6 +58,7 @@ type UserAPI struct {
 	transactionsAPI *transactions.API
 	utxosAPI        *utxos.API
 	paymailsAPI     *paymails.API
+	tracer          *tracing.Tracer
 	totpAPI         *totp.API //only available when using xPriv
 }
 
@@ -59,21 +69,34 @@ type UserAPI struct {
 // provided using query options. The result is unmarshaled into a *queries.ContactsPage.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (u *UserAPI) Contacts(ctx context.Context, contactOpts ...queries.QueryOption[filter.ContactFilter]) (*queries.ContactsPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.Contacts", constants.UserContactsAPI)
+	defer span.End()
+
 	res, err := u.contactsAPI.Contacts(ctx, contactOpts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "retrieve contacts page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "retrieve contacts page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllContacts returns an iterator over all user contacts, walking through every page
+// returned by UserAPI.Contacts. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (u *UserAPI) AllContacts(ctx context.Context, opts ...queries.QueryOption[filter.ContactFilter]) queries.Iterator[response.Contact] {
+	return pagination.Iterate(ctx, u.Contacts, opts...)
+}
+
 // ContactWithPaymail retrieves a user contact by their paymail address.
 // The response is unmarshaled into a *response.Contact.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (u *UserAPI) ContactWithPaymail(ctx context.Context, paymail string) (*response.Contact, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.ContactWithPaymail", constants.UserContactsAPI)
+	defer span.End()
+
 	res, err := u.contactsAPI.ContactWithPaymail(ctx, paymail)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "retrieve contact with paymail", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "retrieve contact with paymail", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -83,9 +106,12 @@ func (u *UserAPI) ContactWithPaymail(ctx context.Context, paymail string) (*resp
 // The response is unmarshaled into a *response.Contact.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (u *UserAPI) UpsertContact(ctx context.Context, cmd commands.UpsertContact) (*response.Contact, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.UpsertContact", constants.UserContactsAPI)
+	defer span.End()
+
 	res, err := u.contactsAPI.UpsertContact(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "upsert contact", err).FormatPutErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserContactsAPI, "upsert contact", err).FormatPutErr())
 	}
 
 	return res, nil
@@ -95,9 +121,12 @@ func (u *UserAPI) UpsertContact(ctx context.Context, cmd commands.UpsertContact)
 // Returns an error if the API request fails or the response cannot be decoded.
 // A nil error indicates the deleting contact was successful.
 func (u *UserAPI) RemoveContact(ctx context.Context, paymail string) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.RemoveContact", constants.AdminContactsAPI)
+	defer span.End()
+
 	err := u.contactsAPI.RemoveContact(ctx, paymail)
 	if err != nil {
-		return errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "remove contact", err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "remove contact", err).FormatDeleteErr())
 	}
 
 	return nil
@@ -105,13 +134,16 @@ func (u *UserAPI) RemoveContact(ctx context.Context, paymail string) error {
 
 // ConfirmContact checks the TOTP code and if it's ok, confirms user's contact using the user contacts API.
 func (u *UserAPI) ConfirmContact(ctx context.Context, contact *models.Contact, passcode, requesterPaymail string, period, digits uint) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.ConfirmContact", constants.AdminContactsAPI)
+	defer span.End()
+
 	if err := u.ValidateTotpForContact(contact, passcode, requesterPaymail, period, digits); err != nil {
-		return fmt.Errorf("failed to validate TOTP for contact: %w", err)
+		return tracing.Error(span, fmt.Errorf("failed to validate TOTP for contact: %w", err))
 	}
 
 	err := u.contactsAPI.ConfirmContact(ctx, contact.Paymail)
 	if err != nil {
-		return errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "confirm contact", err).FormatPostErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "confirm contact", err).FormatPostErr())
 	}
 
 	return nil
@@ -120,9 +152,12 @@ func (u *UserAPI) ConfirmContact(ctx context.Context, contact *models.Contact, p
 // UnconfirmContact unconfirms a user contact with the given paymail via the user contacts API.
 // Returns an error if the API request fails or the response cannot be decoded. A nil error indicates the deleting confirmation was successful.
 func (u *UserAPI) UnconfirmContact(ctx context.Context, paymail string) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.UnconfirmContact", constants.AdminContactsAPI)
+	defer span.End()
+
 	err := u.contactsAPI.UnconfirmContact(ctx, paymail)
 	if err != nil {
-		return errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "unconfirm contact", err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminContactsAPI, "unconfirm contact", err).FormatDeleteErr())
 	}
 
 	return nil
@@ -131,9 +166,12 @@ func (u *UserAPI) UnconfirmContact(ctx context.Context, paymail string) error {
 // AcceptInvitation accepts a user contact with the given paymail via the user contacts API.
 // Returns an error if the API request fails or the response cannot be decoded. A nil error indicates the acceptation was successful.
 func (u *UserAPI) AcceptInvitation(ctx context.Context, paymail string) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.AcceptInvitation", constants.UserInvitationsAPI)
+	defer span.End()
+
 	err := u.invitationsAPI.AcceptInvitation(ctx, paymail)
 	if err != nil {
-		return errutil.NewHTTPErrorFormatter(constants.UserInvitationsAPI, "accept invitation", err).FormatPostErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserInvitationsAPI, "accept invitation", err).FormatPostErr())
 	}
 
 	return nil
@@ -143,9 +181,12 @@ func (u *UserAPI) AcceptInvitation(ctx context.Context, paymail string) error {
 // Returns an error if the API request fails or the response cannot be decoded.
 // A nil error indicates the rejection was successful.
 func (u *UserAPI) RejectInvitation(ctx context.Context, paymail string) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.RejectInvitation", constants.UserInvitationsAPI)
+	defer span.End()
+
 	err := u.invitationsAPI.RejectInvitation(ctx, paymail)
 	if err != nil {
-		return errutil.NewHTTPErrorFormatter(constants.UserInvitationsAPI, "reject invitation", err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserInvitationsAPI, "reject invitation", err).FormatDeleteErr())
 	}
 
 	return nil
@@ -155,9 +196,12 @@ func (u *UserAPI) RejectInvitation(ctx context.Context, paymail string) error {
 // The response is unmarshaled into a response.SharedConfig.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) SharedConfig(ctx context.Context) (*response.SharedConfig, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.SharedConfig", constants.UserSharedConfigAPI)
+	defer span.End()
+
 	res, err := u.configsAPI.SharedConfig(ctx)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserSharedConfigAPI, "retrieve shared configuration", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserSharedConfigAPI, "retrieve shared configuration", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -167,9 +211,12 @@ func (u *UserAPI) SharedConfig(ctx context.Context) (*response.SharedConfig, err
 // The response is expected to be unmarshaled into a *response.DraftTransaction struct.
 // If the request fails or the response cannot be decoded, an error is returned.
 func (u *UserAPI) DraftTransaction(ctx context.Context, cmd *commands.DraftTransaction) (*response.DraftTransaction, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.DraftTransaction", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.DraftTransaction(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "create a draft transaction", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "create a draft transaction", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -179,10 +226,13 @@ func (u *UserAPI) DraftTransaction(ctx context.Context, cmd *commands.DraftTrans
 // The response is unmarshaled into a *response.Transaction.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) RecordTransaction(ctx context.Context, cmd *commands.RecordTransaction) (*response.Transaction, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.RecordTransaction", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.RecordTransaction(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("record a transaction with reference ID: %s", cmd.ReferenceID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatPostErr())
 	}
 
 	return res, nil
@@ -192,10 +242,13 @@ func (u *UserAPI) RecordTransaction(ctx context.Context, cmd *commands.RecordTra
 // The response is expected to be unmarshaled into a *response.Transaction struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) UpdateTransactionMetadata(ctx context.Context, cmd *commands.UpdateTransactionMetadata) (*response.Transaction, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.UpdateTransactionMetadata", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.UpdateTransactionMetadata(ctx, cmd)
 	if err != nil {
 		msg := fmt.Sprintf("record a transaction with ID: %s", cmd.ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatPutErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatPutErr())
 	}
 
 	return res, nil
@@ -209,33 +262,55 @@ func (u *UserAPI) UpdateTransactionMetadata(ctx context.Context, cmd *commands.U
 // The response is expected to be to unmarshal into a *response.PageModel[response.Transaction] struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) Transactions(ctx context.Context, opts ...queries.QueryOption[filter.TransactionFilter]) (*queries.TransactionPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.Transactions", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.Transactions(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "retrieve transactions page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "retrieve transactions page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllTransactions returns an iterator over all transactions, walking through every page
+// returned by UserAPI.Transactions. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (u *UserAPI) AllTransactions(ctx context.Context, opts ...queries.QueryOption[filter.TransactionFilter]) queries.Iterator[response.Transaction] {
+	return pagination.Iterate(ctx, u.Transactions, opts...)
+}
+
 // Transaction retrieves a specific transaction by its ID via the user transactions API.
 // The response is expected to be unmarshaled into a *response.Transaction struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) Transaction(ctx context.Context, ID string) (*response.Transaction, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.Transaction", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.Transaction(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("retrieve a transaction with ID: %s", ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, msg, err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
 // FinalizeTransaction finalizes a draft transaction and returns its signed hex representation.
-// It uses the draft transaction details to construct, enrich, and sign the transaction
-// through the `transactionsigner.TransactionSignedHex` utility function.
+// Before signing, the draft is verified offline: change must go to destinations derived from the user's xPub
+// and the fee must not exceed the rate of the draft's fee unit. The outputs paid by the draft are only checked
+// against the configuration returned by the server along with it, so outputs changed by a compromised server
+// together with the configuration are not detected; use FinalizeTransactionFor to verify them against the
+// transaction config the draft was requested with.
+// The transaction is then signed through the configured TransactionSigner (the xPriv signer or a custom one).
 // The response is the signed transaction in hex format.
-// Returns an error if the transaction cannot be finalized.
+// Returns an error if the draft fails verification (errors.ErrDraft*) or cannot be finalized,
+// including errors.ErrNoTransactionSigner when the client was initialized with an xPub or an access key only.
 func (u *UserAPI) FinalizeTransaction(draft *response.DraftTransaction) (string, error) {
+	if draft == nil {
+		return "", fmt.Errorf("couldn't finalize transaction, %w", goclienterr.ErrDraftMissing)
+	}
+
 	res, err := u.transactionsAPI.FinalizeTransaction(draft)
 	if err != nil {
 		return "", fmt.Errorf("couldn't finalize transaction with ID: %s, %w", draft.ID, err)
@@ -244,15 +319,63 @@ func (u *UserAPI) FinalizeTransaction(draft *response.DraftTransaction) (string,
 	return res, nil
 }
 
+// FinalizeTransactionFor finalizes a draft transaction created with DraftTransaction for the requested
+// transaction config and returns its signed hex representation. Before signing, the draft is verified
+// offline like in VerifyDraftTransaction: it must pay exactly the requested outputs, the remaining outputs
+// must be change to destinations derived from the user's xPub and the fee must not exceed the rate of
+// the draft's fee unit.
+// The transaction is then signed through the configured TransactionSigner (the xPriv signer or a custom one).
+// Returns an error if the draft fails verification (errors.ErrDraft*), including errors.ErrDraftRequestMissing
+// when requested is nil, or cannot be finalized, including errors.ErrNoTransactionSigner when the client
+// was initialized with an xPub or an access key only.
+func (u *UserAPI) FinalizeTransactionFor(draft *response.DraftTransaction, requested *response.TransactionConfig) (string, error) {
+	if draft == nil {
+		return "", fmt.Errorf("couldn't finalize transaction, %w", goclienterr.ErrDraftMissing)
+	}
+
+	res, err := u.transactionsAPI.FinalizeTransactionFor(draft, requested)
+	if err != nil {
+		return "", fmt.Errorf("couldn't finalize transaction with ID: %s, %w", draft.ID, err)
+	}
+
+	return res, nil
+}
+
+// VerifyDraftTransaction checks offline that the draft transaction created by the server
+// pays exactly the outputs of the requested transaction config (amounts, addresses, scripts and OP_RETURN data),
+// that the remaining outputs are change to destinations derived from the user's xPub and that the fee
+// does not exceed the rate of the draft's fee unit. When requested is nil, the draft is verified against
+// the outputs declared in its own configuration, as in FinalizeTransaction, which doesn't detect outputs
+// changed by a compromised server together with the configuration.
+// It is meant for callers drafting transactions with DraftTransaction and signing them separately.
+// Returns one of the errors.ErrDraft* errors if the draft doesn't match the request, including
+// errors.ErrDraftChangeUnverified when the draft has change but the client was initialized with an access key only.
+func (u *UserAPI) VerifyDraftTransaction(draft *response.DraftTransaction, requested *response.TransactionConfig) error {
+	if draft == nil {
+		return fmt.Errorf("draft transaction failed verification, %w", goclienterr.ErrDraftMissing)
+	}
+
+	err := u.transactionsAPI.VerifyDraftTransaction(draft, requested)
+	if err != nil {
+		return fmt.Errorf("draft transaction with ID: %s failed verification, %w", draft.ID, err)
+	}
+
+	return nil
+}
+
 // SendToRecipients creates, finalizes, and broadcasts a transaction to multiple recipients.
 // This method handles the complete process of drafting, finalizing, and recording the transaction
-// using the recipient details provided in the command.
+// using the recipient details provided in the command. The draft returned by the server is verified
+// against the requested recipients before it is signed.
 // The response is unmarshalled into a *response.Transaction struct.
 // Returns an error if the transaction fails at any step, such as drafting, finalization or recording.
 func (u *UserAPI) SendToRecipients(ctx context.Context, cmd *commands.SendToRecipients) (*response.Transaction, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.SendToRecipients", constants.UserTransactionsAPI)
+	defer span.End()
+
 	res, err := u.transactionsAPI.SendToRecipients(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "send to recipients", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserTransactionsAPI, "send to recipients", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -262,9 +385,12 @@ func (u *UserAPI) SendToRecipients(ctx context.Context, cmd *commands.SendToReci
 // The response is unmarshaled into a *response.Xpub.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) XPub(ctx context.Context) (*response.Xpub, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.XPub", constants.UserXPubsAPI)
+	defer span.End()
+
 	res, err := u.xpubAPI.XPub(ctx)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserXPubsAPI, "retrieve xpub information", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserXPubsAPI, "retrieve xpub information", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -274,9 +400,12 @@ func (u *UserAPI) XPub(ctx context.Context) (*response.Xpub, error) {
 // The response is unmarshaled into a *response.Xpub.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) UpdateXPubMetadata(ctx context.Context, cmd *commands.UpdateXPubMetadata) (*response.Xpub, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.UpdateXPubMetadata", constants.UserXPubsAPI)
+	defer span.End()
+
 	res, err := u.xpubAPI.UpdateXPubMetadata(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserXPubsAPI, "update xpub metadata ", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserXPubsAPI, "update xpub metadata ", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -286,9 +415,12 @@ func (u *UserAPI) UpdateXPubMetadata(ctx context.Context, cmd *commands.UpdateXP
 // The response is unmarshaled into a *response.AccessKey.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) GenerateAccessKey(ctx context.Context, cmd *commands.GenerateAccessKey) (*response.AccessKey, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.GenerateAccessKey", constants.UserAccessKeyAPI)
+	defer span.End()
+
 	res, err := u.accessKeyAPI.GenerateAccessKey(ctx, cmd)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserAccessKeyAPI, "generate access key ", err).FormatPostErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserAccessKeyAPI, "generate access key ", err).FormatPostErr())
 	}
 
 	return res, nil
@@ -302,22 +434,35 @@ func (u *UserAPI) GenerateAccessKey(ctx context.Context, cmd *commands.GenerateA
 // The response is expected to unmarshal into a *queries.AccessKeyPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) AccessKeys(ctx context.Context, accessKeyOpts ...queries.QueryOption[filter.AccessKeyFilter]) (*queries.AccessKeyPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.AccessKeys", constants.AdminAccessKeyAPI)
+	defer span.End()
+
 	res, err := u.accessKeyAPI.AccessKeys(ctx, accessKeyOpts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, "retrieve access keys page ", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, "retrieve access keys page ", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllAccessKeys returns an iterator over all access keys, walking through every page
+// returned by UserAPI.AccessKeys. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (u *UserAPI) AllAccessKeys(ctx context.Context, opts ...queries.QueryOption[filter.AccessKeyFilter]) queries.Iterator[response.AccessKey] {
+	return pagination.Iterate(ctx, u.AccessKeys, opts...)
+}
+
 // AccessKey retrieves the access key associated with the specified ID via the user access keys API.
 // The response is expected to be unmarshaled into a *response.AccessKey struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) AccessKey(ctx context.Context, ID string) (*response.AccessKey, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.AccessKey", constants.UserAccessKeyAPI)
+	defer span.End()
+
 	res, err := u.accessKeyAPI.AccessKey(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("retrieve access key with ID: %s", ID)
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserAccessKeyAPI, msg, err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserAccessKeyAPI, msg, err).FormatGetErr())
 	}
 
 	return res, nil
@@ -327,10 +472,13 @@ func (u *UserAPI) AccessKey(ctx context.Context, ID string) (*response.AccessKey
 // If the request fails or the response cannot be processed, an error is returned.
 // A nil error indicates the revoking access key was successful.
 func (u *UserAPI) RevokeAccessKey(ctx context.Context, ID string) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.RevokeAccessKey", constants.AdminAccessKeyAPI)
+	defer span.End()
+
 	err := u.accessKeyAPI.RevokeAccessKey(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("revoke access key with ID: %s", ID)
-		return errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, msg, err).FormatDeleteErr()
+		return tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.AdminAccessKeyAPI, msg, err).FormatDeleteErr())
 	}
 
 	return nil
@@ -344,14 +492,24 @@ func (u *UserAPI) RevokeAccessKey(ctx context.Context, ID string) error {
 // The response is unmarshaled into a *queries.UtxosPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) UTXOs(ctx context.Context, opts ...queries.QueryOption[filter.UtxoFilter]) (*queries.UtxosPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.UTXOs", constants.UserUtxosAPI)
+	defer span.End()
+
 	res, err := u.utxosAPI.UTXOs(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserUtxosAPI, "retrieve UTXOs page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserUtxosAPI, "retrieve UTXOs page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllUTXOs returns an iterator over all UTXOs, walking through every page
+// returned by UserAPI.UTXOs. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (u *UserAPI) AllUTXOs(ctx context.Context, opts ...queries.QueryOption[filter.UtxoFilter]) queries.Iterator[response.Utxo] {
+	return pagination.Iterate(ctx, u.UTXOs, opts...)
+}
+
 // MerkleRoots retrieves a paginated list of Merkle roots via the user Merkle roots API.
 // The API response includes Merkle roots along with pagination details, such as the current
 // page number, sort order, and sorting field (sortBy).
@@ -363,9 +521,12 @@ func (u *UserAPI) UTXOs(ctx context.Context, opts ...queries.QueryOption[filter.
 // The response is unmarshaled into a *queries.MerkleRootPage struct.
 // Returns an error if the request fails or the response cannot be decoded.
 func (u *UserAPI) MerkleRoots(ctx context.Context, opts ...queries.MerkleRootsQueryOption) (*queries.MerkleRootPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.MerkleRoots", constants.UserMerkleRootAPI)
+	defer span.End()
+
 	res, err := u.merkleRootsAPI.MerkleRoots(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserMerkleRootAPI, "retrieve Merkle root page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserMerkleRootAPI, "retrieve Merkle root page", err).FormatGetErr())
 	}
 
 	return res, nil
@@ -374,15 +535,30 @@ func (u *UserAPI) MerkleRoots(ctx context.Context, opts ...queries.MerkleRootsQu
 // SyncMerkleRoots synchronizes Merkle roots known to the SPV Wallet with the client database.
 // This method sends a series of HTTP GET requests to the "/merkleroots" endpoint, fetching
 // Merkle roots and storing them in the client database. The process continues until all
+// Merkle roots are synchronized. Ready to use file and database/sql repositories are provided
+// by the merkleroots package.
 func (u *UserAPI) SyncMerkleRoots(ctx context.Context, repo merkleroots.MerkleRootsRepository) error {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.SyncMerkleRoots", constants.UserMerkleRootAPI)
+	defer span.End()
+
 	err := u.merkleRootsAPI.SyncMerkleRoots(ctx, repo)
 	if err != nil {
-		return fmt.Errorf("failed to sync Merkle roots: %w", err)
+		return tracing.Error(span, fmt.Errorf("failed to sync Merkle roots: %w", err))
 	}
 
 	return nil
 }
 
+// StartMerkleRootsSync keeps the repository in sync with the Merkle roots known to the SPV Wallet
+// in the background, polling every interval until the context is canceled or the returned Syncer is stopped.
+// Unlike SyncMerkleRoots, the most recent stored roots are compared with the SPV Wallet on every poll,
+// and the ones orphaned by a chain reorganization are removed from the repository.
+// Callbacks for new roots, reorganizations and failures can be registered with the options.
+// A non-positive interval is replaced with merkleroots.DefaultSyncInterval.
+func (u *UserAPI) StartMerkleRootsSync(ctx context.Context, repo localroots.ReorgRepository, interval time.Duration, opts ...localroots.SyncerOption) *localroots.Syncer {
+	return localroots.StartSyncer(ctx, u, repo, interval, opts...)
+}
+
 // GenerateTotpForContact generates a TOTP code for the specified contact.
 func (u *UserAPI) GenerateTotpForContact(contact *models.Contact, period, digits uint) (string, error) {
 	if u.totpAPI == nil {
@@ -420,14 +596,24 @@ func (u *UserAPI) ValidateTotpForContact(contact *models.Contact, passcode, requ
 // The API response is unmarshaled into a *queries.PaymailAddressPage struct.
 // Returns an error if the API request fails or the response cannot be decoded.
 func (u *UserAPI) Paymails(ctx context.Context, opts ...queries.QueryOption[filter.PaymailFilter]) (*queries.PaymailsPage, error) {
+	ctx, span := u.tracer.Start(ctx, "UserAPI.Paymails", constants.UserPaymailAPI)
+	defer span.End()
+
 	res, err := u.paymailsAPI.Paymails(ctx, opts...)
 	if err != nil {
-		return nil, errutil.NewHTTPErrorFormatter(constants.UserPaymailAPI, "retrieve paymail addresses page", err).FormatGetErr()
+		return nil, tracing.Error(span, errutil.NewHTTPErrorFormatter(constants.UserPaymailAPI, "retrieve paymail addresses page", err).FormatGetErr())
 	}
 
 	return res, nil
 }
 
+// AllPaymails returns an iterator over all paymail addresses, walking through every page
+// returned by UserAPI.Paymails. The query options apply to every page request;
+// use queries.QueryWithPrefetch to fetch the next page concurrently.
+func (u *UserAPI) AllPaymails(ctx context.Context, opts ...queries.QueryOption[filter.PaymailFilter]) queries.Iterator[response.PaymailAddress] {
+	return pagination.Iterate(ctx, u.Paymails, opts...)
+}
+
 // NewUserAPIWithXPub initializes a new UserAPI instance using an extended public key (xPub).
 // This function configures the API client with the provided configuration and uses the xPub key for authentication.
 // If any configuration or initialization step fails, an appropriate error is returned.
@@ -440,7 +626,9 @@ func NewUserAPIWithXPub(cfg config.Config, xPub string) (*UserAPI, error) {
 		return nil, fmt.Errorf("failed to intialized xPub authenticator: %w", err)
 	}
 
-	return initUserAPI(cfg, authenticator)
+	return newUserAPI(cfg, authenticator, func(url *url.URL, httpClient *resty.Client) (*transactions.API, error) {
+		return transactions.NewAPIWithXPub(url, httpClient, xPub)
+	})
 }
 
 // NewUserAPIWithXPriv initializes a new UserAPI instance using an extended private key (xPriv).
@@ -476,53 +664,137 @@ func NewUserAPIWithAccessKey(cfg config.Config, accessKey string) (*UserAPI, err
 	return initUserAPI(cfg, authenticator)
 }
 
-type authenticator interface {
-	Authenticate(r *resty.Request) error
+// NewUserAPIFromKeystore initializes a new UserAPI instance using the credentials stored under the name
+// in the encrypted keystore file at the path, decrypted with the password (see walletkeys.Keystore).
+// The xPriv of the entry is used if present, as in NewUserAPIWithXPriv, then the xPriv derived from its
+// mnemonic phrase with an empty BIP39 passphrase, and its access key otherwise, as in NewUserAPIWithAccessKey.
+// If the keystore can't be opened or the entry has no credentials, an appropriate error is returned.
+//
+// Note: Requests made with this instance will be securely signed.
+func NewUserAPIFromKeystore(cfg config.Config, path, name, password string) (*UserAPI, error) {
+	entry, err := keystoreEntry(path, name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	xPriv, err := keystoreEntryXPriv(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case xPriv != "":
+		return NewUserAPIWithXPriv(cfg, xPriv)
+	case entry.AccessKey != "":
+		return NewUserAPIWithAccessKey(cfg, entry.AccessKey)
+	default:
+		return nil, fmt.Errorf("%w: %q has neither an xPriv, a mnemonic phrase nor an access key", goclienterr.ErrKeystoreEntryNoCredentials, name)
+	}
 }
 
-func initUserAPIWithXPriv(cfg config.Config, xPriv string, auth authenticator) (*UserAPI, error) {
-	url, err := url.Parse(cfg.Addr)
+// keystoreEntry decrypts the entry with the name from the keystore file at the path.
+func keystoreEntry(path, name, password string) (*walletkeys.KeystoreEntry, error) {
+	ks, err := walletkeys.OpenKeystore(path, password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse addr to url.URL: %w", err)
+		return nil, fmt.Errorf("failed to open keystore: %w", err)
 	}
 
-	httpClient := restyutil.NewHTTPClient(cfg, auth)
-	transactionsAPI, err := transactions.NewAPIWithXPriv(url, httpClient, xPriv)
+	entry, err := ks.Get(name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transactionsAPI: %w", err)
+		return nil, fmt.Errorf("failed to read keystore entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// keystoreEntryXPriv returns the xPriv of the keystore entry, derived from its mnemonic phrase with
+// an empty BIP39 passphrase when the entry has none, or an empty string if the entry has neither.
+func keystoreEntryXPriv(entry *walletkeys.KeystoreEntry) (string, error) {
+	if entry.XPriv != "" || entry.Mnemonic == "" {
+		return entry.XPriv, nil
+	}
+
+	xPriv, err := walletkeys.XPrivFromMnemonic(entry.Mnemonic)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive xPriv from the keystore entry mnemonic phrase: %w", err)
 	}
 
+	return xPriv.String(), nil
+}
+
+// TransactionSigner signs draft transactions created by the SPV Wallet API.
+// It allows keeping the user's xPriv outside the client, e.g. in a separate signing service or an HSM.
+//
+// Implementations receive the complete draft transaction. Each input in draft.Configuration.Inputs
+// carries its Destination, whose Chain, Num and optional PaymailExternalDerivationNum identify the
+// key derived from the user's xPriv (m/chain/num[/paymailExternalDerivationNum]) that unlocks the input.
+// TransactionSignedHex must return the fully signed transaction in hex format.
+type TransactionSigner interface {
+	TransactionSignedHex(draft *response.DraftTransaction) (string, error)
+}
+
+// NewUserAPIWithSigner initializes a new UserAPI instance using an access key for authentication
+// and the provided TransactionSigner for finalizing draft transactions.
+// This function is intended for setups where the xPriv is not available to the client,
+// e.g. when transactions are signed by an external signing service. The user's xPub is required
+// to verify that the change of the draft transactions goes back to the user before they are signed.
+// If any step fails, an appropriate error is returned.
+//
+// Note: Requests made with this instance will be securely signed.
+func NewUserAPIWithSigner(cfg config.Config, accessKey, xPub string, signer TransactionSigner) (*UserAPI, error) {
+	if signer == nil {
+		return nil, goclienterr.ErrNoTransactionSigner
+	}
+
+	authenticator, err := auth.NewAccessKeyAuthenticator(accessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to intialized access key authenticator: %w", err)
+	}
+
+	return newUserAPI(cfg, authenticator, func(url *url.URL, httpClient *resty.Client) (*transactions.API, error) {
+		return transactions.NewAPIWithSigner(url, httpClient, xPub, signer)
+	})
+}
+
+type authenticator interface {
+	Authenticate(r *resty.Request) error
+}
+
+type transactionsAPIFactory func(url *url.URL, httpClient *resty.Client) (*transactions.API, error)
+
+func initUserAPIWithXPriv(cfg config.Config, xPriv string, auth authenticator) (*UserAPI, error) {
 	totpAPI, err := totp.NewAPI(xPriv)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create totpAPI: %w", err)
 	}
 
-	return &UserAPI{
-		merkleRootsAPI:  merkleroots.NewAPI(url, httpClient),
-		configsAPI:      configs.NewAPI(url, httpClient),
-		transactionsAPI: transactionsAPI,
-		utxosAPI:        utxos.NewAPI(url, httpClient),
-		accessKeyAPI:    accesskeys.NewAPI(url, httpClient),
-		xpubAPI:         xpubs.NewAPI(url, httpClient),
-		contactsAPI:     contacts.NewAPI(url, httpClient),
-		invitationsAPI:  invitations.NewAPI(url, httpClient),
-		paymailsAPI:     paymails.NewAPI(url, httpClient),
-		totpAPI:         totpAPI,
-	}, nil
+	userAPI, err := newUserAPI(cfg, auth, func(url *url.URL, httpClient *resty.Client) (*transactions.API, error) {
+		return transactions.NewAPIWithXPriv(url, httpClient, xPriv)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userAPI.totpAPI = totpAPI
+	return userAPI, nil
 }
 
 func initUserAPI(cfg config.Config, auth authenticator) (*UserAPI, error) {
-	url, err := url.Parse(cfg.Addr)
+	return newUserAPI(cfg, auth, transactions.NewAPI)
+}
+
+func newUserAPI(cfg config.Config, auth authenticator, newTransactionsAPI transactionsAPIFactory) (*UserAPI, error) {
+	url, err := cfg.BaseURL()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse addr to url.URL: %w", err)
 	}
 
-	httpClient := restyutil.NewHTTPClient(cfg, auth)
-	if httpClient == nil {
-		return nil, fmt.Errorf("failed to initialize HTTP client - nil value")
+	httpClient, err := restyutil.NewHTTPClient(cfg, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize HTTP client: %w", err)
 	}
 
-	transactionsAPI, err := transactions.NewAPI(url, httpClient)
+	transactionsAPI, err := newTransactionsAPI(url, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transactionsAPI: %w", err)
 	}
@@ -537,5 +809,6 @@ func initUserAPI(cfg config.Config, auth authenticator) (*UserAPI, error) {
 		contactsAPI:     contacts.NewAPI(url, httpClient),
 		invitationsAPI:  invitations.NewAPI(url, httpClient),
 		paymailsAPI:     paymails.NewAPI(url, httpClient),
+		tracer:          tracing.NewTracer(cfg.TracerProvider),
 	}, nil
 }
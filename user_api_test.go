@@ -0,0 +1,119 @@
+package spvwallet_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	spvwallet "github.com/bitcoin-sv/spv-wallet-go-client"
+	"github.com/bitcoin-sv/spv-wallet-go-client/config"
+	"github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/internal/testutils"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/bitcoin-sv/spv-wallet/models"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+const keystoreMnemonic = "absorb corn ostrich order sing boost just harvest enable make detail future desert bus adult"
+
+func TestNewUserAPI_InvalidTransport(t *testing.T) {
+	// given:
+	cfg := config.Config{
+		Addr:      "unix:///var/run/spv-wallet.sock",
+		Transport: config.RoundTripFunc(http.DefaultTransport.RoundTrip),
+	}
+
+	// when:
+	wallet, err := spvwallet.NewUserAPIWithXPub(cfg, testutils.UserXPub)
+
+	// then:
+	require.ErrorIs(t, err, errors.ErrConfigValidationInvalidTransport)
+	require.Nil(t, wallet)
+}
+
+func TestNewUserAPIFromKeystore(t *testing.T) {
+	mnemonicXPriv, err := walletkeys.XPrivFromMnemonic(keystoreMnemonic)
+	require.NoError(t, err)
+	mnemonicXPub, err := walletkeys.XPubFromXPriv(mnemonicXPriv.String())
+	require.NoError(t, err)
+
+	path := testutils.GivenKeystore(t, map[string]walletkeys.KeystoreEntry{
+		"xpriv":      {XPriv: testutils.UserXPriv},
+		"access-key": {AccessKey: testutils.UserPrivAccessKey},
+		"mnemonic":   {Mnemonic: keystoreMnemonic},
+		"empty":      {},
+	})
+
+	tests := map[string]struct {
+		name           string
+		password       string
+		expectedHeader string
+		expectedXPub   string
+		expectedErr    error
+	}{
+		"Entry with xPriv": {
+			name:           "xpriv",
+			password:       testutils.KeystorePassword,
+			expectedHeader: models.AuthHeader,
+			expectedXPub:   testutils.UserXPub,
+		},
+		"Entry with mnemonic": {
+			name:           "mnemonic",
+			password:       testutils.KeystorePassword,
+			expectedHeader: models.AuthHeader,
+			expectedXPub:   mnemonicXPub,
+		},
+		"Entry with access key": {
+			name:           "access-key",
+			password:       testutils.KeystorePassword,
+			expectedHeader: models.AuthAccessKey,
+		},
+		"Entry without credentials": {
+			name:        "empty",
+			password:    testutils.KeystorePassword,
+			expectedErr: errors.ErrKeystoreEntryNoCredentials,
+		},
+		"Missing entry": {
+			name:        "alice",
+			password:    testutils.KeystorePassword,
+			expectedErr: errors.ErrKeystoreEntryNotFound,
+		},
+		"Wrong password": {
+			name:        "xpriv",
+			password:    "wrong password",
+			expectedErr: errors.ErrKeystorePassword,
+		},
+	}
+
+	url := testutils.FullAPIURL(t, "/api/v1/users/current")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given:
+			transport := httpmock.NewMockTransport()
+			var header http.Header
+			transport.RegisterResponder(http.MethodGet, url, func(req *http.Request) (*http.Response, error) {
+				header = req.Header
+				return testutils.NewStringResponderStatusOK("{}")(req)
+			})
+			cfg := config.Config{Addr: testutils.TestAPIAddr, Transport: transport}
+
+			// when:
+			wallet, err := spvwallet.NewUserAPIFromKeystore(cfg, path, tc.name, tc.password)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.Nil(t, wallet)
+				return
+			}
+			_, err = wallet.XPub(context.Background())
+			require.NoError(t, err)
+			require.NotEmpty(t, header.Get(tc.expectedHeader))
+			require.NotEmpty(t, header.Get(models.AuthSignature))
+			if tc.expectedXPub != "" {
+				require.Equal(t, tc.expectedXPub, header.Get(models.AuthHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package walletkeys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreKeyLen  = 32 // AES-256
+	keystoreSaltLen = 32
+
+	// keystoreCheck is the additional data of the ciphertext verifying the password of a keystore without entries.
+	keystoreCheck = "spv-wallet-keystore"
+)
+
+// Scrypt parameters of the new keystores, as recommended for interactive logins.
+// The parameters are stored in the keystore file, so they can be raised without breaking the existing keystores.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Upper bounds of the scrypt parameters read from a keystore file, so that a crafted file can't make
+// OpenKeystore allocate more than 1 GiB (128 * N * r bytes) or spin the CPU for minutes.
+const (
+	maxScryptN = 1 << 20
+	maxScryptR = 8
+	maxScryptP = 16
+)
+
+// KeystoreEntry holds the secrets stored under a name in a Keystore. Any of them may be empty.
+type KeystoreEntry struct {
+	XPriv     string    `json:"xPriv,omitempty"`     // HD extended private key.
+	Mnemonic  string    `json:"mnemonic,omitempty"`  // Mnemonic phrase the xPriv was generated from.
+	AccessKey string    `json:"accessKey,omitempty"` // Hex encoded private key of an access key.
+	CreatedAt time.Time `json:"createdAt"`           // Time the entry was stored, set by Keystore.Put.
+}
+
+// Keystore is a file of named entries encrypted with a key derived from a password using scrypt.
+// Every entry is encrypted separately with AES-256-GCM, authenticated along with its name.
+// The file is rewritten atomically with 0600 permissions on every change.
+// Keystore is safe for concurrent use, but not for use by multiple processes.
+type Keystore struct {
+	path string
+
+	mu   sync.Mutex
+	key  []byte
+	file keystoreFile
+}
+
+type keystoreFile struct {
+	Version int               `json:"version"`
+	KDF     keystoreKDFParams `json:"kdf"`
+	Check   sealed            `json:"check"`
+	Entries map[string]sealed `json:"entries"`
+}
+
+type keystoreKDFParams struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// valid reports whether N is a power of two greater than 1 and the parameters don't exceed their upper bounds.
+func (p keystoreKDFParams) valid() bool {
+	return p.N > 1 && p.N <= maxScryptN && p.N&(p.N-1) == 0 &&
+		p.R >= 1 && p.R <= maxScryptR &&
+		p.P >= 1 && p.P <= maxScryptP
+}
+
+// sealed is an AES-GCM ciphertext along with its nonce.
+type sealed struct {
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// CreateKeystore creates an empty keystore file at the path, encrypted with the password.
+// It returns ErrKeystoreExists if the file already exists.
+func CreateKeystore(path, password string) (*Keystore, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%w: %s", goclienterr.ErrKeystoreExists, path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check keystore file: %w", err)
+	}
+
+	ks := &Keystore{path: path}
+	if err := ks.rekey(password); err != nil {
+		return nil, err
+	}
+	ks.file.Entries = map[string]sealed{}
+	if err := ks.save(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// OpenKeystore opens the keystore file at the path and verifies the password.
+// It returns ErrKeystorePassword if the keystore can't be decrypted with the password.
+func OpenKeystore(path, password string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	ks := &Keystore{path: path}
+	if err := json.Unmarshal(data, &ks.file); err != nil {
+		return nil, fmt.Errorf("%w: %w", goclienterr.ErrKeystoreFormat, err)
+	}
+	params := ks.file.KDF
+	if ks.file.Version != keystoreVersion || params.Name != keystoreKDF {
+		return nil, fmt.Errorf("%w: unsupported version %d with %q key derivation", goclienterr.ErrKeystoreFormat, ks.file.Version, params.Name)
+	}
+	if !params.valid() {
+		return nil, fmt.Errorf("%w: scrypt parameters N=%d, r=%d, p=%d out of bounds", goclienterr.ErrKeystoreFormat, params.N, params.R, params.P)
+	}
+	if ks.file.Entries == nil {
+		ks.file.Entries = map[string]sealed{}
+	}
+
+	if ks.key, err = scrypt.Key([]byte(password), params.Salt, params.N, params.R, params.P, keystoreKeyLen); err != nil {
+		return nil, fmt.Errorf("%w: %w", goclienterr.ErrKeystoreFormat, err)
+	}
+	if _, err := ks.open(ks.file.Check, keystoreCheck); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Path returns the path of the keystore file.
+func (ks *Keystore) Path() string { return ks.path }
+
+// Names returns the names of the entries, sorted alphabetically.
+func (ks *Keystore) Names() []string {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	names := make([]string, 0, len(ks.file.Entries))
+	for name := range ks.file.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get decrypts the entry with the name. It returns ErrKeystoreEntryNotFound if there is no such entry.
+func (ks *Keystore) Get(name string) (*KeystoreEntry, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	s, ok := ks.file.Entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", goclienterr.ErrKeystoreEntryNotFound, name)
+	}
+
+	plaintext, err := ks.open(s, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry KeystoreEntry
+	if err := json.Unmarshal(plaintext, &entry); err != nil {
+		return nil, fmt.Errorf("%w: %w", goclienterr.ErrKeystoreFormat, err)
+	}
+
+	return &entry, nil
+}
+
+// Put encrypts the entry under the name, replacing the existing one, and saves the keystore file.
+func (ks *Keystore) Put(name string, entry KeystoreEntry) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty entry name", goclienterr.ErrKeystoreFormat)
+	}
+
+	entry.CreatedAt = time.Now().UTC()
+	plaintext, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode keystore entry: %w", err)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	s, err := ks.seal(plaintext, name)
+	if err != nil {
+		return err
+	}
+
+	previous, replaced := ks.file.Entries[name]
+	ks.file.Entries[name] = s
+	if err := ks.save(); err != nil {
+		if replaced {
+			ks.file.Entries[name] = previous
+		} else {
+			delete(ks.file.Entries, name)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the entry with the name and saves the keystore file.
+// It returns ErrKeystoreEntryNotFound if there is no such entry.
+func (ks *Keystore) Delete(name string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	s, ok := ks.file.Entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", goclienterr.ErrKeystoreEntryNotFound, name)
+	}
+
+	delete(ks.file.Entries, name)
+	if err := ks.save(); err != nil {
+		ks.file.Entries[name] = s
+		return err
+	}
+
+	return nil
+}
+
+// ChangePassword re-encrypts all the entries with a key derived from the new password and saves the keystore file.
+// The keystore is left unchanged if any entry can't be decrypted or the file can't be saved.
+func (ks *Keystore) ChangePassword(password string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	plaintexts := make(map[string][]byte, len(ks.file.Entries))
+	for name, s := range ks.file.Entries {
+		plaintext, err := ks.open(s, name)
+		if err != nil {
+			return err
+		}
+		plaintexts[name] = plaintext
+	}
+
+	previousKey, previousFile := ks.key, ks.file
+	restore := func() { ks.key, ks.file = previousKey, previousFile }
+
+	if err := ks.rekey(password); err != nil {
+		restore()
+		return err
+	}
+	ks.file.Entries = make(map[string]sealed, len(plaintexts))
+	for name, plaintext := range plaintexts {
+		s, err := ks.seal(plaintext, name)
+		if err != nil {
+			restore()
+			return err
+		}
+		ks.file.Entries[name] = s
+	}
+
+	if err := ks.save(); err != nil {
+		restore()
+		return err
+	}
+
+	return nil
+}
+
+// rekey derives a new key from the password with a fresh salt and seals the password check with it.
+func (ks *Keystore) rekey(password string) error {
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate keystore salt: %w", err)
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, keystoreKeyLen)
+	if err != nil {
+		return fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+
+	ks.key = key
+	ks.file.Version = keystoreVersion
+	ks.file.KDF = keystoreKDFParams{Name: keystoreKDF, Salt: salt, N: scryptN, R: scryptR, P: scryptP}
+	ks.file.Check, err = ks.seal(nil, keystoreCheck)
+	return err
+}
+
+func (ks *Keystore) seal(plaintext []byte, additionalData string) (sealed, error) {
+	aead, err := ks.aead()
+	if err != nil {
+		return sealed{}, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return sealed{}, fmt.Errorf("failed to generate keystore nonce: %w", err)
+	}
+
+	return sealed{Nonce: nonce, Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(additionalData))}, nil
+}
+
+func (ks *Keystore) open(s sealed, additionalData string) ([]byte, error) {
+	aead, err := ks.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce size", goclienterr.ErrKeystoreFormat)
+	}
+
+	plaintext, err := aead.Open(nil, s.Nonce, s.Ciphertext, []byte(additionalData))
+	if err != nil {
+		return nil, goclienterr.ErrKeystorePassword
+	}
+
+	return plaintext, nil
+}
+
+func (ks *Keystore) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(ks.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	return aead, nil
+}
+
+// save writes the keystore to a temporary file renamed over the keystore file,
+// so the keystore is never left partially written.
+func (ks *Keystore) save() error {
+	data, err := json.MarshalIndent(ks.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode keystore: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ks.path), filepath.Base(ks.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create keystore file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set keystore file permissions: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), ks.path); err != nil {
+		return fmt.Errorf("failed to replace keystore file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package walletkeys_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	keystorePassword = "correct horse battery staple"
+	keystoreXPriv    = "xprv9s21ZrQH143K3Lh4wdicqvYNMcdh49rMLqDvQoyys8L6f5tfE2WkQN7ZVE2awBrfVWNSJ8pPd4QLLr94Nur85Dvj8kD8RoZghBuNTpvL8si"
+	keystoreMnemonic = "absorb corn ostrich order sing boost just harvest enable make detail future desert bus adult"
+)
+
+func TestKeystore_PutAndGet(t *testing.T) {
+	// given:
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := walletkeys.CreateKeystore(path, keystorePassword)
+	require.NoError(t, err)
+
+	// when:
+	require.NoError(t, ks.Put("alice", walletkeys.KeystoreEntry{XPriv: keystoreXPriv, Mnemonic: keystoreMnemonic}))
+	require.NoError(t, ks.Put("bob", walletkeys.KeystoreEntry{AccessKey: "3fd870d6bf1725f04084cf31209c04be5bd9bed001a390ad3bc632a55a3ee078"}))
+	reopened, err := walletkeys.OpenKeystore(path, keystorePassword)
+
+	// then:
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, reopened.Names())
+	entry, err := reopened.Get("alice")
+	require.NoError(t, err)
+	require.Equal(t, keystoreXPriv, entry.XPriv)
+	require.Equal(t, keystoreMnemonic, entry.Mnemonic)
+	require.False(t, entry.CreatedAt.IsZero())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(data, []byte(keystoreXPriv)))
+	require.False(t, bytes.Contains(data, []byte("absorb")))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestKeystore_ChangePassword(t *testing.T) {
+	// given:
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := walletkeys.CreateKeystore(path, keystorePassword)
+	require.NoError(t, err)
+	require.NoError(t, ks.Put("alice", walletkeys.KeystoreEntry{XPriv: keystoreXPriv, Mnemonic: keystoreMnemonic}))
+
+	// when:
+	err = ks.ChangePassword("new password")
+
+	// then:
+	require.NoError(t, err)
+	_, err = walletkeys.OpenKeystore(path, keystorePassword)
+	require.ErrorIs(t, err, goclienterr.ErrKeystorePassword)
+
+	reopened, err := walletkeys.OpenKeystore(path, "new password")
+	require.NoError(t, err)
+	entry, err := reopened.Get("alice")
+	require.NoError(t, err)
+	require.Equal(t, keystoreXPriv, entry.XPriv)
+	require.Equal(t, keystoreMnemonic, entry.Mnemonic)
+
+	require.NoError(t, ks.Put("bob", walletkeys.KeystoreEntry{XPriv: keystoreXPriv}))
+	reopened, err = walletkeys.OpenKeystore(path, "new password")
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, reopened.Names())
+}
+
+func TestKeystore_Errors(t *testing.T) {
+	t.Run("Wrong password", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		_, err := walletkeys.CreateKeystore(path, keystorePassword)
+		require.NoError(t, err)
+
+		// when:
+		_, err = walletkeys.OpenKeystore(path, "wrong password")
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrKeystorePassword)
+	})
+
+	t.Run("Existing keystore", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		_, err := walletkeys.CreateKeystore(path, keystorePassword)
+		require.NoError(t, err)
+
+		// when:
+		_, err = walletkeys.CreateKeystore(path, keystorePassword)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrKeystoreExists)
+	})
+
+	t.Run("Malformed keystore", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version":2}`), 0o600))
+
+		// when:
+		_, err := walletkeys.OpenKeystore(path, keystorePassword)
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrKeystoreFormat)
+	})
+
+	t.Run("Scrypt parameters out of bounds", func(t *testing.T) {
+		tests := map[string]struct {
+			n, r, p int
+		}{
+			"N above the cap":      {n: 1 << 31, r: 8, p: 1},
+			"N not a power of two": {n: 32767, r: 8, p: 1},
+			"r above the cap":      {n: 1 << 15, r: 1024, p: 1},
+			"p above the cap":      {n: 1 << 15, r: 8, p: 1024},
+			"Zero r":               {n: 1 << 15, p: 1},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				// given:
+				path := filepath.Join(t.TempDir(), "keystore.json")
+				_, err := walletkeys.CreateKeystore(path, keystorePassword)
+				require.NoError(t, err)
+
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				var file map[string]any
+				require.NoError(t, json.Unmarshal(data, &file))
+				kdf := file["kdf"].(map[string]any)
+				kdf["n"], kdf["r"], kdf["p"] = tc.n, tc.r, tc.p
+				data, err = json.Marshal(file)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, data, 0o600))
+
+				// when:
+				_, err = walletkeys.OpenKeystore(path, keystorePassword)
+
+				// then:
+				require.ErrorIs(t, err, goclienterr.ErrKeystoreFormat)
+			})
+		}
+	})
+
+	t.Run("Missing and deleted entries", func(t *testing.T) {
+		// given:
+		ks, err := walletkeys.CreateKeystore(filepath.Join(t.TempDir(), "keystore.json"), keystorePassword)
+		require.NoError(t, err)
+		require.NoError(t, ks.Put("alice", walletkeys.KeystoreEntry{XPriv: keystoreXPriv}))
+
+		// when:
+		require.NoError(t, ks.Delete("alice"))
+
+		// then:
+		_, err = ks.Get("alice")
+		require.ErrorIs(t, err, goclienterr.ErrKeystoreEntryNotFound)
+		require.ErrorIs(t, ks.Delete("alice"), goclienterr.ErrKeystoreEntryNotFound)
+	})
+
+	t.Run("Entry moved under another name", func(t *testing.T) {
+		// given:
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		ks, err := walletkeys.CreateKeystore(path, keystorePassword)
+		require.NoError(t, err)
+		require.NoError(t, ks.Put("alice", walletkeys.KeystoreEntry{XPriv: keystoreXPriv}))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte(`"alice"`), []byte(`"mallory"`), 1), 0o600))
+
+		// when:
+		reopened, err := walletkeys.OpenKeystore(path, keystorePassword)
+		require.NoError(t, err)
+		_, err = reopened.Get("mallory")
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrKeystorePassword)
+	})
+}
@@ -0,0 +1,97 @@
+package walletkeys
+
+import (
+	"fmt"
+	"strings"
+
+	chaincfg "github.com/bitcoin-sv/go-sdk/transaction/chaincfg"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+)
+
+// Network identifies the network the HD keys are generated for. It determines the version bytes
+// of the serialized keys: xprv and xpub on the main network, tprv and tpub on the test networks.
+type Network string
+
+// Networks supported by the key generation functions.
+const (
+	MainNet Network = "mainnet"
+	TestNet Network = "testnet"
+	RegTest Network = "regtest" // Serialized like TestNet, with tprv and tpub keys.
+)
+
+// ParseNetwork returns the Network with the name, case-insensitively.
+// It returns an error wrapping ErrUnsupportedNetwork if the name is unknown.
+func ParseNetwork(name string) (Network, error) {
+	network := Network(strings.ToLower(strings.TrimSpace(name)))
+	if _, err := network.params(); err != nil {
+		return "", err
+	}
+
+	return network, nil
+}
+
+// String returns the name of the network.
+func (n Network) String() string { return string(n) }
+
+func (n Network) params() (*chaincfg.Params, error) {
+	switch n {
+	case MainNet:
+		return &chaincfg.MainNet, nil
+	case TestNet, RegTest:
+		return &chaincfg.TestNet, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", goclienterr.ErrUnsupportedNetwork, string(n))
+	}
+}
+
+// Option customizes the key generation functions.
+type Option func(*options)
+
+type options struct {
+	passphrase string
+	network    Network
+	entropy    int
+}
+
+// WithPassphrase sets the BIP39 passphrase, also known as the 25th word, mixed with the mnemonic phrase
+// into the seed of the xPriv. The same mnemonic phrase with a different passphrase gives a different xPriv.
+// By default the passphrase is empty.
+func WithPassphrase(passphrase string) Option {
+	return func(o *options) {
+		o.passphrase = passphrase
+	}
+}
+
+// WithNetwork sets the network the keys are generated for. By default the keys are generated for MainNet.
+func WithNetwork(network Network) Option {
+	return func(o *options) {
+		o.network = network
+	}
+}
+
+// WithEntropy sets the entropy (bit size) of new mnemonic phrases, which must be a multiple of 32
+// within the inclusive range of {128, 256}: 128 bits give 12 words and 256 bits give 24 words.
+// By default DefaultEntropy is used.
+func WithEntropy(bits int) Option {
+	return func(o *options) {
+		o.entropy = bits
+	}
+}
+
+func newOptions(opts []Option) (*options, *chaincfg.Params, error) {
+	o := &options{network: MainNet, entropy: DefaultEntropy}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.entropy < 128 || o.entropy > 256 || o.entropy%32 != 0 {
+		return nil, nil, fmt.Errorf("%w: got %d", goclienterr.ErrInvalidEntropy, o.entropy)
+	}
+
+	params, err := o.network.params()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return o, params, nil
+}
@@ -1,11 +1,14 @@
 package walletkeys
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	bip32 "github.com/bitcoin-sv/go-sdk/compat/bip32"
 	bip39 "github.com/bitcoin-sv/go-sdk/compat/bip39"
-	chaincfg "github.com/bitcoin-sv/go-sdk/transaction/chaincfg"
+	ec "github.com/bitcoin-sv/go-sdk/primitives/ec"
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
 )
 
 // DefaultEntropy defines the default entropy (bit size) used for cryptographic purposes.
@@ -64,14 +67,21 @@ func XPubFromXPriv(s string) (string, error) {
 }
 
 // XPrivFromMnemonic generates an extended private key (xPriv) from a mnemonic phrase.
+// By default the seed is generated with an empty BIP39 passphrase and the key is serialized for MainNet;
+// use WithPassphrase and WithNetwork to change it. The mnemonic phrase is validated with ValidateMnemonic.
 // It returns the extended private key and an error if seed generation or HD key creation fails.
-func XPrivFromMnemonic(mnemonic string) (*bip32.ExtendedKey, error) {
-	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+func XPrivFromMnemonic(mnemonic string, opts ...Option) (*bip32.ExtendedKey, error) {
+	o, params, err := newOptions(opts)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateMnemonic(mnemonic); err != nil {
 		return nil, fmt.Errorf("failed to generate seed from mnemonic: %w", err)
 	}
 
-	xPriv, err := bip32.NewMaster(seed, &chaincfg.MainNet)
+	seed := bip39.NewSeed(normalizeMnemonic(mnemonic), o.passphrase)
+	xPriv, err := bip32.NewMaster(seed, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create master node HD key: %w", err)
 	}
@@ -79,16 +89,43 @@ func XPrivFromMnemonic(mnemonic string) (*bip32.ExtendedKey, error) {
 	return xPriv, nil
 }
 
-// RandomXPriv generates a random extended private key (xPriv).
+// ValidateMnemonic checks that the mnemonic phrase has 12, 15, 18, 21 or 24 words of the BIP39 English word list
+// and that its checksum is correct. It returns an error wrapping ErrInvalidMnemonic, naming the first unknown word,
+// or ErrMnemonicChecksum, which usually means that a word is misspelled as another word of the list or misplaced.
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if n := len(words); n < 12 || n > 24 || n%3 != 0 {
+		return fmt.Errorf("%w: expected 12, 15, 18, 21 or 24 words, got %d", goclienterr.ErrInvalidMnemonic, n)
+	}
+
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(word); !ok {
+			return fmt.Errorf("%w: unknown word %q at position %d", goclienterr.ErrInvalidMnemonic, word, i+1)
+		}
+	}
+
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return goclienterr.ErrMnemonicChecksum
+	}
+
+	return nil
+}
+
+// RandomXPriv generates a random extended private key (xPriv), serialized for MainNet unless set otherwise with WithNetwork.
 // The seed size is specified as 32 bytes (256 bits), as defined by the bip32.RecommendedSeedLen constant.
 // It returns a pointer to the extended private key and an error if seed generation or the creation of the master node HD key fails.
-func RandomXPriv() (*bip32.ExtendedKey, error) {
+func RandomXPriv(opts ...Option) (*bip32.ExtendedKey, error) {
+	_, params, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	seed, err := bip32.GenerateSeed(bip32.RecommendedSeedLen)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate seed: %w", err)
 	}
 
-	xPriv, err := bip32.NewMaster(seed, &chaincfg.MainNet)
+	xPriv, err := bip32.NewMaster(seed, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate master node HD key: %w", err)
 	}
@@ -96,10 +133,16 @@ func RandomXPriv() (*bip32.ExtendedKey, error) {
 	return xPriv, nil
 }
 
-// RandomMnemonic generates a mnemonic phrase consisting of words derived from default entropy.
+// RandomMnemonic generates a mnemonic phrase consisting of words derived from default entropy,
+// or from the entropy set with WithEntropy.
 // It returns the mnemonic as a string and an error if entropy generation or mnemonic creation fails.
-func RandomMnemonic() (string, error) {
-	entropy, err := bip39.NewEntropy(DefaultEntropy)
+func RandomMnemonic(opts ...Option) (string, error) {
+	o, _, err := newOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
+	entropy, err := bip39.NewEntropy(o.entropy)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate entropy: %w", err)
 	}
@@ -112,10 +155,10 @@ func RandomMnemonic() (string, error) {
 	return mnemonic, nil
 }
 
-// RandomKeys generates random HD keys (xPriv and xPub).
+// RandomKeys generates random HD keys (xPriv and xPub), serialized for MainNet unless set otherwise with WithNetwork.
 // It returns a Keys struct containing the extended private and public keys and an error if any generation fails.
-func RandomKeys() (*Keys, error) {
-	xPriv, err := RandomXPriv()
+func RandomKeys(opts ...Option) (*Keys, error) {
+	xPriv, err := RandomXPriv(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate random xPriv: %w", err)
 	}
@@ -129,14 +172,15 @@ func RandomKeys() (*Keys, error) {
 }
 
 // RandomKeysWithMnemonic generates random HD keys (xPriv and xPub) along with a mnemonic phrase.
+// The options set the entropy of the mnemonic phrase, the BIP39 passphrase and the network, as for RandomMnemonic and XPrivFromMnemonic.
 // It returns a KeysWithMnemonic struct containing the keys and the associated mnemonic, and an error if any generation fails.
-func RandomKeysWithMnemonic() (*KeysWithMnemonic, error) {
-	mnemonic, err := RandomMnemonic()
+func RandomKeysWithMnemonic(opts ...Option) (*KeysWithMnemonic, error) {
+	mnemonic, err := RandomMnemonic(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate random mnemonic: %w", err)
 	}
 
-	xPriv, err := bip32.GenerateHDKeyFromMnemonic(mnemonic, "", &chaincfg.MainNet)
+	xPriv, err := XPrivFromMnemonic(mnemonic, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate HD key from mnemonic: %w", err)
 	}
@@ -149,3 +193,48 @@ func RandomKeysWithMnemonic() (*KeysWithMnemonic, error) {
 	keys := Keys{xPriv: xPriv.String(), xPub: xPub}
 	return &KeysWithMnemonic{mnemonic: mnemonic, Keys: keys}, nil
 }
+
+// DeriveChild derives the child of an extended private or public key at the BIP32 path, e.g. "0/1" or "44'/236'/0'".
+// Hardened children, marked with "'", can only be derived from an extended private key.
+// It returns the derived extended key and an error if the key or the path is invalid.
+func DeriveChild(key, path string) (*bip32.ExtendedKey, error) {
+	parent, err := bip32.NewKeyFromString(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate HD key from string: %w", err)
+	}
+
+	child, err := parent.DeriveChildFromPath(strings.TrimPrefix(strings.TrimPrefix(path, "m"), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive child key at path %q: %w", path, err)
+	}
+
+	return child, nil
+}
+
+// AccessKeyToWIF converts the hex encoded private key of an access key, as returned by the SPV Wallet,
+// to the Wallet Import Format. It returns an empty string and an error if the access key is invalid.
+func AccessKeyToWIF(accessKey string) (string, error) {
+	b, err := hex.DecodeString(accessKey)
+	if err != nil || len(b) != ec.PrivateKeyBytesLen {
+		return "", fmt.Errorf("%w: expected %d bytes hex string", goclienterr.ErrInvalidAccessKey, ec.PrivateKeyBytesLen)
+	}
+
+	priv, _ := ec.PrivateKeyFromBytes(b)
+	return priv.Wif(), nil
+}
+
+// AccessKeyFromWIF converts a private key in the Wallet Import Format to the hex encoded access key
+// accepted by the SPV Wallet. It returns an empty string and an error if the WIF is invalid.
+func AccessKeyFromWIF(wif string) (string, error) {
+	priv, err := ec.PrivateKeyFromWif(wif)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", goclienterr.ErrInvalidAccessKey, err)
+	}
+
+	return hex.EncodeToString(priv.Serialize()), nil
+}
+
+// normalizeMnemonic separates the words of the mnemonic phrase with single spaces, as expected by the BIP39 seed derivation.
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(mnemonic), " ")
+}
@@ -43,3 +43,48 @@ func ExampleXPrivFromMnemonic() {
 	// Output:
 	// xPriv: xprv9s21ZrQH143K3Lh4wdicqvYNMcdh49rMLqDvQoyys8L6f5tfE2WkQN7ZVE2awBrfVWNSJ8pPd4QLLr94Nur85Dvj8kD8RoZghBuNTpvL8si
 }
+
+func ExampleDeriveChild() {
+	key := "xprv9s21ZrQH143K3Lh4wdicqvYNMcdh49rMLqDvQoyys8L6f5tfE2WkQN7ZVE2awBrfVWNSJ8pPd4QLLr94Nur85Dvj8kD8RoZghBuNTpvL8si"
+	child, err := walletkeys.DeriveChild(key, "0/1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("xPriv:", child)
+
+	// Output:
+	// xPriv: xprv9x2QT5LAcoHU27SVEUK9pFbhKSHTwhN9qFD2pciEsGHW9oKMrSSHeHTrXaveS7Dw4mMRrtWVQLncziuwruDRVJhbNubvjfoyXYcobGZvTBU
+}
+
+func ExampleAccessKeyToWIF() {
+	wif, err := walletkeys.AccessKeyToWIF("3fd870d6bf1725f04084cf31209c04be5bd9bed001a390ad3bc632a55a3ee078")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	accessKey, err := walletkeys.AccessKeyFromWIF(wif)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("WIF:", wif)
+	fmt.Println("Access key:", accessKey)
+
+	// Output:
+	// WIF: KyMpQThiY3ie3PToyU7F39GJHacYvkqt8gusekm7d6qCVKwpb4je
+	// Access key: 3fd870d6bf1725f04084cf31209c04be5bd9bed001a390ad3bc632a55a3ee078
+}
+
+func ExampleXPrivFromMnemonic_withOptions() {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	xPriv, err := walletkeys.XPrivFromMnemonic(mnemonic, walletkeys.WithPassphrase("TREZOR"), walletkeys.WithNetwork(walletkeys.TestNet))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("xPriv:", xPriv)
+
+	// Output:
+	// xPriv: tprv8ZgxMBicQKsPeWHBt7a68nPnvgTnuDhUgDWC8wZCgA8GahrQ3f3uWpq7wE7Uc1dLBnCe1hhCZ886K6ND37memRDWqsA9HgSKDXtwh2Qxo6J
+}
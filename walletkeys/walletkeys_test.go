@@ -0,0 +1,216 @@
+package walletkeys_test
+
+import (
+	"strings"
+	"testing"
+
+	goclienterr "github.com/bitcoin-sv/spv-wallet-go-client/errors"
+	"github.com/bitcoin-sv/spv-wallet-go-client/walletkeys"
+	"github.com/stretchr/testify/require"
+)
+
+// trezorMnemonic is the mnemonic phrase of the first BIP39 test vector, which uses the "TREZOR" passphrase.
+const trezorMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestXPrivFromMnemonic(t *testing.T) {
+	tests := map[string]struct {
+		mnemonic      string
+		opts          []walletkeys.Option
+		expectedXPriv string
+	}{
+		"Default options": {
+			mnemonic:      keystoreMnemonic,
+			expectedXPriv: keystoreXPriv,
+		},
+		"BIP39 test vector with passphrase": {
+			mnemonic:      trezorMnemonic,
+			opts:          []walletkeys.Option{walletkeys.WithPassphrase("TREZOR")},
+			expectedXPriv: "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF",
+		},
+		"Extra whitespace between words": {
+			mnemonic:      " " + strings.ReplaceAll(keystoreMnemonic, " ", "  ") + "\n",
+			expectedXPriv: keystoreXPriv,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			xPriv, err := walletkeys.XPrivFromMnemonic(tc.mnemonic, tc.opts...)
+
+			// then:
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedXPriv, xPriv.String())
+		})
+	}
+
+	t.Run("Test networks", func(t *testing.T) {
+		for _, network := range []walletkeys.Network{walletkeys.TestNet, walletkeys.RegTest} {
+			// when:
+			xPriv, err := walletkeys.XPrivFromMnemonic(keystoreMnemonic, walletkeys.WithNetwork(network))
+
+			// then:
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(xPriv.String(), "tprv"), xPriv.String())
+			xPub, err := walletkeys.XPubFromXPriv(xPriv.String())
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(xPub, "tpub"), xPub)
+		}
+	})
+
+	t.Run("Passphrase changes the xPriv", func(t *testing.T) {
+		// when:
+		xPriv, err := walletkeys.XPrivFromMnemonic(keystoreMnemonic, walletkeys.WithPassphrase("secret"))
+
+		// then:
+		require.NoError(t, err)
+		require.NotEqual(t, keystoreXPriv, xPriv.String())
+	})
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := map[string]struct {
+		mnemonic    string
+		expectedErr error
+		expectedMsg string
+	}{
+		"Valid mnemonic": {
+			mnemonic: keystoreMnemonic,
+		},
+		"Too few words": {
+			mnemonic:    "abandon abandon about",
+			expectedErr: goclienterr.ErrInvalidMnemonic,
+			expectedMsg: "got 3",
+		},
+		"Unknown word": {
+			mnemonic:    strings.Replace(trezorMnemonic, "about", "abuot", 1),
+			expectedErr: goclienterr.ErrInvalidMnemonic,
+			expectedMsg: `unknown word "abuot" at position 12`,
+		},
+		"Incorrect checksum": {
+			mnemonic:    strings.Replace(trezorMnemonic, "about", "abandon", 1),
+			expectedErr: goclienterr.ErrMnemonicChecksum,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			err := walletkeys.ValidateMnemonic(tc.mnemonic)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				require.ErrorContains(t, err, tc.expectedMsg)
+			}
+		})
+	}
+
+	t.Run("Checksum error from XPrivFromMnemonic", func(t *testing.T) {
+		// when:
+		_, err := walletkeys.XPrivFromMnemonic(strings.Replace(trezorMnemonic, "about", "abandon", 1))
+
+		// then:
+		require.ErrorIs(t, err, goclienterr.ErrMnemonicChecksum)
+	})
+}
+
+func TestRandomKeysWithMnemonic(t *testing.T) {
+	tests := map[string]struct {
+		opts          []walletkeys.Option
+		expectedWords int
+		expectedXPriv string
+	}{
+		"Default options": {
+			expectedWords: 12,
+			expectedXPriv: "xprv",
+		},
+		"256 bits entropy on testnet": {
+			opts:          []walletkeys.Option{walletkeys.WithEntropy(256), walletkeys.WithNetwork(walletkeys.TestNet)},
+			expectedWords: 24,
+			expectedXPriv: "tprv",
+		},
+		"160 bits entropy with passphrase": {
+			opts:          []walletkeys.Option{walletkeys.WithEntropy(160), walletkeys.WithPassphrase("secret")},
+			expectedWords: 15,
+			expectedXPriv: "xprv",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			keys, err := walletkeys.RandomKeysWithMnemonic(tc.opts...)
+
+			// then:
+			require.NoError(t, err)
+			require.Len(t, strings.Fields(keys.Mnemonic()), tc.expectedWords)
+			require.True(t, strings.HasPrefix(keys.XPriv(), tc.expectedXPriv), keys.XPriv())
+			xPriv, err := walletkeys.XPrivFromMnemonic(keys.Mnemonic(), tc.opts...)
+			require.NoError(t, err)
+			require.Equal(t, xPriv.String(), keys.XPriv())
+		})
+	}
+}
+
+func TestOptions_Errors(t *testing.T) {
+	tests := map[string]struct {
+		opts        []walletkeys.Option
+		expectedErr error
+	}{
+		"Entropy below 128 bits": {
+			opts:        []walletkeys.Option{walletkeys.WithEntropy(96)},
+			expectedErr: goclienterr.ErrInvalidEntropy,
+		},
+		"Entropy not a multiple of 32": {
+			opts:        []walletkeys.Option{walletkeys.WithEntropy(200)},
+			expectedErr: goclienterr.ErrInvalidEntropy,
+		},
+		"Unknown network": {
+			opts:        []walletkeys.Option{walletkeys.WithNetwork("stn")},
+			expectedErr: goclienterr.ErrUnsupportedNetwork,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			_, err := walletkeys.RandomKeysWithMnemonic(tc.opts...)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestParseNetwork(t *testing.T) {
+	tests := map[string]struct {
+		name            string
+		expectedNetwork walletkeys.Network
+		expectedErr     error
+	}{
+		"Main network": {
+			name:            "mainnet",
+			expectedNetwork: walletkeys.MainNet,
+		},
+		"Case insensitive": {
+			name:            "RegTest",
+			expectedNetwork: walletkeys.RegTest,
+		},
+		"Unknown network": {
+			name:        "stn",
+			expectedErr: goclienterr.ErrUnsupportedNetwork,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when:
+			network, err := walletkeys.ParseNetwork(tc.name)
+
+			// then:
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedNetwork, network)
+		})
+	}
+}